@@ -0,0 +1,61 @@
+// reportescalation แจ้งเตือนรายงานความคืบหน้าที่เลยกำหนดหนึ่งรอบ (server รันเองทุก REPORT_ESCALATION_INTERVAL อยู่แล้ว)
+//
+//	go run ./cmd/reportescalation -dry-run      # ดูว่าจะแจ้งใครบ้าง ไม่เขียนฐานข้อมูล
+//	go run ./cmd/reportescalation -advisor-after 72h -admin-after 168h -json
+//
+// ใช้ค่า DB_* ชุดเดียวกับ server
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"backend/config"
+	"backend/internal/service/reportescalation"
+)
+
+func main() {
+	th := reportescalation.ThresholdsFromEnv()
+	dryRun := flag.Bool("dry-run", false, "report escalations without writing them")
+	advisorAfter := flag.Duration("advisor-after", th.AdvisorAfter, "notify the advisor this long after the due date")
+	adminAfter := flag.Duration("admin-after", th.AdminAfter, "notify the department admin this long after the due date")
+	asJSON := flag.Bool("json", false, "print the full result as JSON")
+	flag.Parse()
+
+	config.ConnectDB()
+
+	svc := reportescalation.New(config.DB())
+	res, err := svc.Run(context.Background(), reportescalation.Options{
+		Thresholds: reportescalation.Thresholds{AdvisorAfter: *advisorAfter, AdminAfter: *adminAfter},
+		DryRun:     *dryRun,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "reportescalation:", err)
+		os.Exit(1)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(res)
+	} else {
+		mode := "APPLY"
+		if res.DryRun {
+			mode = "DRY-RUN"
+		}
+		fmt.Printf("[%s] overdue=%d escalated=%d errors=%d\n", mode, res.Checked, len(res.Escalations), len(res.Errors))
+		for _, e := range res.Escalations {
+			fmt.Printf("  log=%d due=%s step=%s recipients=%v\n", e.AdvisorLogID, e.DueAt.Format("2006-01-02 15:04"), e.Step, e.Recipients)
+		}
+		for _, e := range res.Errors {
+			fmt.Printf("  error    %s\n", e)
+		}
+	}
+
+	if len(res.Errors) > 0 {
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,76 @@
+// storagegc ตรวจความถูกต้องของที่เก็บไฟล์อัปโหลดและเก็บกวาดไฟล์ที่ไม่ถูกใช้
+//
+//	go run ./cmd/storagegc                      # dry-run: รายงานอย่างเดียว
+//	go run ./cmd/storagegc -apply               # ลบ orphan และ purge ไฟล์ที่เกิน retention
+//	go run ./cmd/storagegc -grace 48h -retention 8760h -json
+//
+// ใช้ค่า DB_* และ STORAGE_DRIVER/UPLOAD_DIR/S3_* ชุดเดียวกับ server
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"backend/config"
+	"backend/internal/service/storagegc"
+)
+
+func main() {
+	apply := flag.Bool("apply", false, "delete orphans and purge expired files (default: dry-run)")
+	grace := flag.Duration("grace", storagegc.DefaultGrace, "ignore unreferenced files newer than this")
+	retention := flag.Duration("retention", storagegc.DefaultRetention, "purge files of records deleted longer ago than this")
+	asJSON := flag.Bool("json", false, "print the full report as JSON")
+	flag.Parse()
+
+	config.ConnectDB()
+	config.SetupStorage()
+
+	svc := storagegc.New(config.DB(), config.Storage())
+	rep, err := svc.Run(context.Background(), storagegc.Options{
+		Grace:     *grace,
+		Retention: *retention,
+		Apply:     *apply,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "storagegc:", err)
+		os.Exit(1)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(rep)
+	} else {
+		printSummary(rep)
+	}
+
+	// missing หรือ error = ให้ exit code ไม่เป็น 0 เพื่อให้ cron/CI แจ้งเตือนได้
+	if len(rep.Missing) > 0 || len(rep.Errors) > 0 {
+		os.Exit(2)
+	}
+}
+
+func printSummary(rep *storagegc.Report) {
+	mode := "APPLY"
+	if rep.DryRun {
+		mode = "DRY-RUN"
+	}
+	fmt.Printf("[%s] objects=%d referenced=%d missing=%d orphans=%d purge=%d reclaim=%d bytes\n",
+		mode, rep.Objects, rep.Referenced, len(rep.Missing), len(rep.Orphans), len(rep.Purged), rep.ReclaimBytes)
+
+	for _, m := range rep.Missing {
+		fmt.Printf("  missing  asset=%d %s/%d key=%s\n", m.AssetID, m.OwnerType, m.OwnerID, m.Key)
+	}
+	for _, o := range rep.Orphans {
+		fmt.Printf("  orphan   key=%s size=%d modified=%s deleted=%v\n", o.Key, o.Size, o.ModTime.Format("2006-01-02 15:04"), o.Deleted)
+	}
+	for _, p := range rep.Purged {
+		fmt.Printf("  purge    asset=%d %s/%d key=%s reason=%s deleted=%v\n", p.AssetID, p.OwnerType, p.OwnerID, p.Key, p.Reason, p.Deleted)
+	}
+	for _, e := range rep.Errors {
+		fmt.Printf("  error    %s\n", e)
+	}
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+    "backend/config/migration"
     "backend/config/seed"
     "backend/internal/app/entity"
     "fmt"
@@ -49,6 +50,11 @@ func SetupDatabase() {
     // 1. ปิดการตรวจสอบ Foreign Key ชั่วคราว 
     //    PostgreSQL จะสร้างตารางได้ทั้งหมด แม้จะมีปัญหาลำดับการอ้างอิงกัน
     db.Exec("SET session_replication_role = 'replica';")
+
+    // ตาราง file_assets แบบเดิม (f_storage_path + primary key บน original_name) ต้องปรับก่อน AutoMigrate
+    migration.MigrateLegacyFileAssetsTable(db)
+    // unique index ปี/เทอมของ semesters เดิมไม่ยกเว้นแถวที่ลบแล้ว: ลบทิ้งให้ AutoMigrate สร้างแบบ partial
+    migration.MigrateSemesterYearTermIndex(db)
     
     // 2. Migrate ตารางทั้งหมดในครั้งเดียว
     //    เนื่องจากปิด Foreign Key แล้ว จึงไม่มีปัญหาลำดับการเรียก
@@ -63,8 +69,14 @@ func SetupDatabase() {
         &entity.StudentAcademicRecord{},
         &entity.Appointment{},
         &entity.AdvisorLog{},
+        &entity.AdvisorLogRevision{},
+        &entity.AdvisorLogRevisionFile{},
+        &entity.LogTemplate{},
+        &entity.LogTemplateField{},
+        &entity.LogTag{},
         &entity.ProgressReport{},
         &entity.ReportFeedback{},
+        &entity.ReportEscalation{},
         &entity.AppointmentReminder{},
         &entity.AppointmentState{},
         &entity.AppointmentStatus{},
@@ -77,6 +89,10 @@ func SetupDatabase() {
         &entity.ReportImage{}, // รวม ReportImage เข้ามาใน Batch นี้ได้เลย
         &entity.ReportStatus{},
         &entity.ReportTopic{},
+        &entity.CalendarFeedToken{},
+        &entity.Semester{},
+        &entity.FileAssets{},
+        &entity.AppointmentAttachment{},
     ); err != nil {
         log.Fatalf("failed to migrate schema: %v", err)
     }
@@ -84,6 +100,12 @@ func SetupDatabase() {
     // 3. เปิดการตรวจสอบ Foreign Key กลับมา
     db.Exec("SET session_replication_role = 'origin';")
 
+    // ย้ายไฟล์แนบแบบ comma-separated เดิมไปตาราง file_assets (ครั้งเดียว)
+    migration.MigrateLegacyAttachments(db)
+
+    // ดัชนี trigram ของการค้นหารวม (/api/search)
+    migration.EnsureSearchIndexes(db)
+
     // 4. ส่วน Seed ข้อมูล (รันหลังจากตารางทั้งหมดถูกสร้าง)
     seed.SeedPrefix(db)
     seed.SeedMajor(db)
@@ -98,6 +120,8 @@ func SetupDatabase() {
     seed.SeedAppointments(db)
     seed.SeedAppointmentCategories(db)
     seed.SeedAppointmentTopics(db)
+    seed.SeedLogTags(db)
+    seed.SeedLogTemplates(db)
     seed.SeedReportStatus(db)
     seed.SeedReportTopic(db)
     seed.SeedReport(db)
@@ -0,0 +1,312 @@
+package migration
+
+import (
+	"fmt"
+	"mime"
+	"path/filepath"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"backend/internal/app/entity"
+	"backend/internal/service/fileasset"
+	"backend/internal/service/storage"
+)
+
+// ย้ายไฟล์แนบแบบเก่า (ชื่อ/พาธคั่นด้วย comma ในคอลัมน์ file_name, file_path)
+// ไปเป็น 1 แถวต่อไฟล์ในตาราง file_assets แล้วลบคอลัมน์เก่าทิ้ง
+// รันทุกครั้งที่ start ได้: ถ้าคอลัมน์เก่าไม่มีแล้วจะไม่ทำอะไร
+
+func splitCsv(s string) []string {
+	var out []string
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func mimeFromName(name string) string {
+	if mt := mime.TypeByExtension(strings.ToLower(filepath.Ext(name))); mt != "" {
+		if base, _, err := mime.ParseMediaType(mt); err == nil {
+			return base
+		}
+	}
+	return "application/octet-stream"
+}
+
+// uploadRoot โฟลเดอร์อัปโหลดของ local storage (ไฟล์เก่าอยู่ในนี้ทั้งหมด)
+func uploadRoot() string {
+	return filepath.Clean(storage.ConfigFromEnv().LocalRoot)
+}
+
+// storageKey แปลงพาธบนดิสก์แบบเดิม (เช่น uploads/abc.pdf) เป็น key ของ storage (abc.pdf)
+func storageKey(path string) string {
+	rel, err := filepath.Rel(uploadRoot(), filepath.Clean(path))
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return filepath.Base(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// legacyAsset สร้างแถว FileAssets จากพาธเดิม (คำนวณขนาด/SHA-256 ถ้าไฟล์ยังอยู่)
+func legacyAsset(name, path, mimeType string, uploaderID *uint, ownerType string, ownerID uint, position int) entity.FileAssets {
+	if name == "" {
+		name = filepath.Base(path)
+	}
+	if mimeType == "" {
+		mimeType = mimeFromName(name)
+	}
+
+	asset := entity.FileAssets{
+		OriginalName: name,
+		MimeType:     mimeType,
+		StoragePath:  storageKey(path),
+		UploaderID:   uploaderID,
+		OwnerType:    ownerType,
+		OwnerID:      ownerID,
+		Position:     position,
+	}
+	if size, sum, err := fileasset.HashFile(path); err == nil {
+		asset.Size, asset.SHA256 = size, sum
+	}
+	return asset
+}
+
+// MigrateLegacyFileAssetsTable ปรับตาราง file_assets แบบเดิม (primary key คู่ id + original_name ที่เป็นเลขรัน,
+// พาธอยู่ในคอลัมน์ f_storage_path) ให้ตรงกับ entity ปัจจุบัน
+// ต้องรันก่อน AutoMigrate เพราะ storage_path เป็น NOT NULL จะเพิ่มคอลัมน์ให้ตารางที่มีข้อมูลอยู่แล้วไม่ได้
+// รันทุกครั้งที่ start ได้: ถ้าไม่มีคอลัมน์ f_storage_path แล้วจะไม่ทำอะไร
+func MigrateLegacyFileAssetsTable(db *gorm.DB) {
+	m := db.Migrator()
+	if !m.HasTable(&entity.FileAssets{}) || !m.HasColumn(&entity.FileAssets{}, "f_storage_path") {
+		return
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// primary key เดิมครอบ original_name ด้วย: ลบทิ้งแล้วสร้างใหม่บน id อย่างเดียว
+		var pkeys []string
+		if err := tx.Raw(`SELECT conname FROM pg_constraint
+			WHERE conrelid = 'file_assets'::regclass AND contype = 'p'`).Scan(&pkeys).Error; err != nil {
+			return err
+		}
+		for _, name := range pkeys {
+			if err := tx.Exec(fmt.Sprintf(`ALTER TABLE file_assets DROP CONSTRAINT %q`, name)).Error; err != nil {
+				return err
+			}
+		}
+
+		// original_name เดิมเป็นเลขรัน ไม่ใช่ชื่อไฟล์: ใช้ชื่อจากพาธแทน
+		for _, sql := range []string{
+			`ALTER TABLE file_assets ALTER COLUMN original_name DROP DEFAULT`,
+			`DROP SEQUENCE IF EXISTS file_assets_original_name_seq`,
+			`ALTER TABLE file_assets ALTER COLUMN original_name TYPE varchar(255) USING original_name::text`,
+			`UPDATE file_assets SET original_name = regexp_replace(f_storage_path, '^.*/', '')
+				WHERE COALESCE(f_storage_path, '') <> ''`,
+		} {
+			if err := tx.Exec(sql).Error; err != nil {
+				return err
+			}
+		}
+
+		tm := tx.Migrator()
+		if tm.HasColumn(&entity.FileAssets{}, "storage_path") {
+			if err := tx.Exec(`UPDATE file_assets SET storage_path = f_storage_path
+				WHERE COALESCE(storage_path, '') = ''`).Error; err != nil {
+				return err
+			}
+			if err := tm.DropColumn(&entity.FileAssets{}, "f_storage_path"); err != nil {
+				return err
+			}
+		} else if err := tm.RenameColumn(&entity.FileAssets{}, "f_storage_path", "storage_path"); err != nil {
+			return err
+		}
+		if err := tx.Exec(`UPDATE file_assets SET storage_path = '' WHERE storage_path IS NULL`).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(`ALTER TABLE file_assets ADD PRIMARY KEY (id)`).Error
+	})
+	if err != nil {
+		fmt.Printf("Failed to migrate legacy file_assets table: %v\n", err)
+		return
+	}
+	fmt.Println("Migrated legacy file_assets table (dropped f_storage_path and the original_name primary key)")
+}
+
+func MigrateLegacyAttachments(db *gorm.DB) {
+	steps := []struct {
+		name string
+		fn   func(*gorm.DB) (int, error)
+	}{
+		{"advisor_logs", migrateAdvisorLogFiles},
+		{"progress_reports", migrateProgressReportFiles},
+		{"report_images", migrateReportImages},
+		{"file_assets", migrateStorageKeys},
+	}
+
+	for _, step := range steps {
+		n, err := step.fn(db)
+		if err != nil {
+			fmt.Printf("Failed to migrate %s attachments: %v\n", step.name, err)
+			continue
+		}
+		if n > 0 {
+			fmt.Printf("Migrated %d %s attachment(s) to file_assets\n", n, step.name)
+		}
+	}
+}
+
+// advisor_logs.file_name / file_path: หลายไฟล์คั่นด้วย comma
+func migrateAdvisorLogFiles(db *gorm.DB) (int, error) {
+	m := db.Migrator()
+	if !m.HasColumn(&entity.AdvisorLog{}, "file_path") {
+		return 0, nil
+	}
+
+	type row struct {
+		ID       uint
+		FileName string
+		FilePath string
+	}
+
+	count := 0
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var rows []row
+		if err := tx.Raw(`SELECT id, COALESCE(file_name, '') AS file_name, COALESCE(file_path, '') AS file_path
+			FROM advisor_logs WHERE COALESCE(file_path, '') <> ''`).Scan(&rows).Error; err != nil {
+			return err
+		}
+
+		for _, r := range rows {
+			paths := splitCsv(r.FilePath)
+			names := splitCsv(r.FileName)
+			// ชื่อไฟล์ที่มี comma ทำให้จำนวนไม่ตรงกัน: ใช้ชื่อจากพาธแทน
+			if len(names) != len(paths) {
+				names = nil
+			}
+
+			for i, p := range paths {
+				name := ""
+				if names != nil {
+					name = names[i]
+				}
+				asset := legacyAsset(name, p, "", nil, entity.FileOwnerAdvisorLog, r.ID, i)
+				if err := tx.Create(&asset).Error; err != nil {
+					return err
+				}
+				count++
+			}
+		}
+
+		if err := tx.Migrator().DropColumn(&entity.AdvisorLog{}, "file_name"); err != nil {
+			return err
+		}
+		return tx.Migrator().DropColumn(&entity.AdvisorLog{}, "file_path")
+	})
+	return count, err
+}
+
+// progress_reports.file_name / file_path: ไฟล์เดียว ผู้อัปโหลดคือนักศึกษาของนัดหมาย
+func migrateProgressReportFiles(db *gorm.DB) (int, error) {
+	m := db.Migrator()
+	if !m.HasColumn(&entity.ProgressReport{}, "file_path") {
+		return 0, nil
+	}
+
+	type row struct {
+		ID            uint
+		FileName      string
+		FilePath      string
+		StudentUserID *uint
+	}
+
+	count := 0
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var rows []row
+		if err := tx.Raw(`SELECT pr.id, COALESCE(pr.file_name, '') AS file_name, COALESCE(pr.file_path, '') AS file_path,
+				a.student_user_id
+			FROM progress_reports pr
+			LEFT JOIN advisor_logs l ON l.id = pr.advisor_logs_id
+			LEFT JOIN appointments a ON a.id = l.appointment_id
+			WHERE COALESCE(pr.file_path, '') <> ''`).Scan(&rows).Error; err != nil {
+			return err
+		}
+
+		for _, r := range rows {
+			asset := legacyAsset(strings.TrimSpace(r.FileName), strings.TrimSpace(r.FilePath), "",
+				r.StudentUserID, entity.FileOwnerProgressReport, r.ID, 0)
+			if err := tx.Create(&asset).Error; err != nil {
+				return err
+			}
+			count++
+		}
+
+		if err := tx.Migrator().DropColumn(&entity.ProgressReport{}, "file_name"); err != nil {
+			return err
+		}
+		return tx.Migrator().DropColumn(&entity.ProgressReport{}, "file_path")
+	})
+	return count, err
+}
+
+// report_images.file_url / file_type -> report_images.file_assets_id
+func migrateReportImages(db *gorm.DB) (int, error) {
+	m := db.Migrator()
+	if !m.HasColumn(&entity.ReportImage{}, "file_url") {
+		return 0, nil
+	}
+
+	type row struct {
+		ID         uint
+		FileURL    string
+		FileType   string
+		ReportID   uint
+		ReportByID *uint
+	}
+
+	count := 0
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var rows []row
+		if err := tx.Raw(`SELECT ri.id, COALESCE(ri.file_url, '') AS file_url, COALESCE(ri.file_type, '') AS file_type,
+				ri.report_id, r.report_by_id
+			FROM report_images ri
+			LEFT JOIN reports r ON r.id = ri.report_id
+			WHERE COALESCE(ri.file_assets_id, 0) = 0 AND COALESCE(ri.file_url, '') <> ''`).Scan(&rows).Error; err != nil {
+			return err
+		}
+
+		for _, r := range rows {
+			mimeType := r.FileType
+			if !strings.Contains(mimeType, "/") {
+				mimeType = ""
+			}
+			asset := legacyAsset("", r.FileURL, mimeType, r.ReportByID, entity.FileOwnerReport, r.ReportID, 0)
+			if err := tx.Create(&asset).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&entity.ReportImage{}).Where("id = ?", r.ID).
+				Update("file_assets_id", asset.ID).Error; err != nil {
+				return err
+			}
+			count++
+		}
+
+		if err := tx.Migrator().DropColumn(&entity.ReportImage{}, "file_url"); err != nil {
+			return err
+		}
+		return tx.Migrator().DropColumn(&entity.ReportImage{}, "file_type")
+	})
+	return count, err
+}
+
+// file_assets.storage_path: เดิมเก็บพาธบนดิสก์ (uploads/xxx) เปลี่ยนเป็น key ของ storage (xxx)
+// ถ้าใช้ S3 ต้องคัดลอกไฟล์จากโฟลเดอร์ uploads ขึ้น bucket ด้วย key เดียวกันเอง
+func migrateStorageKeys(db *gorm.DB) (int, error) {
+	prefix := filepath.ToSlash(uploadRoot()) + "/"
+	res := db.Unscoped().Model(&entity.FileAssets{}).
+		Where("storage_path LIKE ?", prefix+"%").
+		Update("storage_path", gorm.Expr("substr(storage_path, ?)", len(prefix)+1))
+	return int(res.RowsAffected), res.Error
+}
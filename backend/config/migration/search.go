@@ -0,0 +1,30 @@
+package migration
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+
+	"backend/internal/service/search"
+)
+
+// ดัชนี trigram สำหรับ /api/search (ภาษาไทยไม่มีช่องว่างระหว่างคำ full-text ของ Postgres จึงตัดคำไม่ได้)
+// นิพจน์มาจาก search.Sources ชุดเดียวกับตอนค้น planner จึงใช้ index กับ ILIKE '%...%' ได้
+// รันทุกครั้งที่ start ได้ (IF NOT EXISTS)
+
+func EnsureSearchIndexes(db *gorm.DB) {
+	if err := db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error; err != nil {
+		// ไม่มีสิทธิ์สร้าง extension: ให้ DBA สร้างเอง (/api/search ใช้ word_similarity ของ pg_trgm)
+		log.Printf("search: cannot enable pg_trgm: %v", err)
+		return
+	}
+	for _, src := range search.Sources {
+		name := fmt.Sprintf("idx_%s_search_trgm", src.Table)
+		sql := fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s USING gin (%s gin_trgm_ops)",
+			name, src.Table, src.Expr(false))
+		if err := db.Exec(sql).Error; err != nil {
+			log.Printf("search: create index %s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package migration
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// MigrateSemesterYearTermIndex ลบ unique index idx_semester_year_term แบบเดิม (ไม่มี WHERE deleted_at IS NULL)
+// ภาคที่ถูก soft delete จะกันไม่ให้สร้างปี/เทอมเดิมใหม่ AutoMigrate จะสร้าง index ใหม่แบบ partial ให้เอง
+// รันทุกครั้งที่ start ได้: ถ้า index เป็นแบบใหม่แล้วจะไม่ทำอะไร
+func MigrateSemesterYearTermIndex(db *gorm.DB) {
+	var defs []string
+	if err := db.Raw(`SELECT indexdef FROM pg_indexes
+		WHERE tablename = 'semesters' AND indexname = 'idx_semester_year_term'`).Scan(&defs).Error; err != nil {
+		fmt.Printf("Failed to inspect semesters index: %v\n", err)
+		return
+	}
+	if len(defs) == 0 || strings.Contains(strings.ToUpper(defs[0]), " WHERE ") {
+		return
+	}
+	if err := db.Exec(`DROP INDEX IF EXISTS idx_semester_year_term`).Error; err != nil {
+		fmt.Printf("Failed to drop semesters index: %v\n", err)
+		return
+	}
+	fmt.Println("Dropped idx_semester_year_term (recreated as a partial index on live semesters)")
+}
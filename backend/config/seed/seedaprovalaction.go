@@ -21,6 +21,13 @@ func SeedApprovalActions(db *gorm.DB) {
 			IsActive:     true,
 			DisplayOrder: 2,
 		},
+		{
+			Model:        gorm.Model{ID: 3},
+			ActionCode:   "FOLLOWUP",
+			ActionName:   "Schedule follow-up appointment",
+			IsActive:     true,
+			DisplayOrder: 3,
+		},
 	}
 
 	for _, action := range actions {
@@ -0,0 +1,67 @@
+package seed
+
+import (
+	"log"
+
+	"gorm.io/gorm"
+
+	"backend/internal/app/entity"
+)
+
+func SeedLogTags(db *gorm.DB) {
+	tags := []entity.LogTag{
+		{Name: "study-plan", Label: "แผนการเรียน", IsActive: true},
+		{Name: "registration", Label: "การลงทะเบียน", IsActive: true},
+		{Name: "low-gpa", Label: "ผลการเรียนต่ำ", IsActive: true},
+		{Name: "project", Label: "โปรเจกต์", IsActive: true},
+		{Name: "internship", Label: "ฝึกงาน / สหกิจ", IsActive: true},
+		{Name: "career", Label: "การทำงาน / อาชีพ", IsActive: true},
+		{Name: "financial", Label: "การเงิน / ทุนการศึกษา", IsActive: true},
+		{Name: "wellbeing", Label: "สุขภาพจิต / ความเป็นอยู่", IsActive: true},
+		{Name: "referral", Label: "ส่งต่อหน่วยงาน", IsActive: true},
+	}
+
+	for _, t := range tags {
+		var existing entity.LogTag
+		err := db.Unscoped().Where("name = ?", t.Name).First(&existing).Error
+		if err == gorm.ErrRecordNotFound {
+			if err := db.Create(&t).Error; err != nil {
+				log.Printf("❌ Seed LogTag failed: %v\n", err)
+			}
+		}
+	}
+}
+
+// SeedLogTemplates แบบฟอร์มตั้งต้นของหัวข้อ "ปรึกษาเรื่องการเรียน" (ถ้ายังไม่มีแบบฟอร์มของหัวข้อนี้)
+func SeedLogTemplates(db *gorm.DB) {
+	var topic entity.AppointmentTopic
+	if err := db.Where("topic = ?", "ปรึกษาเรื่องการเรียน").First(&topic).Error; err != nil {
+		return
+	}
+
+	var count int64
+	db.Unscoped().Model(&entity.LogTemplate{}).Where("topic_id = ?", topic.ID).Count(&count)
+	if count > 0 {
+		return
+	}
+
+	tmpl := entity.LogTemplate{
+		TopicID:  topic.ID,
+		Name:     "บันทึกการปรึกษาเรื่องการเรียน",
+		IsActive: true,
+		Fields: []entity.LogTemplateField{
+			{Key: "issues_discussed", Label: "ประเด็นที่ปรึกษา", Type: entity.TemplateFieldMultiSelect, Required: true, Position: 0,
+				Options: []string{"แผนการเรียน", "การลงทะเบียน", "ผลการเรียน", "การย้ายสาขา", "อื่น ๆ"}},
+			{Key: "details", Label: "รายละเอียด", Type: entity.TemplateFieldTextarea, Position: 1},
+			{Key: "actions_agreed", Label: "สิ่งที่ตกลงจะดำเนินการ", Type: entity.TemplateFieldTextarea, Required: true, Position: 2},
+			{Key: "referral", Label: "ส่งต่อหน่วยงาน", Type: entity.TemplateFieldSelect, Position: 3,
+				Options: []string{"ไม่ส่งต่อ", "ศูนย์บริการการศึกษา", "ศูนย์แนะแนว", "กองกิจการนักศึกษา"}},
+			{Key: "follow_up_needed", Label: "ต้องนัดติดตามผล", Type: entity.TemplateFieldCheckbox, Position: 4},
+		},
+	}
+	if err := db.Create(&tmpl).Error; err != nil {
+		log.Printf("❌ Seed LogTemplate failed: %v\n", err)
+		return
+	}
+	log.Printf("✅ Seeded LogTemplate: %s\n", tmpl.Name)
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+    "context"
+    "log"
+    "sync"
+
+    "backend/internal/service/storage"
+)
+
+var (
+    store     storage.Storage
+    storeOnce sync.Once
+)
+
+// SetupStorage เลือก storage ตาม STORAGE_DRIVER (local | s3) เรียกหลัง ConnectDB (โหลด .env แล้ว)
+func SetupStorage() {
+    storeOnce.Do(func() {
+        cfg := storage.ConfigFromEnv()
+        s, err := storage.New(context.Background(), cfg)
+        if err != nil {
+            log.Fatalf("failed to setup storage: %v", err)
+        }
+        store = s
+        log.Printf("Using %s storage for uploads", cfg.Driver)
+    })
+}
+
+// Storage ที่เก็บไฟล์อัปโหลดที่ทุก service ใช้ร่วมกัน
+func Storage() storage.Storage {
+    SetupStorage()
+    return store
+}
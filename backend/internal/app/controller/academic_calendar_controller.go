@@ -144,4 +144,31 @@ func (ctrl *AcademicCalendarController) DeleteEvent(c *gin.Context) {
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "Event and associated time deleted"})
-}
\ No newline at end of file
+}
+// GET /api/holidays/range?from=YYYY-MM-DD&to=YYYY-MM-DD (รวมวัน to และกระจาย event ซ้ำ)
+func (ctrl *AcademicCalendarController) GetEventsInRange(c *gin.Context) {
+	loc, err := time.LoadLocation("Asia/Bangkok")
+	if err != nil {
+		loc = time.Local
+	}
+
+	from, errFrom := time.ParseInLocation("2006-01-02", c.Query("from"), loc)
+	to, errTo := time.ParseInLocation("2006-01-02", c.Query("to"), loc)
+	if errFrom != nil || errTo != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from and to are required (YYYY-MM-DD)"})
+		return
+	}
+	// กันช่วงกว้างเกินไป (event ซ้ำรายวันจะกระจายเยอะมาก)
+	if to.Sub(from) > 366*24*time.Hour {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "range must not exceed 1 year"})
+		return
+	}
+
+	response, err := ctrl.Service.GetEventsInRange(from, to.AddDate(0, 0, 1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -6,6 +6,7 @@ import (
     "github.com/gin-gonic/gin"
     "net/http"
     "backend/internal/app/dto"
+    "backend/internal/app/repository"
 )
 
 type AdminProfileController struct {
@@ -49,7 +50,11 @@ func (c *AdminProfileController) GetManagedUsers(ctx *gin.Context) {
         Search:    ctx.Query("search"),
 		Major:    ctx.Query("major"),
     }
-    users, err := c.Service.GetAllUsers(filters) 
+    spec, ok := parseListQuery(ctx, repository.UserListQuery)
+    if !ok {
+        return
+    }
+    users, err := c.Service.GetAllUsers(filters, spec)
     if err != nil {
         ctx.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error: failed to fetch users"})
         return
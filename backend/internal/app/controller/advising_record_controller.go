@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"backend/internal/service/advisingrecord"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdvisingRecordController struct {
+	svc advisingrecord.Service
+}
+
+func NewAdvisingRecordController(svc advisingrecord.Service) *AdvisingRecordController {
+	return &AdvisingRecordController{svc: svc}
+}
+
+// GET /api/advisor/me/students/:sut_id/record.pdf
+func (ctrl *AdvisingRecordController) StudentRecordPDF(c *gin.Context) {
+	sutIdRaw, ok := c.Get("sut_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	advisorSutID, _ := sutIdRaw.(string)
+	studentSutID := c.Param("sut_id")
+
+	out, err := ctrl.svc.StudentRecordPDF(c.Request.Context(), advisorSutID, studentSutID)
+	if err != nil {
+		switch {
+		case errors.Is(err, advisingrecord.ErrStudentNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "ไม่พบนักศึกษา"})
+		case errors.Is(err, advisingrecord.ErrAdvisorNotFound):
+			c.JSON(http.StatusForbidden, gin.H{"error": "advisor only"})
+		case errors.Is(err, advisingrecord.ErrForbidden):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	fileName := fmt.Sprintf("advising_record_%s.pdf", studentSutID)
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="%s"`, fileName))
+	c.Data(http.StatusOK, "application/pdf", out)
+}
@@ -1,24 +1,34 @@
 package controller
 
 import (
+    "errors"
     "fmt"
     "net/http"
     "strconv"
     "strings"
 
     "backend/internal/app/dto"
+    "backend/internal/app/entity"
+    "backend/internal/app/queryspec"
     "backend/internal/service/advisorlog"
+    "backend/internal/service/fileasset"
+    "backend/internal/service/logtemplate"
+    "backend/internal/service/signedurl"
     "github.com/gin-gonic/gin"
 )
 
 
 type AdvisorLogController struct {
-	svc advisorlog.Service
+	svc    advisorlog.Service
+	files  fileasset.Service
+	signer *signedurl.Signer
 }
 
-func NewAdvisorLogController(svc advisorlog.Service) *AdvisorLogController {
+func NewAdvisorLogController(svc advisorlog.Service, files fileasset.Service, signer *signedurl.Signer) *AdvisorLogController {
 	return &AdvisorLogController{
-		svc: svc,
+		svc:    svc,
+		files:  files,
+		signer: signer,
 	}
 }
 // ==========================================
@@ -26,10 +36,10 @@ func NewAdvisorLogController(svc advisorlog.Service) *AdvisorLogController {
 // ==========================================
 func getUserFromContext(c *gin.Context) (uint, string) {
 	var userID uint
-	
+
 	// 1. ดึงค่าดิบๆ ออกมาก่อน (ยังไม่รู้ว่าเป็น Type อะไร)
 	v, exists := c.Get("user_id")
-	
+
 	if exists {
 		// 2. เช็ค Type และแปลงให้ถูก
 		switch val := v.(type) {
@@ -55,6 +65,45 @@ func getUserFromContext(c *gin.Context) (uint, string) {
 	return userID, role
 }
 
+// listFilterFromQuery อ่าน ?tag=a&tag=b (หรือ tag=a,b), ?topic_id=, ?status=, ?from=&to= (YYYY-MM-DD) และ page/page_size/cursor/sort
+func listFilterFromQuery(c *gin.Context) (dto.AdvisorLogListFilter, queryspec.Spec, bool) {
+	spec, ok := parseListQuery(c, advisorlog.ListQuery)
+	if !ok {
+		return dto.AdvisorLogListFilter{}, spec, false
+	}
+	topicID, err := spec.FilterUint("topic_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return dto.AdvisorLogListFilter{}, spec, false
+	}
+	return dto.AdvisorLogListFilter{
+		Tags:    c.QueryArray("tag"),
+		TopicID: topicID,
+		Status:  spec.Filter("status"),
+		From:    spec.Filter("from"),
+		To:      spec.Filter("to"),
+	}, spec, true
+}
+
+// writeTemplateError ตอบ error ของแบบฟอร์ม/tag (คืน false ถ้าไม่ใช่ error กลุ่มนี้)
+func writeTemplateError(c *gin.Context, err error) bool {
+	var fieldErr *logtemplate.FieldError
+	var tagErr *logtemplate.UnknownTagsError
+	switch {
+	case errors.As(err, &fieldErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "field": fieldErr.Field})
+	case errors.As(err, &tagErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "unknown_tags": tagErr.Names})
+	case errors.Is(err, logtemplate.ErrTemplateNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, logtemplate.ErrTemplateInactive), errors.Is(err, logtemplate.ErrTemplateTopic):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		return false
+	}
+	return true
+}
+
 // ------------------------------
 // CREATE LOG (multipart/form-data)
 // ------------------------------
@@ -62,8 +111,10 @@ func (ctrl *AdvisorLogController) Create(c *gin.Context) {
 	// 1. ดึง User ID และ Role จาก Context
 	userID, role := getUserFromContext(c)
 
-	const maxMemory = 32 << 20
-	_ = c.Request.ParseMultipartForm(maxMemory)
+	form, ok := parseUploadForm(c, fileasset.PolicyAdvisorLog)
+	if !ok {
+		return
+	}
 
 	var req dto.AdvisorLogCreateReq
 	if err := c.ShouldBind(&req); err != nil {
@@ -71,14 +122,21 @@ func (ctrl *AdvisorLogController) Create(c *gin.Context) {
 		return
 	}
 
-	if form, _ := c.MultipartForm(); form != nil {
+	if form != nil {
 		req.Files = form.File["files"]
 	}
 
 	// 2. ✅ ส่ง userID และ role ไปให้ Service
 	out, err := ctrl.svc.Create(c.Request.Context(), req, userID, role)
-	
+
 	if err != nil {
+		if writeUploadError(c, err) || writeTemplateError(c, err) {
+			return
+		}
+		if err == advisorlog.ErrPrivateNotesForbidden {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		if err == advisorlog.ErrForbidden {
 			 c.JSON(http.StatusForbidden, gin.H{"error": "You do not own this appointment (neither student nor advisor)"})
 			 return
@@ -124,19 +182,19 @@ func (ctrl *AdvisorLogController) GetByID(c *gin.Context) {
 }
 
 // ------------------------------
-// LIST BY STUDENT (🔒 แก้ไขแล้ว: Clean & Secure)
+// LIST BY STUDENT (🔒 ขอบเขตตรวจใน service)
 // ------------------------------
 func (ctrl *AdvisorLogController) ListByStudent(c *gin.Context) {
 	// 1. ดึง User Info
 	requesterID, role := getUserFromContext(c)
-	
+
 	var targetStudentID uint
 
-	// 2. Logic เลือก Target ID (ยังคงไว้ เพื่อกัน IDOR)
-	if role == "student" {
+	// 2. Logic เลือก Target ID (role จาก token เป็น "Student" จึงเทียบแบบไม่สนตัวพิมพ์)
+	if strings.EqualFold(role, "student") {
 		targetStudentID = requesterID // นักศึกษาดูได้แค่ของตัวเอง
 	} else {
-		// อาจารย์ดูของใครก็ได้
+		// อาจารย์/admin: service จำกัดให้เห็นเฉพาะนักศึกษาในขอบเขตของตัวเอง
 		paramID, err := strconv.Atoi(c.Param("student_id"))
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid student_id"})
@@ -145,30 +203,48 @@ func (ctrl *AdvisorLogController) ListByStudent(c *gin.Context) {
 		targetStudentID = uint(paramID)
 	}
 
-	// 3. ✅ เรียก Service: ส่ง role ไปด้วย!
-	// Service จะเอา role ไปเช็ค: ถ้าเป็น student -> เติม WHERE status != 'Draft' ให้เอง
-	out, err := ctrl.svc.ListByStudent(c.Request.Context(), targetStudentID, role)
-	
+	f, spec, ok := listFilterFromQuery(c)
+	if !ok {
+		return
+	}
+
+	out, err := ctrl.svc.ListByStudent(c.Request.Context(), targetStudentID, requesterID, role, f, spec)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeListError(c, err)
 		return
 	}
 
-	// 4. ส่งข้อมูลกลับได้เลย (ไม่ต้องมานั่งวนลูปกรองเองแล้ว)
-	c.JSON(http.StatusOK, gin.H{"data": out})
+	c.JSON(http.StatusOK, out)
 }
 
 // ------------------------------
-// LIST ALL (advisor)
+// LIST ALL (ตามขอบเขตของผู้เรียก)
 // ------------------------------
 func (ctrl *AdvisorLogController) ListAll(c *gin.Context) {
-	out, err := ctrl.svc.ListAll(c.Request.Context())
+	userID, role := getUserFromContext(c)
+
+	f, spec, ok := listFilterFromQuery(c)
+	if !ok {
+		return
+	}
+	out, err := ctrl.svc.ListAll(c.Request.Context(), f, spec, userID, role)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeListError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": out})
+	c.JSON(http.StatusOK, out)
+}
+
+func writeListError(c *gin.Context, err error) {
+	switch err {
+	case advisorlog.ErrForbidden:
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case advisorlog.ErrInvalidStatus, advisorlog.ErrInvalidDateRange:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
 }
 
 // ------------------------------
@@ -189,12 +265,44 @@ func (ctrl *AdvisorLogController) UpdateStatus(c *gin.Context) {
 		return
 	}
 
-	if err := ctrl.svc.UpdateStatus(c.Request.Context(), uint(id), req.Status); err != nil {
+	userID, role := getUserFromContext(c)
+
+	// ปิดบันทึกพร้อมนัดติดตามผล
+	if req.FollowUp != nil {
+		if req.Status != "Completed" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": advisorlog.ErrFollowUpNotCompleted.Error()})
+			return
+		}
+		out, err := ctrl.svc.CompleteWithFollowUp(c.Request.Context(), uint(id), *req.FollowUp, userID, role)
+		if err != nil {
+			switch err {
+			case advisorlog.ErrForbidden:
+				c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			case advisorlog.ErrAdvisorLogNotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			case advisorlog.ErrInvalidFollowUpTime, advisorlog.ErrFollowUpTopic, advisorlog.ErrFollowUpCategory:
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			case advisorlog.ErrFollowUpExists, advisorlog.ErrReportPendingReview:
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"message": "updated", "data": out})
+		return
+	}
+
+	if err := ctrl.svc.UpdateStatus(c.Request.Context(), uint(id), req.Status, userID, role); err != nil {
 		switch err {
+		case advisorlog.ErrForbidden:
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 		case advisorlog.ErrInvalidStatus:
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		case advisorlog.ErrAdvisorLogNotFound:
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case advisorlog.ErrReportPendingReview:
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		}
@@ -221,8 +329,10 @@ func (ctrl *AdvisorLogController) Update(c *gin.Context) {
         return
     }
 
-    const maxMemory = 32 << 20
-    _ = c.Request.ParseMultipartForm(maxMemory)
+    form, ok := parseUploadForm(c, fileasset.PolicyAdvisorLog)
+    if !ok {
+        return
+    }
 
     var req dto.AdvisorLogUpdateReq
 
@@ -233,6 +343,10 @@ func (ctrl *AdvisorLogController) Update(c *gin.Context) {
     if v := c.PostForm("body"); v != "" {
         req.Body = &v
     }
+    // ส่ง privateNotes มาแม้เป็นค่าว่าง = ตั้งค่าใหม่ (ลบได้)
+    if v, ok := c.GetPostForm("privateNotes"); ok {
+        req.PrivateNotes = &v
+    }
     if v := c.PostForm("requiresReport"); v != "" {
         b, err := strconv.ParseBool(v)
         if err != nil {
@@ -243,7 +357,7 @@ func (ctrl *AdvisorLogController) Update(c *gin.Context) {
     }
 
     // Files (optional)
-    if form, _ := c.MultipartForm(); form != nil && len(form.File["files"]) > 0 {
+    if form != nil && len(form.File["files"]) > 0 {
         req.Files = form.File["files"]
     }
 
@@ -251,12 +365,17 @@ func (ctrl *AdvisorLogController) Update(c *gin.Context) {
     out, err := ctrl.svc.Update(c.Request.Context(), uint(id), req, userID, role)
     
     if err != nil {
+        if writeUploadError(c, err) {
+            return
+        }
         switch err {
         case advisorlog.ErrAdvisorLogNotFound:
             c.JSON(http.StatusNotFound, gin.H{"error": "log not found"})
         case advisorlog.ErrForbidden:
             // 3. ✅ เพิ่ม Case นี้: เมื่อพยายามแก้ของคนอื่น
             c.JSON(http.StatusForbidden, gin.H{"error": "permission denied"})
+        case advisorlog.ErrPrivateNotesForbidden:
+            c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
         case advisorlog.ErrSaveFileFailed:
             c.JSON(http.StatusInternalServerError, gin.H{"error": "save file failed"})
         default:
@@ -273,27 +392,28 @@ func (ctrl *AdvisorLogController) Update(c *gin.Context) {
 // ------------------------------
 // DOWNLOAD FILE (🔒 แก้ไข Bug ประกาศตัวแปรซ้ำ)
 // ------------------------------
-func (ctrl *AdvisorLogController) DownloadFile(c *gin.Context) {
+// authorizedFile ตรวจ id/index และสิทธิ์ (GetFileForLog) แล้วคืนไฟล์ ถ้าไม่ผ่านจะตอบ error ไปแล้ว
+func (ctrl *AdvisorLogController) authorizedFile(c *gin.Context) (*entity.FileAssets, bool) {
 	// รับ id, index
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil || id <= 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
-		return
+		return nil, false
 	}
 	idx, err := strconv.Atoi(c.Param("index"))
 	if err != nil || idx < 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid index"})
-		return
+		return nil, false
 	}
 
 	sutAny, ok := c.Get("sut_id") 
 	if !ok {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing sut_id in context"})
-		return
+		return nil, false
 	}
 	sutID := strings.TrimSpace(fmt.Sprint(sutAny)) // ใช้ตัวแปรนี้ส่งไป
 
-	fileName, absPath, err := ctrl.svc.GetFileForLog(c.Request.Context(), uint(id), idx, sutID)
+	asset, err := ctrl.svc.GetFileForLog(c.Request.Context(), uint(id), idx, sutID)
 	if err != nil {
 		switch err {
 		case advisorlog.ErrAdvisorLogNotFound, advisorlog.ErrFileNotFound:
@@ -303,8 +423,145 @@ func (ctrl *AdvisorLogController) DownloadFile(c *gin.Context) {
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		}
+		return nil, false
+	}
+	return asset, true
+}
+
+func (ctrl *AdvisorLogController) DownloadFile(c *gin.Context) {
+	asset, ok := ctrl.authorizedFile(c)
+	if !ok {
 		return
 	}
+	serveFileAsset(c, ctrl.files, asset, false)
+}
 
-	c.FileAttachment(absPath, fileName)
+// ------------------------------
+// SIGNED FILE URL: GET /advisor_logs/:id/files/:index/url
+// ------------------------------
+func (ctrl *AdvisorLogController) FileURL(c *gin.Context) {
+	asset, ok := ctrl.authorizedFile(c)
+	if !ok {
+		return
+	}
+	signedFileURL(c, ctrl.signer, asset)
+}
+
+// ------------------------------
+// REVISIONS
+// ------------------------------
+
+func writeRevisionError(c *gin.Context, err error) {
+	switch err {
+	case advisorlog.ErrAdvisorLogNotFound, advisorlog.ErrRevisionNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case advisorlog.ErrForbidden:
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case advisorlog.ErrInvalidRevisionArg:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case advisorlog.ErrNothingToRestore, advisorlog.ErrRevisionFilesGone:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// GET /advisor_logs/:id/revisions?page=&page_size=&sort=
+func (ctrl *AdvisorLogController) ListRevisions(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	spec, ok := parseListQuery(c, advisorlog.RevisionListQuery)
+	if !ok {
+		return
+	}
+	userID, role := getUserFromContext(c)
+
+	out, err := ctrl.svc.ListRevisions(c.Request.Context(), uint(id), spec, userID, role)
+	if err != nil {
+		writeRevisionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// GET /advisor_logs/:id/revisions/diff?from=1&to=3
+func (ctrl *AdvisorLogController) DiffRevisions(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	from, err1 := strconv.Atoi(c.Query("from"))
+	to, err2 := strconv.Atoi(c.Query("to"))
+	if err1 != nil || err2 != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from and to must be revision numbers"})
+		return
+	}
+	userID, role := getUserFromContext(c)
+
+	out, err := ctrl.svc.DiffRevisions(c.Request.Context(), uint(id), from, to, userID, role)
+	if err != nil {
+		writeRevisionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": out})
+}
+
+// POST /advisor_logs/:id/revisions/:revision/restore (อาจารย์ที่ปรึกษาของนัดเท่านั้น)
+func (ctrl *AdvisorLogController) RestoreRevision(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	rev, err := strconv.Atoi(c.Param("revision"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid revision"})
+		return
+	}
+	userID, role := getUserFromContext(c)
+
+	out, err := ctrl.svc.RestoreRevision(c.Request.Context(), uint(id), rev, userID, role)
+	if err != nil {
+		writeRevisionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "restored",
+		"data":    out,
+	})
+}
+
+// ------------------------------
+// TAG ANALYTICS: GET /advisor_logs/analytics/tags?from=&to=&topic_id=
+// ------------------------------
+func (ctrl *AdvisorLogController) TagStats(c *gin.Context) {
+	userID, role := getUserFromContext(c)
+
+	f := dto.AdvisorLogTagStatsFilter{From: c.Query("from"), To: c.Query("to")}
+	if v := c.Query("topic_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil || id <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid topic_id"})
+			return
+		}
+		f.TopicID = uint(id)
+	}
+
+	out, err := ctrl.svc.TagStats(c.Request.Context(), f, userID, role)
+	if err != nil {
+		switch err {
+		case advisorlog.ErrForbidden:
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case advisorlog.ErrInvalidDateRange:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": out})
 }
@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"backend/internal/service/agenda"
+)
+
+type AgendaController struct {
+	svc agenda.Service
+}
+
+func NewAgendaController(svc agenda.Service) *AgendaController {
+	return &AgendaController{svc: svc}
+}
+
+// GET /api/me/agenda?from=YYYY-MM-DD&to=YYYY-MM-DD
+func (ctrl *AgendaController) Agenda(c *gin.Context) {
+	userID, role := getUserFromContext(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	out, err := ctrl.svc.Agenda(c.Request.Context(), c.Query("from"), c.Query("to"), userID, role)
+	if err != nil {
+		switch {
+		case errors.Is(err, agenda.ErrInvalidRange):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, agenda.ErrForbidden):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": out})
+}
@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"backend/internal/app/dto"
+	"backend/internal/service/booking"
+	"backend/internal/service/fileasset"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AppointmentBookingController struct {
+	svc   booking.Service
+	files fileasset.Service
+}
+
+func NewAppointmentBookingController(svc booking.Service, files fileasset.Service) *AppointmentBookingController {
+	return &AppointmentBookingController{svc: svc, files: files}
+}
+
+// ---------------------------
+// POST /api/appointments นักศึกษาขอนัด (multipart/form-data, แนบไฟล์ได้ใน field "files")
+// ---------------------------
+func (ctr *AppointmentBookingController) Request(c *gin.Context) {
+	userID, role := getUserFromContext(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	form, ok := parseUploadForm(c, fileasset.PolicyAppointmentAttachment)
+	if !ok {
+		return
+	}
+
+	var req dto.AppointmentRequestReq
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if form != nil {
+		req.Files = form.File["files"]
+	}
+
+	appt, err := ctr.svc.Request(c.Request.Context(), req, userID, role)
+	if err != nil {
+		if writeUploadError(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, booking.ErrStudentOnly):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, booking.ErrNoAdvisor), errors.Is(err, booking.ErrInvalidAdvisor), errors.Is(err, booking.ErrInvalidTime):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": appt})
+}
+
+// ---------------------------
+// GET /api/appointments/:id/attachments/:index
+// ---------------------------
+func (ctr *AppointmentBookingController) DownloadAttachment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	idx, err := strconv.Atoi(c.Param("index"))
+	if err != nil || idx < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid index"})
+		return
+	}
+	userID, role := getUserFromContext(c)
+
+	asset, err := ctr.svc.GetAttachment(c.Request.Context(), uint(id), idx, userID, role)
+	if err != nil {
+		switch {
+		case errors.Is(err, booking.ErrAppointmentNotFound), errors.Is(err, booking.ErrAttachmentNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, booking.ErrForbidden):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	serveFileAsset(c, ctr.files, asset, false)
+}
+
+func writeStudentViewError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, booking.ErrStudentOnly):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, booking.ErrAppointmentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, booking.ErrInvalidStatusFilter):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// ---------------------------
+// GET /api/appointments/mine?status=PENDING&page=&page_size=&sort= นัดของนักศึกษาที่ login
+// ---------------------------
+func (ctr *AppointmentBookingController) ListMine(c *gin.Context) {
+	userID, role := getUserFromContext(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	spec, ok := parseListQuery(c, booking.MineListQuery)
+	if !ok {
+		return
+	}
+	out, err := ctr.svc.ListMine(c.Request.Context(), userID, role, spec)
+	if err != nil {
+		writeStudentViewError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// ---------------------------
+// GET /api/appointments/mine/:id
+// ---------------------------
+func (ctr *AppointmentBookingController) GetMine(c *gin.Context) {
+	userID, role := getUserFromContext(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+	out, err := ctr.svc.GetMine(c.Request.Context(), id, userID, role)
+	if err != nil {
+		writeStudentViewError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": out})
+}
+
+// ---------------------------
+// GET /api/appointments/:id/history timeline สถานะเรียงตามเวลา
+// ---------------------------
+func (ctr *AppointmentBookingController) History(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+	userID, role := getUserFromContext(c)
+
+	out, err := ctr.svc.History(c.Request.Context(), id, userID, role)
+	if err != nil {
+		switch {
+		case errors.Is(err, booking.ErrAppointmentNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, booking.ErrForbidden):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": out})
+}
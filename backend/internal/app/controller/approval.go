@@ -1,24 +1,31 @@
 package controller
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
+	"backend/internal/app/dto"
 	"backend/internal/app/entity"
+	"backend/internal/app/repository"
 	service "backend/internal/service/approval"
+	"backend/internal/service/booking"
 
 	"github.com/gin-gonic/gin"
 )
 
 type AppointmentController struct {
 	service service.AppointmentService
+	// ตรวจสิทธิ์เห็นรายละเอียดนัด (กติกาเดียวกับไฟล์แนบ/timeline)
+	access booking.Service
 }
 
 // Constructor
-func NewAppointmentController(service service.AppointmentService) *AppointmentController {
-	return &AppointmentController{service: service}
+func NewAppointmentController(service service.AppointmentService, access booking.Service) *AppointmentController {
+	return &AppointmentController{service: service, access: access}
 }
 
 // ---------------------------
@@ -92,18 +99,22 @@ func (ctr *AppointmentController) ProposeNewTime(c *gin.Context) {
 	}
 
 	var request struct {
-		Description string `json:"description"`
+		Description string     `json:"description"`
+		StartAt     *time.Time `json:"start_at"` // optional (RFC3339)
+		EndAt       *time.Time `json:"end_at"`
 	}
 	if err := c.ShouldBindJSON(&request); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	appt, err := ctr.service.ProposeNewTime(
+	appt, err := ctr.service.ProposeNewTimeAt(
 		uint(AppointmentID64),
 		actorID,
 		role,
 		request.Description,
+		request.StartAt,
+		request.EndAt,
 	)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -128,11 +139,51 @@ func (ctr *AppointmentController) GetByID(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, appt)
+	// รายละเอียดนัด (รวมไฟล์แนบ) เห็นได้เฉพาะคู่นัดและแอดมิน
+	userID, role := getUserFromContext(c)
+	if err := ctr.access.Authorize(c.Request.Context(), appt, userID, role); err != nil {
+		if errors.Is(err, booking.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// สายนัดต้นทาง/นัดติดตามผล (แสดงเฉพาะนัดที่ผู้เรียกเห็นได้)
+	links, err := ctr.service.GetFollowUpChain(appt.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	chain := make([]dto.AppointmentChainItem, 0, len(links))
+	for _, l := range links {
+		a := l.Appointment
+		if err := ctr.access.Authorize(c.Request.Context(), &a, userID, role); err != nil {
+			if errors.Is(err, booking.ErrForbidden) {
+				continue
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		chain = append(chain, dto.AppointmentChainItem{
+			ID:                  a.ID,
+			ParentAppointmentID: a.ParentAppointmentID,
+			SourceAdvisorLogID:  a.SourceAdvisorLogID,
+			StartAt:             a.StartAt,
+			EndAt:               a.EndAt,
+			StatusID:            a.AppointmentStatusID,
+			StatusCode:          a.AppointmentStatus.StatusCode,
+			StatusName:          a.AppointmentStatus.StatusName,
+			Depth:               l.Depth,
+		})
+	}
+
+	c.JSON(http.StatusOK, dto.AppointmentDetailResp{Appointment: appt, FollowUpChain: chain})
 }
 
 // ---------------------------
-// 3) list: pending (ดึง advisorID จาก token)
+// 3) list: pending (ดึง advisorID จาก token) ?page=&page_size=&sort=&status=&topic_id=&student_id=
 // ---------------------------
 func (ctr *AppointmentController) ListPending(c *gin.Context) {
 	advisorID, ok := requireAdvisorFromContext(c)
@@ -140,13 +191,18 @@ func (ctr *AppointmentController) ListPending(c *gin.Context) {
 		return
 	}
 
-	appts, err := ctr.service.ListPendingByAdvisor(advisorID)
+	spec, ok := parseListQuery(c, repository.AppointmentListQuery)
+	if !ok {
+		return
+	}
+
+	appts, meta, err := ctr.service.ListPendingByAdvisor(advisorID, spec)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeAppointmentListError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, mapToListRows(appts))
+	c.JSON(http.StatusOK, dto.NewPageResp(mapToListRows(appts), meta))
 }
 
 // ---------------------------
@@ -158,13 +214,18 @@ func (ctr *AppointmentController) ListDone(c *gin.Context) {
 		return
 	}
 
-	appts, err := ctr.service.ListDoneByAdvisor(advisorID)
+	spec, ok := parseListQuery(c, repository.AppointmentListQuery)
+	if !ok {
+		return
+	}
+
+	appts, meta, err := ctr.service.ListDoneByAdvisor(advisorID, spec)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeAppointmentListError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, mapToListRows(appts))
+	c.JSON(http.StatusOK, dto.NewPageResp(mapToListRows(appts), meta))
 }
 
 // ---------------------------
@@ -176,17 +237,30 @@ func (ctr *AppointmentController) ListAll(c *gin.Context) {
 		return
 	}
 
-	appts, err := ctr.service.ListAllByAdvisor(advisorID)
+	spec, ok := parseListQuery(c, repository.AppointmentListQuery)
+	if !ok {
+		return
+	}
+
+	appts, meta, err := ctr.service.ListAllByAdvisor(advisorID, spec)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeAppointmentListError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, mapToListRows(appts))
+	c.JSON(http.StatusOK, dto.NewPageResp(mapToListRows(appts), meta))
 }
 
 // ---- helpers ----
 
+func writeAppointmentListError(c *gin.Context, err error) {
+	if errors.Is(err, repository.ErrInvalidAppointmentFilter) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
 // requireAdvisorFromContext: เช็ค role=ADVISOR และคืน user_id
 func requireAdvisorFromContext(c *gin.Context) (uint, bool) {
 	role, ok := getRoleFromContext(c)
@@ -258,14 +332,22 @@ func getRoleFromContext(c *gin.Context) (string, bool) {
 func mapToListRows(appts []entity.Appointment) []gin.H {
 	out := make([]gin.H, 0, len(appts))
 	for _, a := range appts {
-		out = append(out, gin.H{
+		row := gin.H{
 			"id":          a.ID,
 			"studentName": a.StudentUser.FirstName + " " + a.StudentUser.LastName,
 			"sutId":       a.StudentUser.SutId,
 			"topic":       a.Description,
 			"submittedAt": a.CreatedAt.Format("02/01/2006"),
 			"status":      a.AppointmentStatus.StatusCode, // PENDING/APPROVED/RESCHEDULE
-		})
+			"startAt":     a.StartAt,
+			"endAt":       a.EndAt,
+		}
+		// บันทึกการปรึกษาของนัด (nil = ยังไม่บันทึก)
+		if a.AdvisorLog != nil {
+			row["advisorLogId"] = a.AdvisorLog.ID
+			row["advisorLogStatus"] = a.AdvisorLog.Status
+		}
+		out = append(out, row)
 	}
 	return out
 }
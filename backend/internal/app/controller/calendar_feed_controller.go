@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"backend/internal/service/calendarfeed"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CalendarFeedController struct {
+	svc calendarfeed.Service
+}
+
+func NewCalendarFeedController(svc calendarfeed.Service) *CalendarFeedController {
+	return &CalendarFeedController{svc: svc}
+}
+
+const icsContentType = "text/calendar; charset=utf-8"
+
+// publicBaseURL ใช้ PUBLIC_API_URL ถ้าตั้งไว้ (เช่นอยู่หลัง reverse proxy) ไม่งั้นเดาจาก request
+func publicBaseURL(c *gin.Context) string {
+	if v := strings.TrimRight(os.Getenv("PUBLIC_API_URL"), "/"); v != "" {
+		return v
+	}
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if p := c.GetHeader("X-Forwarded-Proto"); p != "" {
+		scheme = p
+	}
+	return scheme + "://" + c.Request.Host
+}
+
+func (ctrl *CalendarFeedController) feedResponse(c *gin.Context, token string) {
+	url := fmt.Sprintf("%s/api/calendar/%s.ics", publicBaseURL(c), token)
+	c.JSON(http.StatusOK, gin.H{
+		"token":      token,
+		"url":        url,
+		"webcal_url": "webcal://" + strings.SplitN(url, "://", 2)[1],
+	})
+}
+
+// GET /api/me/calendar-feed
+func (ctrl *CalendarFeedController) GetMyFeed(c *gin.Context) {
+	userID, _ := getUserFromContext(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	token, err := ctrl.svc.GetOrCreateToken(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctrl.feedResponse(c, token)
+}
+
+// POST /api/me/calendar-feed/rotate (ลิงก์เดิมใช้ไม่ได้ทันที)
+func (ctrl *CalendarFeedController) RotateMyFeed(c *gin.Context) {
+	userID, _ := getUserFromContext(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	token, err := ctrl.svc.RotateToken(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctrl.feedResponse(c, token)
+}
+
+// GET /api/calendar/:token.ics (ไม่ต้อง login ใช้ token แทน)
+func (ctrl *CalendarFeedController) UserFeed(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	body, err := ctrl.svc.UserFeed(c.Request.Context(), token)
+	if err != nil {
+		if errors.Is(err, calendarfeed.ErrInvalidToken) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, icsContentType, body)
+}
+
+// GET /api/calendar/academic.ics?type=exam|activity|holiday
+func (ctrl *CalendarFeedController) AcademicFeed(c *gin.Context) {
+	body, err := ctrl.svc.AcademicFeed(c.Request.Context(), c.Query("type"))
+	if err != nil {
+		if errors.Is(err, calendarfeed.ErrInvalidEventType) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, icsContentType, body)
+}
+
+// GET /api/appointments/:id/ics
+func (ctrl *CalendarFeedController) AppointmentICS(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	userID, role := getUserFromContext(c)
+
+	body, err := ctrl.svc.AppointmentICS(c.Request.Context(), uint(id), userID, role)
+	if err != nil {
+		switch {
+		case errors.Is(err, calendarfeed.ErrAppointmentNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, calendarfeed.ErrForbidden):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, calendarfeed.ErrAppointmentNoTime):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="appointment-%d.ics"`, id))
+	c.Data(http.StatusOK, icsContentType, body)
+}
@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"backend/internal/service/calendarimport"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ขนาดไฟล์ปฏิทินสูงสุด (ปฏิทินทั้งปีมีไม่กี่ร้อย event)
+const maxCalendarImportSize = 2 << 20
+
+type CalendarImportController struct {
+	svc calendarimport.Service
+}
+
+func NewCalendarImportController(svc calendarimport.Service) *CalendarImportController {
+	return &CalendarImportController{svc: svc}
+}
+
+func (ctrl *CalendarImportController) handle(c *gin.Context, commit bool) {
+	userID, ok := requireAdmin(c)
+	if !ok {
+		return
+	}
+
+	fh, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required (.ics|.csv)"})
+		return
+	}
+	if fh.Size > maxCalendarImportSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file too large (max 2MB)"})
+		return
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer f.Close()
+
+	// type ที่ใช้กับแถวที่ไม่ได้ระบุประเภทมา (exam|activity|holiday)
+	defaultType := c.PostForm("default_type")
+
+	var res interface{}
+	if commit {
+		res, err = ctrl.svc.Import(c.Request.Context(), fh.Filename, f, defaultType, userID)
+	} else {
+		res, err = ctrl.svc.Preview(c.Request.Context(), fh.Filename, f, defaultType)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, calendarimport.ErrUnsupportedFormat),
+			errors.Is(err, calendarimport.ErrEmptyFile),
+			errors.Is(err, calendarimport.ErrMissingColumn),
+			errors.Is(err, calendarimport.ErrInvalidDefaultType):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// POST /api/admin/academic-calendar/import/preview (multipart: file, default_type)
+func (ctrl *CalendarImportController) Preview(c *gin.Context) {
+	ctrl.handle(c, false)
+}
+
+// POST /api/admin/academic-calendar/import (multipart: file, default_type)
+func (ctrl *CalendarImportController) Import(c *gin.Context) {
+	ctrl.handle(c, true)
+}
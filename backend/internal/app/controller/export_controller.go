@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend/internal/app/dto"
+	"backend/internal/service/export"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExportController struct {
+	svc export.Service
+}
+
+func NewExportController(svc export.Service) *ExportController {
+	return &ExportController{svc: svc}
+}
+
+// parseExportFilter อ่าน ?from=YYYY-MM-DD&to=YYYY-MM-DD&advisor_id=&status=&columns=
+func parseExportFilter(c *gin.Context) (dto.ExportFilter, error) {
+	var f dto.ExportFilter
+
+	loc, err := time.LoadLocation("Asia/Bangkok")
+	if err != nil {
+		loc = time.Local
+	}
+
+	if v := c.Query("from"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, loc)
+		if err != nil {
+			return f, errors.New("invalid from (YYYY-MM-DD)")
+		}
+		f.From = &t
+	}
+	if v := c.Query("to"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, loc)
+		if err != nil {
+			return f, errors.New("invalid to (YYYY-MM-DD)")
+		}
+		// ให้ "to" รวมทั้งวัน
+		t = t.AddDate(0, 0, 1)
+		f.To = &t
+	}
+	if v := c.Query("advisor_id"); v != "" {
+		n, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return f, errors.New("invalid advisor_id")
+		}
+		f.AdvisorID = uint(n)
+	}
+
+	f.Status = c.Query("status")
+	f.Columns = c.Query("columns")
+	return f, nil
+}
+
+type exportFunc func(ctx context.Context, f dto.ExportFilter, requesterID uint, requesterRole string) (*export.Job, error)
+
+// stream: ตรวจสิทธิ์/พารามิเตอร์ให้ครบก่อน แล้วค่อยเริ่มส่งไฟล์
+func (ctrl *ExportController) stream(c *gin.Context, run exportFunc) {
+	format, err := export.NormalizeFormat(c.Query("format"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	f, err := parseExportFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, role := getUserFromContext(c)
+
+	job, err := run(c.Request.Context(), f, userID, role)
+	if err != nil {
+		switch {
+		case errors.Is(err, export.ErrForbidden):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, export.ErrUnknownColumn), errors.Is(err, export.ErrInvalidDateRange):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	fileName := fmt.Sprintf("%s_%s.%s", job.Name, time.Now().Format("20060102_150405"), format)
+	c.Header("Content-Type", export.ContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	c.Status(http.StatusOK)
+
+	w, err := export.NewRowWriter(format, c.Writer, job.Name)
+	if err != nil {
+		log.Printf("export %s: %v", job.Name, err)
+		return
+	}
+
+	// เริ่มส่งข้อมูลไปแล้ว เปลี่ยน status code ไม่ได้ ทำได้แค่ log
+	if err := job.Run(c.Request.Context(), w); err != nil {
+		log.Printf("export %s: %v", job.Name, err)
+	}
+}
+
+// GET /api/export/appointments?format=csv|xlsx
+func (ctrl *ExportController) Appointments(c *gin.Context) {
+	ctrl.stream(c, ctrl.svc.Appointments)
+}
+
+// GET /api/export/advisor_logs?format=csv|xlsx
+func (ctrl *ExportController) AdvisorLogs(c *gin.Context) {
+	ctrl.stream(c, ctrl.svc.AdvisorLogs)
+}
+
+// GET /api/export/reports?format=csv|xlsx
+func (ctrl *ExportController) Reports(c *gin.Context) {
+	ctrl.stream(c, ctrl.svc.Reports)
+}
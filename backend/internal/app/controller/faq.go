@@ -2,24 +2,53 @@ package controller
 
 import (
 	"backend/config"
+	"backend/internal/app/dto"
 	"backend/internal/app/entity"
+	"backend/internal/app/queryspec"
 	"net/http"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
-func GetAllFAQ(c *gin.Context) {
-
-	db := config.DB()
-	var FAQ []entity.FAQ
-	results := db.Preload("AppointmentTopicID").Preload("UserID").Find(&FAQ)
-	if results.Error != nil {
-
-		c.JSON(http.StatusNotFound, gin.H{"error": results.Error.Error()})
+// ตัวกรอง/การเรียงที่รายการ FAQ รับได้
+var faqListQuery = queryspec.Options{
+	Sorts: map[string]string{
+		"id":         "id",
+		"created_at": "created_at",
+		"view_count": "view_count",
+		"question":   "faq_question",
+	},
+	DefaultSort: "-created_at",
+	Filters:     []string{"status", "topic_id"},
+}
 
+// GetAllFAQ ?status=Hide|Unhide&topic_id=&page=&page_size=&sort=
+func GetAllFAQ(c *gin.Context) {
+	spec, ok := parseListQuery(c, faqListQuery)
+	if !ok {
+		return
+	}
+	q := config.DB().Model(&entity.FAQ{})
+	if status := spec.Filter("status"); status != "" {
+		q = q.Where("faq_status = ?", status)
+	}
+	topicID, err := spec.FilterUint("topic_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
+	}
+	if topicID != 0 {
+		q = q.Where("faq_topic = ?", topicID)
+	}
 
+	faqs, meta, err := queryspec.Find(q, spec, func(f entity.FAQ) uint { return f.ID }, func(db *gorm.DB) *gorm.DB {
+		return db.Preload("AppointmentTopicID").Preload("UserID")
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
-	c.JSON(http.StatusOK, &FAQ)
+	c.JSON(http.StatusOK, dto.NewPageResp(faqs, meta))
 }
 
 func GetFAQ(c *gin.Context) {
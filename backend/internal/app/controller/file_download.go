@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend/internal/app/entity"
+	"backend/internal/service/fileasset"
+	"backend/internal/service/signedurl"
+
+	"github.com/gin-gonic/gin"
+)
+
+// contentDisposition รองรับชื่อไฟล์ภาษาไทย (RFC 6266 แบบ filename*=UTF-8)
+func contentDisposition(kind, fileName string) string {
+	if v := mime.FormatMediaType(kind, map[string]string{"filename": fileName}); v != "" {
+		return v
+	}
+	return kind
+}
+
+// serveFileAsset สตรีมไฟล์จาก storage กลับไปให้ client (เรียกหลังตรวจสิทธิ์แล้วเท่านั้น)
+// ใช้ http.ServeContent จึงรองรับ Range / If-Modified-Since ทั้ง local และ S3
+func serveFileAsset(c *gin.Context, files fileasset.Service, asset *entity.FileAssets, inline bool) {
+	obj, info, err := files.Open(c.Request.Context(), *asset)
+	if err != nil {
+		if errors.Is(err, fileasset.ErrFileNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer obj.Close()
+
+	kind := "attachment"
+	if inline {
+		kind = "inline"
+	}
+	mimeType := asset.MimeType
+	if mimeType == "" {
+		mimeType = info.ContentType
+	}
+	// นอกจากรูปภาพที่อนุญาต ให้ดาวน์โหลดเท่านั้น (กัน HTML/SVG ที่หลุดมาถูกรันบน origin ของ API)
+	if !fileasset.InlineSafe(mimeType) {
+		kind = "attachment"
+		mimeType = "application/octet-stream"
+	}
+
+	c.Header("Content-Type", mimeType)
+	c.Header("Content-Disposition", contentDisposition(kind, asset.OriginalName))
+	c.Header("X-Content-Type-Options", "nosniff")
+	http.ServeContent(c.Writer, c.Request, asset.OriginalName, info.ModTime, obj)
+}
+
+// ------------------------------
+// SIGNED URL
+// ------------------------------
+
+// signedFileURL ออกลิงก์ดาวน์โหลดแบบมีลายเซ็น (เรียกหลังตรวจสิทธิ์แล้วเท่านั้น)
+// query: disposition=inline|attachment, ttl=วินาที (ค่าเริ่มต้น 5 นาที สูงสุด 1 ชั่วโมง)
+func signedFileURL(c *gin.Context, signer *signedurl.Signer, asset *entity.FileAssets) {
+	ttl := time.Duration(0)
+	if v := c.Query("ttl"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ttl"})
+			return
+		}
+		ttl = time.Duration(n) * time.Second
+	}
+
+	q, expires := signer.Sign(asset.ID, c.DefaultQuery("disposition", signedurl.DispositionAttachment), ttl)
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{
+		"url":        fmt.Sprintf("%s/api/files/%d?%s", publicBaseURL(c), asset.ID, q.Encode()),
+		"expires_at": expires,
+		"name":       asset.OriginalName,
+	}})
+}
+
+type SignedFileController struct {
+	signer *signedurl.Signer
+	files  fileasset.Service
+}
+
+func NewSignedFileController(signer *signedurl.Signer, files fileasset.Service) *SignedFileController {
+	return &SignedFileController{signer: signer, files: files}
+}
+
+// GET /api/files/:id?exp=&disp=&sig= (ไม่ต้องใช้ JWT ตรวจจากลายเซ็นแทน)
+func (ctrl *SignedFileController) Download(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	disposition, err := ctrl.signer.Verify(uint(id), c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	asset, err := ctrl.files.Get(c.Request.Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, fileasset.ErrFileNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// ลิงก์เป็นของคนที่ได้รับสิทธิ์เท่านั้น ห้าม proxy/CDN เก็บ cache
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("Referrer-Policy", "no-referrer")
+	serveFileAsset(c, ctrl.files, asset, disposition == signedurl.DispositionInline)
+}
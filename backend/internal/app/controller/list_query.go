@@ -0,0 +1,19 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"backend/internal/app/queryspec"
+)
+
+// parseListQuery อ่าน page/page_size/cursor/sort/ตัวกรองของ list endpoint (ผิดรูปแบบ = 400)
+func parseListQuery(c *gin.Context, opt queryspec.Options) (queryspec.Spec, bool) {
+	spec, err := queryspec.Parse(c.Request.URL.Query(), opt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return spec, false
+	}
+	return spec, true
+}
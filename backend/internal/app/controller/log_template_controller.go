@@ -0,0 +1,220 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"backend/internal/app/dto"
+	"backend/internal/service/logtemplate"
+)
+
+type LogTemplateController struct {
+	svc logtemplate.Service
+}
+
+func NewLogTemplateController(svc logtemplate.Service) *LogTemplateController {
+	return &LogTemplateController{svc: svc}
+}
+
+func parseIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// includeInactive ?include_inactive=true ใช้ได้เฉพาะ admin (คนอื่นเห็นแค่ที่เปิดใช้งาน)
+func includeInactive(c *gin.Context) bool {
+	_, role := getUserFromContext(c)
+	return strings.ToLower(role) == "admin" && c.Query("include_inactive") == "true"
+}
+
+func writeLogTemplateError(c *gin.Context, err error) {
+	if writeTemplateError(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, logtemplate.ErrTopicNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, logtemplate.ErrTagNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, logtemplate.ErrDuplicateTag):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
+
+// ------------------------------
+// TEMPLATES
+// ------------------------------
+
+// GET /api/log-templates?topic_id=&page=&page_size=&sort=
+func (ctrl *LogTemplateController) ListTemplates(c *gin.Context) {
+	spec, ok := parseListQuery(c, logtemplate.TemplateListQuery)
+	if !ok {
+		return
+	}
+	topicID, err := spec.FilterUint("topic_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	out, err := ctrl.svc.ListTemplates(c.Request.Context(), topicID, includeInactive(c), spec)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// GET /api/log-templates/:id
+func (ctrl *LogTemplateController) GetTemplate(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+	out, err := ctrl.svc.GetTemplate(c.Request.Context(), id)
+	if err != nil {
+		writeLogTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": out})
+}
+
+// POST /api/admin/log-templates
+func (ctrl *LogTemplateController) CreateTemplate(c *gin.Context) {
+	if _, ok := requireAdmin(c); !ok {
+		return
+	}
+	var req dto.LogTemplateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	out, err := ctrl.svc.CreateTemplate(c.Request.Context(), req)
+	if err != nil {
+		writeLogTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": out})
+}
+
+// PUT /api/admin/log-templates/:id
+func (ctrl *LogTemplateController) UpdateTemplate(c *gin.Context) {
+	if _, ok := requireAdmin(c); !ok {
+		return
+	}
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+	var req dto.LogTemplateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	out, err := ctrl.svc.UpdateTemplate(c.Request.Context(), id, req)
+	if err != nil {
+		writeLogTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": out})
+}
+
+// DELETE /api/admin/log-templates/:id
+func (ctrl *LogTemplateController) DeleteTemplate(c *gin.Context) {
+	if _, ok := requireAdmin(c); !ok {
+		return
+	}
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+	if err := ctrl.svc.DeleteTemplate(c.Request.Context(), id); err != nil {
+		writeLogTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+// ------------------------------
+// TAGS
+// ------------------------------
+
+// GET /api/log-tags?page=&page_size=&sort=
+func (ctrl *LogTemplateController) ListTags(c *gin.Context) {
+	spec, ok := parseListQuery(c, logtemplate.TagListQuery)
+	if !ok {
+		return
+	}
+	out, err := ctrl.svc.ListTags(c.Request.Context(), includeInactive(c), spec)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// POST /api/admin/log-tags
+func (ctrl *LogTemplateController) CreateTag(c *gin.Context) {
+	if _, ok := requireAdmin(c); !ok {
+		return
+	}
+	var req dto.LogTagReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	out, err := ctrl.svc.CreateTag(c.Request.Context(), req)
+	if err != nil {
+		writeLogTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": out})
+}
+
+// PUT /api/admin/log-tags/:id
+func (ctrl *LogTemplateController) UpdateTag(c *gin.Context) {
+	if _, ok := requireAdmin(c); !ok {
+		return
+	}
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+	var req dto.LogTagReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	out, err := ctrl.svc.UpdateTag(c.Request.Context(), id, req)
+	if err != nil {
+		writeLogTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": out})
+}
+
+// DELETE /api/admin/log-tags/:id (ปิดใช้งาน ไม่ลบจริง)
+func (ctrl *LogTemplateController) DeactivateTag(c *gin.Context) {
+	if _, ok := requireAdmin(c); !ok {
+		return
+	}
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+	if err := ctrl.svc.DeactivateTag(c.Request.Context(), id); err != nil {
+		writeLogTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "deactivated"})
+}
@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"backend/internal/service/fileasset"
+	"backend/internal/service/profileimage"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProfileImageController struct {
+	svc   profileimage.Service
+	files fileasset.Service
+}
+
+func NewProfileImageController(svc profileimage.Service, files fileasset.Service) *ProfileImageController {
+	return &ProfileImageController{svc: svc, files: files}
+}
+
+// POST /api/me/profile-image (multipart field "image")
+func (ctrl *ProfileImageController) Upload(c *gin.Context) {
+	userID, _ := getUserFromContext(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	form, ok := parseUploadForm(c, fileasset.PolicyProfileImage)
+	if !ok {
+		return
+	}
+	if form == nil || len(form.File["image"]) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": profileimage.ErrNoFile.Error()})
+		return
+	}
+
+	url, err := ctrl.svc.Upload(c.Request.Context(), userID, form.File["image"][0])
+	if err != nil {
+		if writeUploadError(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"profile_image": url}})
+}
+
+// GET /api/users/:id/profile-image (ไม่ต้อง login ใช้กับ <img src>)
+func (ctrl *ProfileImageController) Get(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	asset, err := ctrl.svc.Get(c.Request.Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, profileimage.ErrImageNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=86400")
+	serveFileAsset(c, ctrl.files, asset, true)
+}
@@ -1,76 +1,162 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
-	"github.com/asaskevich/govalidator"
 	"github.com/gin-gonic/gin"
-	"backend/config"
-	"backend/internal/app/entity"
+	"backend/internal/app/dto"
+	"backend/internal/app/repository"
+	"backend/internal/service/fileasset"
+	"backend/internal/service/progressreport"
 )
 
-type ProgressReportController struct{}
+type ProgressReportController struct {
+	svc progressreport.Service
+}
 
-func NewProgressReportController() *ProgressReportController {
-	return &ProgressReportController{}
+func NewProgressReportController(svc progressreport.Service) *ProgressReportController {
+	return &ProgressReportController{svc: svc}
+}
+
+func writeProgressReportError(c *gin.Context, err error) {
+	if writeUploadError(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, progressreport.ErrLogNotFound), errors.Is(err, progressreport.ErrReportNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, progressreport.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, progressreport.ErrNotPending), errors.Is(err, progressreport.ErrAlreadyReviewed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, progressreport.ErrSaveFileFailed):
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
 }
 
 // 🟢 POST /progress_reports
+// รับได้ทั้ง JSON (ไม่มีไฟล์) และ multipart/form-data (advisor_logs_id, body, files)
 func (ctrl *ProgressReportController) Create(c *gin.Context) {
-	var report entity.ProgressReport
-
-	if err := c.ShouldBindJSON(&report); err != nil {
+	userID, role := getUserFromContext(c)
+
+	var req dto.ProgressReportCreateReq
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		form, ok := parseUploadForm(c, fileasset.PolicyProgressReport)
+		if !ok {
+			return
+		}
+		if err := c.ShouldBind(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if form != nil {
+			req.Files = form.File["files"]
+		}
+	} else if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if _, err := govalidator.ValidateStruct(report); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	out, err := ctrl.svc.Submit(c.Request.Context(), req, userID, role)
+	if err != nil {
+		writeProgressReportError(c, err)
 		return
 	}
 
-	db := config.DB()
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Report submitted successfully",
+		"data":    out,
+	})
+}
 
-	// 🔍 แก้ไขจุดที่ 1: ใช้ AdvisorLogsID ให้ตรงกับ Entity
-	var advisorLog entity.AdvisorLog
-	if err := db.First(&advisorLog, report.AdvisorLogsID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Advisor Log not found"})
+// 🔵 GET /progress_reports/log/:log_id
+// คืนทุก version (เก่าไปใหม่ ต่อกันด้วย previousId) พร้อม feedback ของแต่ละฉบับ
+func (ctrl *ProgressReportController) GetByLogID(c *gin.Context) {
+	logID, err := strconv.Atoi(c.Param("log_id"))
+	if err != nil || logID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid log_id"})
 		return
 	}
+	userID, role := getUserFromContext(c)
 
-	if advisorLog.Status != "PendingReport" { 
-		c.JSON(http.StatusBadRequest, gin.H{"error": "This log is not waiting for a report"})
+	out, err := ctrl.svc.ListByLog(c.Request.Context(), uint(logID), userID, role)
+	if err != nil {
+		writeProgressReportError(c, err)
 		return
 	}
 
-	report.SubmittedAt = time.Now()
-	if err := db.Create(&report).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	c.JSON(http.StatusOK, gin.H{"data": out})
+}
+
+// 🟣 POST /progress_reports/:id/review  {"decision": "approve" | "request_revision", "comment": "..."}
+func (ctrl *ProgressReportController) Review(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
 		return
 	}
-
-	if err := db.Model(&advisorLog).Update("status", "ReportSubmitted").Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update log status"})
+	var req dto.ReportReviewReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"message": "Report submitted successfully",
-		"data":    report,
-	})
+	userID, role := getUserFromContext(c)
+	out, err := ctrl.svc.Review(c.Request.Context(), id, req, userID, role)
+	if err != nil {
+		writeProgressReportError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": out})
 }
 
-// 🔵 GET /progress_reports/log/:log_id
-func (ctrl *ProgressReportController) GetByLogID(c *gin.Context) {
-	logID := c.Param("log_id")
-	var reports []entity.ProgressReport
+// 🟠 PUT /advisor_logs/:id/report_due  {"dueAt": "2025-01-31" | RFC3339 | null}
+func (ctrl *ProgressReportController) SetDueDate(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+	var req dto.ReportDueReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	// 🔍 แก้ไขจุดที่ 2: เปลี่ยนชื่อคอลัมน์เป็น advisor_logs_id
-	if err := config.DB().Preload("Feedbacks").Where("advisor_logs_id = ?", logID).Find(&reports).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	var due *time.Time
+	if req.DueAt != nil && strings.TrimSpace(*req.DueAt) != "" {
+		t, err := progressreport.ParseDue(*req.DueAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		due = &t
+	}
+
+	userID, role := getUserFromContext(c)
+	out, err := ctrl.svc.SetDueDate(c.Request.Context(), id, due, userID, role)
+	if err != nil {
+		writeProgressReportError(c, err)
 		return
 	}
+	c.JSON(http.StatusOK, gin.H{"data": out})
+}
 
-	c.JSON(http.StatusOK, gin.H{"data": reports})
-}
\ No newline at end of file
+// 🔵 GET /advisor/me/overdue_reports?page=&page_size=&sort=
+func (ctrl *ProgressReportController) ListOverdue(c *gin.Context) {
+	spec, ok := parseListQuery(c, repository.OverdueListQuery)
+	if !ok {
+		return
+	}
+	userID, role := getUserFromContext(c)
+	out, err := ctrl.svc.ListOverdue(c.Request.Context(), spec, userID, role)
+	if err != nil {
+		writeProgressReportError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
@@ -5,22 +5,52 @@ import (
 	"backend/internal/app/dto"
 	"backend/config"
 	"backend/internal/app/entity"
+	"backend/internal/app/queryspec"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
+// ตัวกรอง/การเรียงที่ GET /reports รับได้
+var reportListQuery = queryspec.Options{
+	Sorts: map[string]string{
+		"id":         "id",
+		"created_at": "created_at",
+		"status":     "report_status_id",
+		"topic":      "report_topic_id",
+	},
+	DefaultSort: "-created_at",
+	Filters:     []string{"status_id", "topic_id", "report_by_id"},
+}
+
 /*
-GET: /reports
+GET: /reports?status_id=&topic_id=&report_by_id=&page=&page_size=&sort=
 */
 func GetAllReport(c *gin.Context) {
-	db := config.DB()
+	spec, ok := parseListQuery(c, reportListQuery)
+	if !ok {
+		return
+	}
+	q := config.DB().Model(&entity.Report{})
+	for name, col := range map[string]string{"status_id": "report_status_id", "topic_id": "report_topic_id", "report_by_id": "report_by_id"} {
+		id, err := spec.FilterUint(name)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if id != 0 {
+			q = q.Where(col+" = ?", id)
+		}
+	}
 
-	var reports []entity.Report
-	db.Preload("User").
-	   Preload("Status").
-	   Preload("Topic").
-	   Find(&reports)
+	reports, meta, err := queryspec.Find(q, spec, func(r entity.Report) uint { return r.ID }, func(db *gorm.DB) *gorm.DB {
+		return db.Preload("User").Preload("Status").Preload("Topic")
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
-	var response []dto.ReportDTO
+	response := make([]dto.ReportDTO, 0, len(reports))
 
 	for _, r := range reports {
 		item := dto.ReportDTO{
@@ -57,7 +87,7 @@ func GetAllReport(c *gin.Context) {
 		response = append(response, item)
 	}
 
-	c.JSON(http.StatusOK, response)
+	c.JSON(http.StatusOK, dto.NewPageResp(response, meta))
 }
 
 
@@ -1,54 +1,110 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 
-	"github.com/asaskevich/govalidator"
 	"github.com/gin-gonic/gin"
-	"backend/config"
-	"backend/internal/app/entity"
+	"backend/internal/app/dto"
+	"backend/internal/service/fileasset"
+	"backend/internal/service/progressreport"
 )
 
-type ReportFeedbackController struct{}
+type ReportFeedbackController struct {
+	svc   progressreport.Service
+	files fileasset.Service
+}
+
+func NewReportFeedbackController(svc progressreport.Service, files fileasset.Service) *ReportFeedbackController {
+	return &ReportFeedbackController{svc: svc, files: files}
+}
 
-func NewReportFeedbackController() *ReportFeedbackController {
-	return &ReportFeedbackController{}
+func writeFeedbackError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, progressreport.ErrFeedbackNotFound), errors.Is(err, progressreport.ErrFileNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, progressreport.ErrNotAuthor), errors.Is(err, progressreport.ErrEditWindowClosed):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		writeProgressReportError(c, err)
+	}
 }
 
 // 🟢 POST /report_feedbacks
-// หน้าที่: อาจารย์บันทึกคอมเมนต์ (Feedback) ลงในรายงานของนักศึกษา
+// หน้าที่: นักศึกษา/อาจารย์ของนัดแสดงความเห็นหรือตอบกลับ (parentId) ในรายงาน แนบไฟล์ได้ถ้าส่งเป็น multipart
 func (ctrl *ReportFeedbackController) Create(c *gin.Context) {
-	var feedback entity.ReportFeedback
+	userID, role := getUserFromContext(c)
 
-	// 1. รับค่าจาก JSON
-	if err := c.ShouldBindJSON(&feedback); err != nil {
+	var req dto.ReportFeedbackCreateReq
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		form, ok := parseUploadForm(c, fileasset.PolicyReportFeedback)
+		if !ok {
+			return
+		}
+		if err := c.ShouldBind(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if form != nil {
+			req.Files = form.File["files"]
+		}
+	} else if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// 2. Validation
-	if _, err := govalidator.ValidateStruct(feedback); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	out, err := ctrl.svc.AddFeedback(c.Request.Context(), req, userID, role)
+	if err != nil {
+		writeFeedbackError(c, err)
 		return
 	}
 
-	db := config.DB()
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Feedback submitted successfully",
+		"data":    out,
+	})
+}
+
+// 🟠 PATCH /report_feedbacks/:id  (ผู้เขียนเท่านั้น ภายในช่วงเวลาแก้ไข)
+func (ctrl *ReportFeedbackController) Update(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+	var req dto.ReportFeedbackUpdateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	// 3. (Optional) เช็คว่า Report ที่จะคอมเมนต์มีอยู่จริงไหม
-	var report entity.ProgressReport
-	if err := db.First(&report, feedback.ProgressReportsID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Progress Report not found"})
+	userID, role := getUserFromContext(c)
+	out, err := ctrl.svc.EditFeedback(c.Request.Context(), id, req, userID, role)
+	if err != nil {
+		writeFeedbackError(c, err)
 		return
 	}
+	c.JSON(http.StatusOK, gin.H{"data": out})
+}
 
-	// 4. บันทึก Feedback ลงฐานข้อมูล
-	if err := db.Create(&feedback).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+// 🔵 GET /report_feedbacks/:id/files/:index
+func (ctrl *ReportFeedbackController) DownloadFile(c *gin.Context) {
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid index"})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"message": "Feedback submitted successfully",
-		"data":    feedback,
-	})
-}
\ No newline at end of file
+	userID, role := getUserFromContext(c)
+	asset, err := ctrl.svc.FeedbackFile(c.Request.Context(), id, index, userID, role)
+	if err != nil {
+		writeFeedbackError(c, err)
+		return
+	}
+	serveFileAsset(c, ctrl.files, asset, false)
+}
@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"backend/internal/app/dto"
+	"backend/internal/app/entity"
+	"backend/internal/service/fileasset"
+	"backend/internal/service/reportimage"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReportImageController struct {
+	svc   reportimage.Service
+	files fileasset.Service
+}
+
+func NewReportImageController(svc reportimage.Service, files fileasset.Service) *ReportImageController {
+	return &ReportImageController{svc: svc, files: files}
+}
+
+func writeReportImageError(c *gin.Context, err error) {
+	if writeUploadError(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, reportimage.ErrReportNotFound), errors.Is(err, reportimage.ErrImageNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, reportimage.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, reportimage.ErrNoFiles):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// toReportImageResp start = ลำดับของรูปแรกในรายการทั้งหมด (หน้าถัดไปนับต่อจากหน้าก่อน)
+func toReportImageResp(images []entity.ReportImage, start int) []dto.FileAssetResp {
+	out := make([]dto.FileAssetResp, 0, len(images))
+	for _, img := range images {
+		if img.FileAssets == nil {
+			continue
+		}
+		r := fileasset.ToResp([]entity.FileAssets{*img.FileAssets})[0]
+		r.ID = img.ID
+		r.Index = start + len(out)
+		out = append(out, r)
+	}
+	return out
+}
+
+// POST /reports/:id/images (multipart field "images")
+func (ctrl *ReportImageController) Upload(c *gin.Context) {
+	reportID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || reportID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid report id"})
+		return
+	}
+	userID, role := getUserFromContext(c)
+
+	form, ok := parseUploadForm(c, fileasset.PolicyReportImage)
+	if !ok {
+		return
+	}
+	if form == nil {
+		writeReportImageError(c, reportimage.ErrNoFiles)
+		return
+	}
+
+	images, err := ctrl.svc.Upload(c.Request.Context(), uint(reportID), form.File["images"], userID, role)
+	if err != nil {
+		writeReportImageError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": toReportImageResp(images, 0)})
+}
+
+// GET /reports/:id/images?page=&page_size=
+func (ctrl *ReportImageController) List(c *gin.Context) {
+	reportID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || reportID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid report id"})
+		return
+	}
+	spec, ok := parseListQuery(c, reportimage.ListQuery)
+	if !ok {
+		return
+	}
+	userID, role := getUserFromContext(c)
+
+	images, meta, err := ctrl.svc.List(c.Request.Context(), uint(reportID), spec, userID, role)
+	if err != nil {
+		writeReportImageError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewPageResp(toReportImageResp(images, (meta.Page-1)*meta.PageSize), meta))
+}
+
+// GET /reports/:id/images/:image_id
+func (ctrl *ReportImageController) Download(c *gin.Context) {
+	reportID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || reportID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid report id"})
+		return
+	}
+	imageID, err := strconv.Atoi(c.Param("image_id"))
+	if err != nil || imageID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid image id"})
+		return
+	}
+	userID, role := getUserFromContext(c)
+
+	asset, err := ctrl.svc.Get(c.Request.Context(), uint(reportID), uint(imageID), userID, role)
+	if err != nil {
+		writeReportImageError(c, err)
+		return
+	}
+	serveFileAsset(c, ctrl.files, asset, true)
+}
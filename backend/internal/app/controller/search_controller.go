@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"backend/internal/service/search"
+)
+
+type SearchController struct {
+	svc search.Service
+}
+
+func NewSearchController(svc search.Service) *SearchController {
+	return &SearchController{svc: svc}
+}
+
+// GET /api/search?q=&type=user,advisor_log,faq,report&limit=
+func (ctrl *SearchController) Search(c *gin.Context) {
+	userID, role := getUserFromContext(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	types, err := search.ParseTypes(c.QueryArray("type"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	limit := 0
+	if v := c.Query("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+	}
+
+	out, err := ctrl.svc.Search(c.Request.Context(), c.Query("q"), types, limit, userID, role)
+	if err != nil {
+		switch {
+		case errors.Is(err, search.ErrQueryTooShort), errors.Is(err, search.ErrInvalidType):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, search.ErrForbidden):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": out})
+}
@@ -0,0 +1,168 @@
+package controller
+
+import (
+	"backend/internal/app/dto"
+	"backend/internal/app/repository"
+	"backend/internal/service/academiccalendar"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SemesterController struct {
+	Service *service.SemesterService
+}
+
+func NewSemesterController(s *service.SemesterService) *SemesterController {
+	return &SemesterController{Service: s}
+}
+
+// parseDateQuery อ่าน ?date=YYYY-MM-DD (ไม่ส่ง = วันนี้)
+func parseDateQuery(c *gin.Context) (time.Time, error) {
+	loc, err := time.LoadLocation("Asia/Bangkok")
+	if err != nil {
+		loc = time.Local
+	}
+	v := c.Query("date")
+	if v == "" {
+		return time.Now().In(loc), nil
+	}
+	return time.ParseInLocation("2006-01-02", v, loc)
+}
+
+func requireAdmin(c *gin.Context) (uint, bool) {
+	userID, role := getUserFromContext(c)
+	if strings.ToLower(role) != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "admin only"})
+		return 0, false
+	}
+	return userID, true
+}
+
+// GET /api/semesters?academic_year=&page=&page_size=&sort=
+func (ctrl *SemesterController) List(c *gin.Context) {
+	spec, ok := parseListQuery(c, repository.SemesterListQuery)
+	if !ok {
+		return
+	}
+	if _, err := spec.FilterUint("academic_year"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	out, err := ctrl.Service.List(spec)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// GET /api/semesters/at?date=YYYY-MM-DD
+func (ctrl *SemesterController) At(c *gin.Context) {
+	date, err := parseDateQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date (YYYY-MM-DD)"})
+		return
+	}
+
+	sem, err := ctrl.Service.SemesterAt(date)
+	if err != nil {
+		if errors.Is(err, service.ErrNoSemester) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, sem)
+}
+
+// GET /api/semesters/teaching-day?date=YYYY-MM-DD
+func (ctrl *SemesterController) TeachingDay(c *gin.Context) {
+	date, err := parseDateQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date (YYYY-MM-DD)"})
+		return
+	}
+
+	res, err := ctrl.Service.IsTeachingDay(date)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// POST /api/semesters (admin)
+func (ctrl *SemesterController) Create(c *gin.Context) {
+	adminID, ok := requireAdmin(c)
+	if !ok {
+		return
+	}
+
+	var req dto.SemesterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ข้อมูลที่ส่งมาไม่ถูกต้อง"})
+		return
+	}
+	req.AdminID = adminID
+
+	sem, err := ctrl.Service.Create(req)
+	if err != nil {
+		if errors.Is(err, service.ErrSemesterExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Semester created", "data": sem})
+}
+
+// PUT /api/semesters/:id (admin)
+func (ctrl *SemesterController) Update(c *gin.Context) {
+	if _, ok := requireAdmin(c); !ok {
+		return
+	}
+
+	var req dto.SemesterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ข้อมูลที่ส่งมาไม่ถูกต้อง"})
+		return
+	}
+
+	sem, err := ctrl.Service.Update(c.Param("id"), req)
+	if err != nil {
+		if errors.Is(err, service.ErrSemesterNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrSemesterExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Semester updated", "data": sem})
+}
+
+// DELETE /api/semesters/:id (admin)
+func (ctrl *SemesterController) Delete(c *gin.Context) {
+	if _, ok := requireAdmin(c); !ok {
+		return
+	}
+
+	if err := ctrl.Service.Delete(c.Param("id")); err != nil {
+		if errors.Is(err, service.ErrSemesterNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Semester deleted"})
+}
@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"errors"
+	"mime/multipart"
+	"net/http"
+
+	"backend/internal/service/fileasset"
+
+	"github.com/gin-gonic/gin"
+)
+
+// หน่วยความจำที่ใช้พักไฟล์ตอน parse (ส่วนเกินจะเขียนลงไฟล์ชั่วคราว)
+const multipartMaxMemory = 32 << 20
+
+// เผื่อขนาดของ field ข้อความและ boundary ใน multipart
+const multipartOverhead = 1 << 20
+
+// parseUploadForm จำกัดขนาด request ตาม policy แล้ว parse multipart
+// request ที่ไม่ใช่ multipart (ไม่มีไฟล์) ถือว่าผ่าน คืน form = nil
+func parseUploadForm(c *gin.Context, p fileasset.Policy) (*multipart.Form, bool) {
+	if p.MaxRequestSize > 0 {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, p.MaxRequestSize+multipartOverhead)
+	}
+
+	if err := c.Request.ParseMultipartForm(multipartMaxMemory); err != nil {
+		if errors.Is(err, http.ErrNotMultipart) {
+			return nil, true
+		}
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeUploadError(c, &fileasset.UploadError{
+				Code:    fileasset.CodeRequestTooLarge,
+				Message: "request body too large",
+				Limit:   p.MaxRequestSize,
+			})
+			return nil, false
+		}
+		writeUploadError(c, &fileasset.UploadError{
+			Code:    fileasset.CodeInvalidMultipart,
+			Message: "invalid multipart form: " + err.Error(),
+		})
+		return nil, false
+	}
+	return c.Request.MultipartForm, true
+}
+
+// writeUploadError ตอบ error การอัปโหลดแบบมีโครงสร้าง คืน false ถ้า err ไม่ใช่ UploadError
+func writeUploadError(c *gin.Context, err error) bool {
+	var ue *fileasset.UploadError
+	if !errors.As(err, &ue) {
+		return false
+	}
+
+	status := http.StatusBadRequest
+	switch ue.Code {
+	case fileasset.CodeRequestTooLarge, fileasset.CodeFileTooLarge:
+		status = http.StatusRequestEntityTooLarge
+	case fileasset.CodeUnsupportedType:
+		status = http.StatusUnsupportedMediaType
+	case fileasset.CodeQuotaExceeded:
+		status = http.StatusInsufficientStorage
+	}
+
+	c.JSON(status, gin.H{"error": ue.Message, "upload_error": ue})
+	return true
+}
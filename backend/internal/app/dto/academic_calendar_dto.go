@@ -1,5 +1,7 @@
 package dto
 
+import "backend/internal/app/entity"
+
 type EventRequest struct {
 	Title     string `json:"title"`
 	Type      string `json:"type"`
@@ -10,6 +12,9 @@ type EventRequest struct {
 
 	StartTime string `json:"start_time"`
 	EndTime   string `json:"end_time"`
+
+	// event ซ้ำ เช่น "FREQ=WEEKLY;BYDAY=MO;UNTIL=20251231" (ไม่ส่ง = ไม่ซ้ำ)
+	RRule     string `json:"rrule"`
 	
 	AdminID   uint   `json:"-"` 
 }
@@ -28,4 +33,73 @@ type HolidayResponse struct {
 	
 	StartTime   string   `json:"start_time"`
 	EndTime     string   `json:"end_time"`
-}
\ No newline at end of file
+
+	// มีค่าเมื่อเป็น occurrence ของ event ซ้ำ (ID เดียวกันได้หลายรายการ)
+	RRule       string   `json:"rrule,omitempty"`
+	IsHoliday   bool     `json:"is_holiday"`
+}
+// ------------------------------
+// IMPORT (.ics / .csv)
+// ------------------------------
+
+// CalendarImportRow 1 event ที่อ่านได้จากไฟล์ (ใช้ทั้งตอน preview และตอน import จริง)
+type CalendarImportRow struct {
+	Line      int    `json:"line"` // ลำดับในไฟล์ (CSV = เลขบรรทัด, ICS = ลำดับ VEVENT)
+	Title     string `json:"title"`
+	Type      string `json:"type"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+	IsHoliday bool   `json:"is_holiday"`
+	RRule     string `json:"rrule,omitempty"`
+
+	// new | duplicate | invalid
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type CalendarImportResult struct {
+	Format     string              `json:"format"`
+	Total      int                 `json:"total"`
+	New        int                 `json:"new"`
+	Duplicates int                 `json:"duplicates"`
+	Invalid    int                 `json:"invalid"`
+	Imported   int                 `json:"imported"` // 0 ตอน preview
+	Rows       []CalendarImportRow `json:"rows"`
+}
+
+// ------------------------------
+// SEMESTER
+// ------------------------------
+
+// SemesterListResp รายการภาคการศึกษาแบบแบ่งหน้า
+type SemesterListResp = PageResp[entity.Semester]
+
+// SemesterRequest วันที่ทั้งหมดเป็น YYYY-MM-DD (ช่วงลงทะเบียน/สอบไม่บังคับ แต่ต้องส่งคู่กัน)
+type SemesterRequest struct {
+	Name         string `json:"name"`
+	AcademicYear int    `json:"academic_year"`
+	TermNumber   int    `json:"term_number"`
+
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+
+	RegistrationStart string `json:"registration_start"`
+	RegistrationEnd   string `json:"registration_end"`
+	MidtermExamStart  string `json:"midterm_exam_start"`
+	MidtermExamEnd    string `json:"midterm_exam_end"`
+	FinalExamStart    string `json:"final_exam_start"`
+	FinalExamEnd      string `json:"final_exam_end"`
+
+	AdminID uint `json:"-"`
+}
+
+type TeachingDayResponse struct {
+	Date          string           `json:"date"`
+	IsTeachingDay bool             `json:"is_teaching_day"`
+	// outside_semester | weekend | holiday | exam_period (ว่างถ้าเป็นวันเรียน)
+	Reason        string           `json:"reason,omitempty"`
+	HolidayName   string           `json:"holiday_name,omitempty"`
+	Semester      *entity.Semester `json:"semester"`
+}
@@ -35,7 +35,6 @@ type UserListFilter struct {
 	Major      string `form:"major"`
     Department string `form:"department"`
     Search     string `form:"search"`    // ค้นหา SutID, Name, Email
-    // สามารถเพิ่ม Page, PageSize ได้ในอนาคต เช่น Page int `form:"page"`
 }
 
 // AdminProfileResponse (GET /api/admin/profile)
@@ -141,14 +140,11 @@ type ManagedUserEntry struct {
 	Active         bool   `json:"active"`
 }
 
-// AdminManagedUsersResponse DTO สำหรับรายการผู้ใช้ทั้งหมดในระบบ
-type AdminManagedUsersResponse struct {
-	TotalUsers int                `json:"totalUsers"`
-	Users      []ManagedUserEntry `json:"users"`
-}
+// AdminManagedUsersResponse DTO สำหรับรายการผู้ใช้ทั้งหมดในระบบ (ซองแบ่งหน้ามาตรฐาน)
+type AdminManagedUsersResponse = PageResp[ManagedUserEntry]
 
 // NewAdminManagedUsersResponse สร้าง DTO จาก []entity.User
-func NewAdminManagedUsersResponse(users []*entity.User) AdminManagedUsersResponse {
+func NewAdminManagedUsersResponse(users []*entity.User, meta PageMeta) AdminManagedUsersResponse {
 	entries := make([]ManagedUserEntry, 0, len(users))
 	for _, u := range users {
 		majorName := ""
@@ -187,10 +183,7 @@ func NewAdminManagedUsersResponse(users []*entity.User) AdminManagedUsersRespons
 		})
 	}
 
-	return AdminManagedUsersResponse{
-		TotalUsers: len(users),
-		Users:      entries,
-	}
+	return NewPageResp(entries, meta)
 }
 
 // AdminUserDetailResponse (GET /api/admin/users/:sut_id)
@@ -5,20 +5,45 @@ import "mime/multipart"
 type AdvisorLogCreateReq struct {
 	AppointmentID  uint                    `form:"appointmentId" binding:"required"`
 	Title          string                  `form:"title" binding:"required"`
-	Body           string                  `form:"body" binding:"required"`
+	Body           string                  `form:"body"` // ว่างได้ถ้าใช้แบบฟอร์ม (สร้างจากค่าที่กรอก)
 	RequiresReport bool                    `form:"requiresReport"`
 	Status         string                  `form:"status"` 
+	PrivateNotes   string                  `form:"privateNotes"` // อาจารย์ที่ปรึกษาของนัดเท่านั้น
+	TemplateID     uint                    `form:"templateId"`
+	TemplateValues string                  `form:"templateValues"` // JSON object {key: value} ตาม field ของแบบฟอร์ม
+	Tags           []string                `form:"tags"`           // ชื่อ tag (ส่งซ้ำหลายค่า หรือคั่นด้วย comma)
 	Files          []*multipart.FileHeader `form:"files"`  
 }
 
 type AdvisorLogUpdateStatusReq struct {
 	Status string `json:"status" binding:"required"`
+	// นัดติดตามผล (ส่งได้เฉพาะตอนเปลี่ยนเป็น Completed)
+	FollowUp *FollowUpReq `json:"followUp"`
+}
+
+// FollowUpReq นัดติดตามผลที่อาจารย์สร้างให้ (อนุมัติแล้วทันที) หัวข้อ/หมวดว่าง = ใช้ของนัดเดิม
+type FollowUpReq struct {
+	StartAt     string `json:"start_at" binding:"required"` // RFC3339
+	EndAt       string `json:"end_at" binding:"required"`
+	Description string `json:"description"`
+	TopicID     uint   `json:"topic_id"`
+	CategoryID  uint   `json:"category_id"`
+}
+
+type FollowUpResp struct {
+	AdvisorLogID        uint   `json:"advisorLogId"`
+	Status              string `json:"status"`
+	AppointmentID       uint   `json:"appointmentId"` // นัดติดตามผลที่สร้างขึ้น
+	ParentAppointmentID uint   `json:"parentAppointmentId"`
+	StartAt             string `json:"startAt"`
+	EndAt               string `json:"endAt"`
 }
 
 type AdvisorLogUpdateReq struct {
 	Title          *string                 `form:"title"`
 	Body           *string                 `form:"body"`
 	RequiresReport *bool                   `form:"requiresReport"`
+	PrivateNotes   *string                 `form:"privateNotes"` // ส่งค่าว่างมา = ลบบันทึกส่วนตัว
 	Files          []*multipart.FileHeader `form:"files"`
 }
 
@@ -30,8 +55,16 @@ type AdvisorLogRespBase struct {
 	Body           string `json:"body"`
 	Status         string `json:"status"`
 	RequiresReport bool   `json:"requiresReport"`
-	FileName       string `json:"fileName"`
-	FilePath       string `json:"-"` 
+	ReportDueAt    *string `json:"reportDueAt,omitempty"` // RFC3339
+	// ชื่อไฟล์คั่นด้วย comma (คงไว้ให้ frontend เดิม) ใช้ Files แทนถ้าเป็นไปได้
+	FileName       string          `json:"fileName"`
+	Files          []FileAssetResp `json:"files"`
+	// มีค่าเฉพาะเมื่อผู้เรียกคืออาจารย์ที่ปรึกษาของนัดนี้
+	PrivateNotes *string `json:"privateNotes,omitempty"`
+
+	TemplateID     *uint          `json:"templateId,omitempty"`
+	TemplateValues map[string]any `json:"templateValues,omitempty"`
+	Tags           []LogTagResp   `json:"tags"`
 
 	CreatedAt string `json:"createdAt"`
 	UpdatedAt string `json:"updatedAt"`
@@ -43,4 +76,48 @@ type AdvisorLogUpdateResp struct{ AdvisorLogRespBase }
 
 type AdvisorLogListItemResp struct {
 	AdvisorLogRespBase
-}
\ No newline at end of file
+}
+
+type AdvisorLogListResp = PageResp[AdvisorLogListItemResp]
+// ------------------------------
+// Revision history
+// ------------------------------
+
+type AdvisorLogRevisionResp struct {
+	Revision       int             `json:"revision"`
+	Action         string          `json:"action"` // create | baseline | update | restore
+	RestoredFrom   *int            `json:"restoredFrom,omitempty"`
+	EditorID       *uint           `json:"editorId"`
+	EditorName     string          `json:"editorName"`
+	ChangedFields  []string        `json:"changedFields"`
+	Title          string          `json:"title"`
+	Body           string          `json:"body"`
+	RequiresReport bool            `json:"requiresReport"`
+	PrivateNotes   *string         `json:"privateNotes,omitempty"`
+	Files          []FileAssetResp `json:"files"`
+	CreatedAt      string          `json:"createdAt"`
+}
+
+type AdvisorLogRevisionListResp = PageResp[AdvisorLogRevisionResp]
+
+type AdvisorLogFieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// AdvisorLogDiffLine บรรทัดของ body ที่เทียบกัน (op: equal | add | remove)
+type AdvisorLogDiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+type AdvisorLogRevisionDiffResp struct {
+	AdvisorLogID uint                    `json:"advisorLogId"`
+	From         int                     `json:"from"`
+	To           int                     `json:"to"`
+	Changes      []AdvisorLogFieldChange `json:"changes"`
+	BodyDiff     []AdvisorLogDiffLine    `json:"bodyDiff"`
+	FilesAdded   []FileAssetResp         `json:"filesAdded"`
+	FilesRemoved []FileAssetResp         `json:"filesRemoved"`
+}
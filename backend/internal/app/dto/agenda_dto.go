@@ -0,0 +1,24 @@
+package dto
+
+// AgendaItem รายการหนึ่งในปฏิทินส่วนตัว (เวลาเป็น RFC3339 ตามเวลาไทย)
+type AgendaItem struct {
+	// appointment | academic_event | unavailable | report_due
+	Type  string  `json:"type"`
+	ID    uint    `json:"id"` // id ของแหล่งข้อมูล (นัด, event, ช่วงไม่ว่าง, บันทึกการปรึกษา)
+	Title string  `json:"title"`
+	Start string  `json:"start"`
+	End   *string `json:"end,omitempty"` // กำหนดส่งรายงานไม่มีเวลาสิ้นสุด
+	// ทั้งวัน (วันหยุด/กิจกรรมที่ไม่มีเวลา)
+	AllDay bool `json:"all_day"`
+	// รายละเอียดเสริม เช่น ชื่อคู่นัด, ประเภท event, สถานะบันทึก
+	Detail string `json:"detail,omitempty"`
+	// นัดที่เกี่ยวข้อง (appointment, report_due)
+	AppointmentID *uint `json:"appointment_id,omitempty"`
+}
+
+// AgendaResp GET /api/me/agenda
+type AgendaResp struct {
+	From  string       `json:"from"` // YYYY-MM-DD
+	To    string       `json:"to"`   // YYYY-MM-DD (รวมวันนี้)
+	Items []AgendaItem `json:"items"`
+}
@@ -0,0 +1,88 @@
+package dto
+
+import (
+	"mime/multipart"
+	"time"
+
+	"backend/internal/app/entity"
+)
+
+// AppointmentRequestReq นักศึกษาขอนัดหมาย (multipart/form-data, แนบไฟล์ใน field "files" ได้)
+type AppointmentRequestReq struct {
+	// 0 = ใช้อาจารย์ที่ปรึกษาในโปรไฟล์นักศึกษา
+	AdvisorUserID uint   `form:"advisor_user_id" json:"advisor_user_id"`
+	TopicID       uint   `form:"topic_id" json:"topic_id" binding:"required"`
+	CategoryID    uint   `form:"category_id" json:"category_id" binding:"required"`
+	Description   string `form:"description" json:"description"`
+
+	// เวลาที่ขอ (RFC3339, ไม่บังคับ แต่ต้องส่งคู่กัน)
+	StartAt string `form:"start_at" json:"start_at"`
+	EndAt   string `form:"end_at" json:"end_at"`
+
+	Files []*multipart.FileHeader `form:"-" json:"-"`
+}
+
+// AppointmentChainItem นัดหนึ่งในสายนัดติดตามผล
+type AppointmentChainItem struct {
+	ID                  uint       `json:"id"`
+	ParentAppointmentID *uint      `json:"parent_appointment_id"`
+	SourceAdvisorLogID  *uint      `json:"source_advisor_log_id"`
+	StartAt             *time.Time `json:"start_at"`
+	EndAt               *time.Time `json:"end_at"`
+	StatusID            uint       `json:"status_id"`
+	StatusCode          string     `json:"status_code"`
+	StatusName          string     `json:"status_name"`
+	// < 0 = นัดก่อนหน้า, 0 = นัดนี้, > 0 = นัดติดตามผล
+	Depth int `json:"depth"`
+}
+
+// AppointmentDetailResp รายละเอียดนัด (ฟิลด์เดิมของ entity) + สายนัดติดตามผล เรียงจากนัดแรกสุด
+type AppointmentDetailResp struct {
+	*entity.Appointment
+	FollowUpChain []AppointmentChainItem `json:"follow_up_chain"`
+}
+
+// StudentAppointmentItem นัดหนึ่งรายการในมุมมองของนักศึกษาเจ้าของนัด
+type StudentAppointmentItem struct {
+	ID          uint   `json:"id"`
+	Topic       string `json:"topic"`
+	Category    string `json:"category"`
+	Description string `json:"description"`
+	AdvisorID   uint   `json:"advisor_user_id"`
+	AdvisorName string `json:"advisor_name"`
+	StatusCode  string `json:"status_code"`
+	StatusName  string `json:"status_name"`
+	// เวลาที่ขอ/ที่อาจารย์เสนอล่าสุด (nil = ยังไม่ระบุ)
+	StartAt             *time.Time `json:"start_at"`
+	EndAt               *time.Time `json:"end_at"`
+	ParentAppointmentID *uint      `json:"parent_appointment_id"`
+	HasAdvisorLog       bool       `json:"has_advisor_log"`
+	SubmittedAt         string     `json:"submitted_at"`
+	UpdatedAt           string     `json:"updated_at"`
+}
+
+// AppointmentTimelineItem การเปลี่ยนสถานะหนึ่งครั้ง (จาก StatusHistory)
+type AppointmentTimelineItem struct {
+	FromStatusCode string `json:"from_status_code"`
+	FromStatusName string `json:"from_status_name"`
+	ToStatusCode   string `json:"to_status_code"`
+	ToStatusName   string `json:"to_status_name"`
+	ActionCode     string `json:"action_code"`
+	ActionName     string `json:"action_name"`
+	ChangedByID    uint   `json:"changed_by_user_id"`
+	ChangedByName  string `json:"changed_by_name"`
+	ChangedByRole  string `json:"changed_by_role"`
+	Reason         string `json:"reason"`
+	ChangedAt      string `json:"changed_at"`
+}
+
+// StudentAppointmentListResp GET /api/appointments/mine
+type StudentAppointmentListResp = PageResp[StudentAppointmentItem]
+
+// StudentAppointmentDetailResp รายละเอียดนัด + timeline + บันทึกการปรึกษา (เฉพาะที่เผยแพร่แล้ว)
+type StudentAppointmentDetailResp struct {
+	StudentAppointmentItem
+	Attachments []FileAssetResp           `json:"attachments"`
+	Timeline    []AppointmentTimelineItem `json:"timeline"`
+	AdvisorLog  *AdvisorLogRespBase       `json:"advisor_log"`
+}
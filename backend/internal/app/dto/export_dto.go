@@ -0,0 +1,12 @@
+package dto
+
+import "time"
+
+// ExportFilter เงื่อนไขสำหรับ export (มาจาก query string)
+type ExportFilter struct {
+	From      *time.Time // รวมวันนี้
+	To        *time.Time // ไม่รวม (controller บวกไปแล้ว 1 วัน)
+	AdvisorID uint       // 0 = ไม่กรอง (appointments: admin เท่านั้น, advisor logs: กรองภายในขอบเขตผู้เรียก)
+	Status    string
+	Columns   string // เช่น "id,student_name,status"
+}
@@ -0,0 +1,12 @@
+package dto
+
+// FileAssetResp ข้อมูลไฟล์แนบที่ส่งให้ frontend (ไม่เปิดเผย path จริงบน server)
+type FileAssetResp struct {
+	ID         uint   `json:"id"`
+	Index      int    `json:"index"` // ใช้กับ /files/:index
+	Name       string `json:"name"`
+	MimeType   string `json:"mimeType"`
+	Size       int64  `json:"size"`
+	SHA256     string `json:"sha256"`
+	UploadedAt string `json:"uploadedAt"`
+}
@@ -0,0 +1,64 @@
+package dto
+
+import "backend/internal/app/entity"
+
+type LogTemplateFieldReq struct {
+	Key      string   `json:"key"`
+	Label    string   `json:"label"`
+	Type     string   `json:"type"` // text | textarea | select | multiselect | checkbox
+	Required bool     `json:"required"`
+	Options  []string `json:"options"`
+}
+
+type LogTemplateReq struct {
+	TopicID     uint                  `json:"topic_id" binding:"required"`
+	Name        string                `json:"name" binding:"required"`
+	Description string                `json:"description"`
+	IsActive    *bool                 `json:"is_active"` // ไม่ส่ง = true
+	Fields      []LogTemplateFieldReq `json:"fields"`
+}
+
+type LogTagReq struct {
+	Name        string `json:"name" binding:"required"`
+	Label       string `json:"label" binding:"required"`
+	Description string `json:"description"`
+	IsActive    *bool  `json:"is_active"`
+}
+
+type LogTagResp struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Label string `json:"label"`
+}
+
+// ตัวกรองรายการบันทึก
+type AdvisorLogListFilter struct {
+	Tags     []string // ต้องมีครบทุก tag
+	TopicID  uint
+	Status   string
+	From     string // YYYY-MM-DD (วันที่สร้างบันทึก)
+	To       string
+}
+
+type AdvisorLogTagStatsFilter struct {
+	From    string // YYYY-MM-DD (วันที่สร้างบันทึก)
+	To      string
+	TopicID uint
+}
+
+type LogTagStat struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Label string `json:"label"`
+	Count int64  `json:"count"`
+}
+
+type LogTagStatsResp struct {
+	TotalLogs int64        `json:"totalLogs"`
+	Untagged  int64        `json:"untagged"`
+	Tags      []LogTagStat `json:"tags"`
+}
+
+// รายการแบบฟอร์ม/tag แบบแบ่งหน้า
+type LogTemplateListResp = PageResp[entity.LogTemplate]
+type LogTagListResp = PageResp[entity.LogTag]
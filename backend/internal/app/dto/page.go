@@ -0,0 +1,23 @@
+package dto
+
+// PageMeta ข้อมูลการแบ่งหน้าที่ทุก list endpoint ส่งกลับ
+type PageMeta struct {
+	Total    int64 `json:"total"`
+	Page     int   `json:"page"`
+	PageSize int   `json:"page_size"`
+	// มีค่าเฉพาะโหมด cursor และยังมีหน้าถัดไป
+	NextCursor *string `json:"next_cursor,omitempty"`
+}
+
+// PageResp ซองข้อมูลมาตรฐานของ list endpoint: {data, total, page, page_size, next_cursor}
+type PageResp[T any] struct {
+	Data []T `json:"data"`
+	PageMeta
+}
+
+func NewPageResp[T any](data []T, meta PageMeta) PageResp[T] {
+	if data == nil {
+		data = []T{}
+	}
+	return PageResp[T]{Data: data, PageMeta: meta}
+}
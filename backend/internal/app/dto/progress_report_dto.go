@@ -0,0 +1,83 @@
+package dto
+
+import "mime/multipart"
+
+// ProgressReportCreateReq รับได้ทั้ง multipart/form-data และ JSON (ไม่มีไฟล์)
+type ProgressReportCreateReq struct {
+	AdvisorLogID uint                    `form:"advisor_logs_id" json:"advisor_logs_id" binding:"required"`
+	Body         string                  `form:"body" json:"body" binding:"required"`
+	Files        []*multipart.FileHeader `form:"files" json:"-"`
+}
+
+// ReportReviewReq decision: approve | request_revision (ขอแก้ต้องมี comment)
+type ReportReviewReq struct {
+	Decision string `json:"decision" binding:"required"`
+	Comment  string `json:"comment"`
+}
+
+// ReportFeedbackCreateReq รับได้ทั้ง JSON และ multipart/form-data (แนบไฟล์ได้)
+type ReportFeedbackCreateReq struct {
+	ProgressReportID uint                    `form:"progressReportsId" json:"progressReportsId" binding:"required"`
+	ParentID         *uint                   `form:"parentId" json:"parentId"` // ตอบกลับ feedback นี้
+	Body             string                  `form:"body" json:"body" binding:"required"`
+	Files            []*multipart.FileHeader `form:"files" json:"-"`
+}
+
+type ReportFeedbackUpdateReq struct {
+	Body string `json:"body" binding:"required"`
+}
+
+type ReportFeedbackResp struct {
+	ID           uint                 `json:"id"`
+	ParentID     *uint                `json:"parentId"`
+	AuthorUserID *uint                `json:"authorUserId"`
+	AuthorName   string               `json:"authorName"`
+	AuthorRole   string               `json:"authorRole"` // student | advisor (ตามบทบาทในนัดนี้)
+	Body         string               `json:"body"`
+	Outcome      string               `json:"outcome"` // comment | approve | request_revision
+	Files        []FileAssetResp      `json:"files"`
+	CanEdit      bool                 `json:"canEdit"`
+	CreatedAt    string               `json:"createdAt"`
+	EditedAt     *string              `json:"editedAt"`
+	Replies      []ReportFeedbackResp `json:"replies"`
+}
+
+type ProgressReportResp struct {
+	ID           uint                 `json:"id"`
+	AdvisorLogID uint                 `json:"advisorLogId"`
+	Body         string               `json:"body"`
+	Status       string               `json:"status"` // Submitted | Approved | RevisionRequested
+	Version      int                  `json:"version"`
+	PreviousID   *uint                `json:"previousId"`
+	SubmittedAt  string               `json:"submittedAt"`
+	ReviewedAt   *string              `json:"reviewedAt"`
+	ReviewedByID *uint                `json:"reviewedById"`
+	Files        []FileAssetResp      `json:"files"`
+	Feedbacks    []ReportFeedbackResp `json:"feedbacks"` // หัวข้อ (ตอบกลับซ้อนอยู่ใน replies)
+}
+
+// ReportDueReq dueAt เป็น YYYY-MM-DD (สิ้นวันเวลาไทย) หรือ RFC3339, null = ยกเลิกกำหนดส่ง
+type ReportDueReq struct {
+	DueAt *string `json:"dueAt"`
+}
+
+type ReportDueResp struct {
+	AdvisorLogID uint    `json:"advisorLogId"`
+	DueAt        *string `json:"dueAt"`
+}
+
+// OverdueReportResp บันทึกที่นักศึกษายังไม่ส่งรายงานและเลยกำหนดแล้ว
+type OverdueReportResp struct {
+	AdvisorLogID  uint   `json:"advisorLogId"`
+	AppointmentID uint   `json:"appointmentId"`
+	Title         string `json:"title"`
+	StudentUserID uint   `json:"studentUserId"`
+	StudentSutID  string `json:"studentSutId"`
+	StudentName   string `json:"studentName"`
+	DueAt         string `json:"dueAt"`
+	OverdueDays   int    `json:"overdueDays"`
+	// ขั้นการแจ้งเตือนล่าสุดของกำหนดส่งนี้: "" | student | advisor | admin
+	EscalationStep string `json:"escalationStep"`
+}
+
+type OverdueReportListResp = PageResp[OverdueReportResp]
@@ -0,0 +1,20 @@
+package dto
+
+// SearchResult ผลค้นหาหนึ่งรายการ (type บอกว่าเป็นอะไร ใช้ id ไปเรียก endpoint ของประเภทนั้นต่อ)
+type SearchResult struct {
+	Type    string `json:"type"` // user | advisor_log | faq | report
+	ID      uint   `json:"id"`
+	Title   string `json:"title"`
+	Snippet string `json:"snippet"`
+	// สถานะ/บทบาทของรายการ (เช่น Completed, Unhide, Advisor)
+	Status string  `json:"status,omitempty"`
+	Score  float64 `json:"score"`
+}
+
+// SearchResp GET /api/search
+type SearchResp struct {
+	Query   string         `json:"q"`
+	Results []SearchResult `json:"results"`
+	// จำนวนที่พบต่อประเภท (ไม่เกิน limit)
+	Counts map[string]int `json:"counts"`
+}
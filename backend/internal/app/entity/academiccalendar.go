@@ -20,6 +20,11 @@ type AcademicCalendar struct {
 	EndDateTime   time.Time `json:"end_date_time" valid:"required~กรุณาระบุวันเวลาสิ้นสุด"`
 
 	IsHoliday bool   `json:"is_holiday"`
+	// RRULE (RFC 5545) เช่น FREQ=WEEKLY;BYDAY=MO;UNTIL=20251231 ว่าง = ไม่ซ้ำ
+	// StartDateTime/EndDateTime คือ occurrence แรก
+	RRule     string `json:"rrule" gorm:"type:varchar(255)"`
+	// SEQUENCE ของ iCalendar (เพิ่มทุกครั้งที่แก้ไข event)
+	Sequence  int    `json:"sequence" gorm:"not null;default:0"`
 	AdminID   uint   `json:"admin_id"`
 	User      User   `json:"user" gorm:"foreignKey:AdminID" valid:"-"`
 }
@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"time"
+)
+
+// ชนิดของการเปลี่ยนแปลงที่ทำให้เกิด revision
+const (
+	RevisionActionCreate   = "create"
+	RevisionActionBaseline = "baseline" // log เก่าที่สร้างก่อนมีระบบ revision (บันทึกตอนแก้ไขครั้งแรก)
+	RevisionActionUpdate   = "update"
+	RevisionActionRestore  = "restore"
+)
+
+// AdvisorLogRevision สำเนาเนื้อหาบันทึก ณ แต่ละครั้งที่สร้าง/แก้ไข/กู้คืน
+// เพิ่มได้อย่างเดียว ไม่มีการแก้ไขหรือลบ (จึงไม่ใช้ gorm.Model ที่มี soft delete)
+type AdvisorLogRevision struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	AdvisorLogID uint `gorm:"not null;uniqueIndex:idx_advisor_log_revision" json:"advisor_log_id"`
+	Revision     int  `gorm:"not null;uniqueIndex:idx_advisor_log_revision" json:"revision"`
+
+	Action       string `gorm:"type:varchar(20);not null" json:"action"`
+	RestoredFrom *int   `json:"restored_from"`
+
+	// nil = ไม่ทราบผู้แก้ (baseline ของ log เก่า)
+	EditorUserID *uint `gorm:"index" json:"editor_user_id"`
+	Editor       *User `gorm:"foreignKey:EditorUserID" json:"-"`
+
+	Title          string `gorm:"type:varchar(255)" json:"title"`
+	Body           string `gorm:"type:text" json:"body"`
+	RequiresReport bool   `gorm:"not null;default:false" json:"requires_report"`
+	PrivateNotes   string `gorm:"type:text" json:"-"`
+
+	// ชื่อฟิลด์ที่เปลี่ยนจาก revision ก่อนหน้า คั่นด้วย comma (title,body,requires_report,private_notes,files)
+	ChangedFields string `gorm:"type:varchar(255)" json:"changed_fields"`
+
+	Files []AdvisorLogRevisionFile `gorm:"foreignKey:RevisionID;constraint:OnDelete:CASCADE" json:"files"`
+}
+
+// AdvisorLogRevisionFile ไฟล์แนบของ revision (อ้างถึงแถว file_assets ซึ่งอาจถูก soft delete ไปแล้วเมื่อถูกแทนที่)
+type AdvisorLogRevisionFile struct {
+	ID           uint        `gorm:"primaryKey" json:"id"`
+	RevisionID   uint        `gorm:"not null;index" json:"revision_id"`
+	FileAssetsID uint        `gorm:"not null;index" json:"file_assets_id"`
+	Position     int         `gorm:"not null;default:0" json:"position"`
+	FileAssets   *FileAssets `gorm:"foreignKey:FileAssetsID" json:"file_assets"`
+}
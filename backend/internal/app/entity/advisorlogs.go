@@ -1,6 +1,8 @@
 package entity
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 )
 
@@ -12,8 +14,22 @@ type AdvisorLog struct {
 	Body           string `gorm:"type:text" valid:"required~body is required"`
 	Status         string `gorm:"type:varchar(50)" valid:"required~status is required"`
 	RequiresReport bool   `gorm:"not null;default:false"`
-	FileName string `gorm:"type:text"`
-	FilePath string `gorm:"type:text"`
+	// กำหนดส่งรายงานความคืบหน้า (อาจารย์เป็นผู้ตั้ง) nil = ไม่กำหนด
+	ReportDueAt *time.Time `gorm:"index"`
+
+	// บันทึกส่วนตัวของอาจารย์ ห้ามส่งให้นักศึกษา (ไม่ serialize ตรง ๆ ให้ service เป็นผู้ตัดสินว่าใครเห็น)
+	PrivateNotes string `gorm:"type:text" json:"-"`
+
+	// แบบฟอร์มที่ใช้ตอนสร้างบันทึก (nil = เขียนแบบอิสระ) และค่าที่กรอก (key ตาม LogTemplateField.Key)
+	TemplateID     *uint          `gorm:"index"`
+	Template       *LogTemplate   `gorm:"foreignKey:TemplateID" valid:"-"`
+	TemplateValues map[string]any `gorm:"type:jsonb;serializer:json" valid:"-"`
+
+	Tags []LogTag `gorm:"many2many:advisor_log_tags;" valid:"-"`
+
+	// ไฟล์แนบ (1 แถวต่อไฟล์ เรียงตาม Position)
+	Files []FileAssets `gorm:"polymorphic:Owner;polymorphicValue:advisor_log" valid:"-"`
 
 	Appointment *Appointment `gorm:"foreignKey:AppointmentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
-}
\ No newline at end of file
+	ProgressReports []ProgressReport `gorm:"foreignKey:AdvisorLogsID" valid:"-"`
+}
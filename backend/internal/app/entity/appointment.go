@@ -1,10 +1,16 @@
 package entity
 import ("gorm.io/gorm"
+    "time"
 )
 type Appointment struct {
 	gorm.Model
     Description string `gorm:"type:text" json:"description"`
 
+    // เวลานัดจริง (ตั้งตอนเสนอเวลาใหม่) ใช้สร้างไฟล์ .ics
+    StartAt  *time.Time `json:"start_at"`
+    EndAt    *time.Time `json:"end_at"`
+    // SEQUENCE ของ iCalendar: เพิ่มทุกครั้งที่สถานะ/เวลาเปลี่ยน ให้ปฏิทินอัปเดตแทนการสร้างซ้ำ
+    Sequence int        `gorm:"not null;default:0" json:"sequence"`
 
     AdvisorUserID       uint                `json:"advisor_user_id"`
     AdvisorUser         User                `gorm:"foreignKey:AdvisorUserID" json:"advisor_user"`
@@ -17,4 +23,11 @@ type Appointment struct {
     AppointmentStatusID uint                `json:"appointment_status_id"`
     AppointmentStatus   AppointmentStatus   `gorm:"foreignKey:AppointmentStatusID"`
     AdvisorLog *AdvisorLog `gorm:"foreignKey:AppointmentID" json:"advisor_log"`
+
+    // นัดติดตามผล: นัดต้นทาง และบันทึกการปรึกษาที่สั่งนัดนี้ (nil = นัดปกติ)
+    ParentAppointmentID *uint `gorm:"index" json:"parent_appointment_id"`
+    SourceAdvisorLogID  *uint `gorm:"index" json:"source_advisor_log_id"`
+
+    // ไฟล์ที่นักศึกษาแนบตอนขอนัด (ทรานสคริปต์, เอกสารประกอบ)
+    Attachments []AppointmentAttachment `gorm:"foreignKey:AppointmentID" json:"attachments"`
 }
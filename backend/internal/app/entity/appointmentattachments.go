@@ -7,9 +7,9 @@ import (
 type AppointmentAttachment struct {
 	gorm.Model
 
-	FileAssetsID uint        `json:"file_assets_id"`
+	FileAssetsID uint        `json:"file_assets_id" gorm:"index"`
 	FileAssets   *FileAssets `json:"file_assets" gorm:"foreignKey:FileAssetsID"`
 
-	AppointmentID uint         `json:"appointment_id"`
-	Appointment   *Appointment `json:"appointment" gorm:"foreignKey:AppointmentID"`
+	AppointmentID uint         `json:"appointment_id" gorm:"index"`
+	Appointment   *Appointment `json:"appointment,omitempty" gorm:"foreignKey:AppointmentID"`
 }
@@ -1,5 +1,12 @@
 package entity
 import ("gorm.io/gorm")
+
+const (
+    ActionApproveID    uint = 1 // อนุมัตินัด
+    ActionRescheduleID uint = 2 // เสนอเวลาใหม่
+    ActionFollowUpID   uint = 3 // อาจารย์สร้างนัดติดตามผล (อนุมัติทันที)
+)
+
 type ApprovalAction struct {
 	gorm.Model
     
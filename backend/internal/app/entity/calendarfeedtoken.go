@@ -0,0 +1,14 @@
+package entity
+
+import "gorm.io/gorm"
+
+// CalendarFeedToken token ลับสำหรับ subscribe ปฏิทิน (.ics) จาก Google/Outlook โดยไม่ต้อง login
+// 1 user มีได้ 1 token (rotate ได้ถ้าหลุด)
+type CalendarFeedToken struct {
+	gorm.Model
+
+	UserID uint  `json:"user_id" gorm:"not null;uniqueIndex"`
+	User   *User `json:"-" gorm:"foreignKey:UserID"`
+
+	Token string `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"`
+}
@@ -4,10 +4,32 @@ import (
 	"gorm.io/gorm"
 )
 
+// เจ้าของไฟล์ (OwnerType) ที่ระบบรองรับ
+const (
+	FileOwnerAdvisorLog     = "advisor_log"
+	FileOwnerProgressReport = "progress_report"
+	FileOwnerReport         = "report"
+	FileOwnerProfileImage   = "profile_image"
+	FileOwnerAppointment    = "appointment"
+	FileOwnerReportFeedback = "report_feedback"
+)
+
+// FileAssets ไฟล์ที่อัปโหลด 1 ไฟล์ต่อ 1 แถว
+// ผูกกับเจ้าของด้วย OwnerType + OwnerID (polymorphic) และเรียงลำดับด้วย Position
 type FileAssets struct {
 	gorm.Model
 
-	OriginalName   uint   `gorm:"primaryKey;autoIncrement;type:int" json:"original_name"`
-	MimeType string `gorm:"type:varchar(100);not null" json:"mime_type"`
-	FStoragePath		string `gorm:"type:text" json:"storage_path"`
-}
\ No newline at end of file
+	OriginalName string `gorm:"type:varchar(255);not null" json:"original_name"`
+	MimeType     string `gorm:"type:varchar(100);not null" json:"mime_type"`
+	Size         int64  `gorm:"not null;default:0" json:"size"`
+	SHA256       string `gorm:"column:sha256;type:varchar(64);index" json:"sha256"`
+	StoragePath  string `gorm:"type:text;not null" json:"-"`
+
+	// nil = ไม่ทราบผู้อัปโหลด (ข้อมูลเก่าที่ย้ายมาจาก CSV)
+	UploaderID *uint `gorm:"index" json:"uploader_id"`
+	Uploader   *User `gorm:"foreignKey:UploaderID" json:"-"`
+
+	OwnerType string `gorm:"type:varchar(50);index:idx_file_assets_owner" json:"owner_type"`
+	OwnerID   uint   `gorm:"index:idx_file_assets_owner" json:"owner_id"`
+	Position  int    `gorm:"not null;default:0" json:"position"`
+}
@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"gorm.io/gorm"
+)
+
+// ชนิดของช่องในแบบฟอร์มบันทึก
+const (
+	TemplateFieldText        = "text"
+	TemplateFieldTextarea    = "textarea"
+	TemplateFieldSelect      = "select"
+	TemplateFieldMultiSelect = "multiselect"
+	TemplateFieldCheckbox    = "checkbox"
+)
+
+// LogTemplate แบบฟอร์มบันทึกการให้คำปรึกษาของแต่ละหัวข้อนัด (admin เป็นผู้กำหนด)
+// หัวข้อหนึ่งมีแบบฟอร์มที่ใช้งานอยู่ได้ทีละ 1 อัน
+type LogTemplate struct {
+	gorm.Model
+
+	TopicID     uint              `gorm:"not null;index" json:"topic_id" valid:"required~topicId is required"`
+	Topic       *AppointmentTopic `gorm:"foreignKey:TopicID" json:"topic,omitempty" valid:"-"`
+	Name        string            `gorm:"type:varchar(150);not null" json:"name" valid:"required~name is required"`
+	Description string            `gorm:"type:text" json:"description"`
+	IsActive    bool              `gorm:"not null;default:true" json:"is_active"`
+
+	Fields []LogTemplateField `gorm:"foreignKey:TemplateID;constraint:OnDelete:CASCADE" json:"fields" valid:"-"`
+}
+
+// LogTemplateField ช่องหนึ่งในแบบฟอร์ม (Key ใช้เป็นชื่อฟิลด์ใน AdvisorLog.TemplateValues)
+type LogTemplateField struct {
+	ID         uint     `gorm:"primaryKey" json:"id"`
+	TemplateID uint     `gorm:"not null;index" json:"template_id"`
+	Key        string   `gorm:"type:varchar(50);not null" json:"key"`
+	Label      string   `gorm:"type:varchar(150);not null" json:"label"`
+	Type       string   `gorm:"type:varchar(20);not null" json:"type"`
+	Required   bool     `gorm:"not null;default:false" json:"required"`
+	Options    []string `gorm:"type:jsonb;serializer:json" json:"options"` // ใช้กับ select / multiselect
+	Position   int      `gorm:"not null;default:0" json:"position"`
+}
+
+// LogTag คำศัพท์ควบคุม (controlled vocabulary) สำหรับติดป้ายบันทึก
+// ไม่ลบจริง ปิดใช้งาน (IsActive=false) แทน เพื่อให้บันทึกเก่ายังนับสถิติได้
+type LogTag struct {
+	gorm.Model
+
+	Name        string `gorm:"type:varchar(50);not null;uniqueIndex" json:"name" valid:"required~name is required,matches(^[a-z0-9][a-z0-9_-]*$)~name must be lowercase letters, digits, - or _"`
+	Label       string `gorm:"type:varchar(100);not null" json:"label" valid:"required~label is required"`
+	Description string `gorm:"type:text" json:"description"`
+	IsActive    bool   `gorm:"not null;default:true" json:"is_active"`
+}
@@ -12,10 +12,11 @@ type NotificationStatusSnapshot string
 
 // ENUM VALUE
 const (
-    EventApproved    NotificationEventType = "APPROVED"
-    EventRescheduled NotificationEventType = "RESCHEDULED"
-    EventFollowup    NotificationEventType = "FOLLOWUP"
-    EventUniEvent    NotificationEventType = "UNIEVENT"
+    EventApproved      NotificationEventType = "APPROVED"
+    EventRescheduled   NotificationEventType = "RESCHEDULED"
+    EventFollowup      NotificationEventType = "FOLLOWUP"
+    EventUniEvent      NotificationEventType = "UNIEVENT"
+    EventReportOverdue NotificationEventType = "REPORT_OVERDUE"
 )
 
 const (
@@ -6,13 +6,27 @@ import (
 	"gorm.io/gorm"
 )
 
+// สถานะรายงานความคืบหน้า
+const (
+	ProgressReportSubmitted         = "Submitted"
+	ProgressReportApproved          = "Approved"
+	ProgressReportRevisionRequested = "RevisionRequested"
+)
+
 type ProgressReport struct {
 	gorm.Model
 	Body string `gorm:"type:text" valid:"required~Body is required"`
 	Status string `gorm:"type:varchar(50)" valid:"required~Status is required"`
-	AdvisorLogsID uint `gorm:"not null" valid:"required~AdvisorLogsID is required"`
+	AdvisorLogsID uint `gorm:"not null;index" valid:"required~AdvisorLogsID is required"`
 	SubmittedAt time.Time
-	FileName string `gorm:"type:varchar(255)"`
-	FilePath string `gorm:"type:varchar(255)"`
+
+	// ส่งใหม่หลังถูกขอแก้ = version ถัดไป ชี้กลับไปฉบับก่อนหน้า
+	Version    int             `gorm:"not null;default:1"`
+	PreviousID *uint           `gorm:"index"`
+	Previous   *ProgressReport `gorm:"foreignKey:PreviousID" json:"-" valid:"-"`
+
+	ReviewedAt   *time.Time
+	ReviewedByID *uint
+	Files []FileAssets `gorm:"polymorphic:Owner;polymorphicValue:progress_report" valid:"-"`
 	Feedbacks []ReportFeedback `gorm:"foreignKey:ProgressReportsID"`
-}
\ No newline at end of file
+}
@@ -0,0 +1,25 @@
+package entity
+
+import "time"
+
+// ขั้นการแจ้งเตือนรายงานความคืบหน้าที่เลยกำหนด (เรียงตามลำดับ)
+const (
+	EscalationStudent = "student"
+	EscalationAdvisor = "advisor"
+	EscalationAdmin   = "admin"
+)
+
+// ReportEscalation บันทึกว่าแจ้งเตือนขั้นไหนของบันทึกไปแล้ว (ต่อกำหนดส่ง 1 ค่า)
+// unique (บันทึก, กำหนดส่ง, ขั้น) กันการแจ้งซ้ำ แม้ job จะรันซ้อนกันหลาย instance
+// ถ้าอาจารย์เลื่อนกำหนดส่ง ขั้นต่าง ๆ จะเริ่มนับใหม่ตามกำหนดใหม่
+type ReportEscalation struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+
+	AdvisorLogID uint      `gorm:"not null;uniqueIndex:idx_report_escalation_step"`
+	DueAt        time.Time `gorm:"not null;uniqueIndex:idx_report_escalation_step"`
+	Step         string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_report_escalation_step"`
+
+	// จำนวนผู้รับที่สร้าง notification ให้ (0 = ไม่พบผู้รับ เช่นหน่วยงานไม่มี admin)
+	Recipients int `gorm:"not null;default:0"`
+}
@@ -1,11 +1,33 @@
 package entity
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 )
 
+// ผลของ feedback (comment = ความเห็นทั่วไป ไม่เปลี่ยนสถานะ)
+const (
+	FeedbackComment         = "comment"
+	FeedbackApprove         = "approve"
+	FeedbackRequestRevision = "request_revision"
+)
+
 type ReportFeedback struct {
 	gorm.Model
-	ProgressReportsID uint `gorm:"not null" valid:"required~ProgressReportsID is required"`
+	ProgressReportsID uint `gorm:"not null;index" valid:"required~ProgressReportsID is required"`
 	Body string `gorm:"type:text" valid:"required~Body is required"`
-}
\ No newline at end of file
+	Outcome string `gorm:"type:varchar(30);not null;default:comment"`
+
+	// ผู้เขียน (nil = feedback เก่าที่ยังไม่ได้เก็บผู้เขียน)
+	AuthorUserID *uint `gorm:"index"`
+	Author       *User `gorm:"foreignKey:AuthorUserID" valid:"-"`
+
+	// ตอบกลับ feedback อื่นของรายงานเดียวกัน (nil = เริ่มหัวข้อใหม่)
+	ParentID *uint `gorm:"index"`
+
+	// แก้ไขล่าสุด (แก้ได้ภายในช่วงเวลาหลังโพสต์เท่านั้น)
+	EditedAt *time.Time
+
+	Files []FileAssets `gorm:"polymorphic:Owner;polymorphicValue:report_feedback" valid:"-"`
+}
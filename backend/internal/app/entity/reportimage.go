@@ -7,8 +7,8 @@ import (
 type ReportImage struct {
 	gorm.Model
 
-	FileURL  string `json:"file_url" gorm:"type:varchar(255);not null"`
-	FileType string `json:"file_type" gorm:"type:varchar(50);not null"`
+	FileAssetsID uint        `json:"file_assets_id" gorm:"index"`
+	FileAssets   *FileAssets `json:"file_assets" gorm:"foreignKey:FileAssetsID"`
 
 	ReportID uint     `json:"report_id"`
 	Report   *Report  `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
@@ -0,0 +1,80 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	"gorm.io/gorm"
+)
+
+// Semester ภาคการศึกษา (วันที่ทั้งหมดเก็บเป็น 00:00 เวลาไทย และรวมวันสุดท้าย)
+type Semester struct {
+	gorm.Model
+
+	Name         string `json:"name" gorm:"type:varchar(100);not null" valid:"required~กรุณาระบุชื่อภาคการศึกษา,maxstringlength(100)~ชื่อภาคการศึกษายาวเกินไป"`
+	// ปี/เทอมซ้ำได้เฉพาะแถวที่ลบแล้ว (soft delete) ลบภาคแล้วสร้างปี/เทอมเดิมใหม่ได้
+	AcademicYear int `json:"academic_year" gorm:"not null;uniqueIndex:idx_semester_year_term,where:deleted_at IS NULL"` // ปีการศึกษา (พ.ศ.) เช่น 2568
+	TermNumber   int `json:"term_number" gorm:"not null;uniqueIndex:idx_semester_year_term,where:deleted_at IS NULL"`   // 1, 2, 3 (ภาคฤดูร้อน)
+
+	StartDate time.Time `json:"start_date" valid:"required~กรุณาระบุวันเปิดภาค"`
+	EndDate   time.Time `json:"end_date" valid:"required~กรุณาระบุวันปิดภาค"`
+
+	RegistrationStart *time.Time `json:"registration_start"`
+	RegistrationEnd   *time.Time `json:"registration_end"`
+	MidtermExamStart  *time.Time `json:"midterm_exam_start"`
+	MidtermExamEnd    *time.Time `json:"midterm_exam_end"`
+	FinalExamStart    *time.Time `json:"final_exam_start"`
+	FinalExamEnd      *time.Time `json:"final_exam_end"`
+
+	AdminID uint `json:"admin_id"`
+	User    User `json:"user" gorm:"foreignKey:AdminID" valid:"-"`
+}
+
+func validateWindow(start, end *time.Time, name string) error {
+	if (start == nil) != (end == nil) {
+		return errors.New("กรุณาระบุวันเริ่มและวันสิ้นสุดของช่วง" + name + "ให้ครบ")
+	}
+	if start != nil && end.Before(*start) {
+		return errors.New("วันสิ้นสุดของช่วง" + name + " ต้องไม่อยู่ก่อนวันเริ่ม")
+	}
+	return nil
+}
+
+func (s *Semester) Validate() error {
+	if _, err := govalidator.ValidateStruct(s); err != nil {
+		return err
+	}
+	if s.AcademicYear < 2500 || s.AcademicYear > 2700 {
+		return errors.New("ปีการศึกษาต้องเป็นปี พ.ศ. เช่น 2568")
+	}
+	if s.TermNumber < 1 || s.TermNumber > 3 {
+		return errors.New("ภาคการศึกษาต้องเป็น 1, 2 หรือ 3")
+	}
+	if s.EndDate.Before(s.StartDate) {
+		return errors.New("วันปิดภาค ต้องอยู่หลังวันเปิดภาค")
+	}
+	if err := validateWindow(s.RegistrationStart, s.RegistrationEnd, "ลงทะเบียน"); err != nil {
+		return err
+	}
+	if err := validateWindow(s.MidtermExamStart, s.MidtermExamEnd, "สอบกลางภาค"); err != nil {
+		return err
+	}
+	if err := validateWindow(s.FinalExamStart, s.FinalExamEnd, "สอบปลายภาค"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Contains วันที่ d (ตัดเวลาแล้ว) อยู่ในภาคการศึกษานี้หรือไม่
+func (s *Semester) Contains(d time.Time) bool {
+	return !d.Before(s.StartDate) && !d.After(s.EndDate)
+}
+
+// InExamPeriod วันที่ d อยู่ในช่วงสอบกลางภาค/ปลายภาคหรือไม่
+func (s *Semester) InExamPeriod(d time.Time) bool {
+	in := func(start, end *time.Time) bool {
+		return start != nil && end != nil && !d.Before(*start) && !d.After(*end)
+	}
+	return in(s.MidtermExamStart, s.MidtermExamEnd) || in(s.FinalExamStart, s.FinalExamEnd)
+}
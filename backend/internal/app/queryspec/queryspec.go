@@ -0,0 +1,252 @@
+package queryspec
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"backend/internal/app/dto"
+)
+
+// รูปแบบ query ของ endpoint ที่คืนรายการ (ใช้ร่วมกันทุก list):
+//   ?page=2&page_size=20                แบ่งหน้าแบบ offset
+//   ?cursor=&page_size=20               แบ่งหน้าแบบ cursor (หน้าแรกส่งค่าว่าง ถัดไปใช้ next_cursor, เรียงตาม id เท่านั้น)
+//   ?sort=-created_at,name              เรียงตามฟิลด์ที่ endpoint อนุญาต (- = มากไปน้อย)
+//   ?status=active                      ตัวกรองตามชื่อที่ endpoint ประกาศไว้
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+var (
+	ErrInvalidPage   = errors.New("page and page_size must be positive integers")
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrCursorSort    = errors.New("cursor paging only supports sort=id or sort=-id")
+)
+
+// SortError ฟิลด์เรียงที่ endpoint ไม่อนุญาต
+type SortError struct {
+	Field   string
+	Allowed []string
+}
+
+func (e *SortError) Error() string {
+	return fmt.Sprintf("cannot sort by %q (allowed: %s)", e.Field, strings.Join(e.Allowed, ", "))
+}
+
+// Options สิ่งที่ endpoint หนึ่งอนุญาต
+type Options struct {
+	// ชื่อฟิลด์ใน ?sort= → คอลัมน์จริง
+	Sorts map[string]string
+	// ค่าเริ่มต้น เช่น "-created_at" (ว่าง = id มากไปน้อย)
+	DefaultSort string
+	// ชื่อ query ที่ใช้เป็นตัวกรองได้
+	Filters []string
+	// คอลัมน์ id ที่ใช้กับ cursor (ว่าง = "id") ใส่ชื่อตารางเมื่อ query มี JOIN
+	IDColumn string
+	// 0 = DefaultPageSize
+	PageSize int
+}
+
+type Order struct {
+	Column string
+	Desc   bool
+}
+
+// Spec ผลการอ่าน query string แล้ว (ผ่าน whitelist แล้วทั้งหมด)
+type Spec struct {
+	Page     int
+	PageSize int
+	// > 0 = โหมด cursor (id ของแถวสุดท้ายในหน้าก่อน)
+	After   uint
+	Cursor  bool
+	Orders  []Order
+	Filters map[string]string
+
+	idColumn string
+}
+
+// Filter ค่าตัวกรอง (ว่าง = ไม่ได้ส่งมา)
+func (s Spec) Filter(name string) string {
+	return s.Filters[name]
+}
+
+// FilterUint ตัวกรองที่เป็น id (0 = ไม่ได้ส่งมา)
+func (s Spec) FilterUint(name string) (uint, error) {
+	v := s.Filters[name]
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(v, 10, 64)
+	if err != nil || n == 0 {
+		return 0, fmt.Errorf("invalid %s", name)
+	}
+	return uint(n), nil
+}
+
+func positive(v string, def int) (int, error) {
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		return 0, ErrInvalidPage
+	}
+	return n, nil
+}
+
+func sortNames(m map[string]string) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func parseSort(raw string, opt Options) ([]Order, error) {
+	var out []Order
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		desc := strings.HasPrefix(part, "-")
+		name := strings.TrimPrefix(part, "-")
+		col, ok := opt.Sorts[name]
+		if !ok {
+			return nil, &SortError{Field: name, Allowed: sortNames(opt.Sorts)}
+		}
+		out = append(out, Order{Column: col, Desc: desc})
+	}
+	return out, nil
+}
+
+// EncodeCursor / DecodeCursor cursor เป็น id ที่เข้ารหัสไว้ (client ไม่ควรสร้างเอง)
+func EncodeCursor(id uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(uint64(id), 10)))
+}
+
+func DecodeCursor(c string) (uint, error) {
+	b, err := base64.RawURLEncoding.DecodeString(c)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	n, err := strconv.ParseUint(string(b), 10, 64)
+	if err != nil || n == 0 {
+		return 0, ErrInvalidCursor
+	}
+	return uint(n), nil
+}
+
+// Parse อ่าน page/page_size/cursor/sort/ตัวกรอง ตาม Options ของ endpoint
+func Parse(q url.Values, opt Options) (Spec, error) {
+	def := opt.PageSize
+	if def <= 0 {
+		def = DefaultPageSize
+	}
+	spec := Spec{Filters: map[string]string{}, idColumn: opt.IDColumn}
+	if spec.idColumn == "" {
+		spec.idColumn = "id"
+	}
+
+	var err error
+	if spec.Page, err = positive(q.Get("page"), 1); err != nil {
+		return spec, err
+	}
+	if spec.PageSize, err = positive(q.Get("page_size"), def); err != nil {
+		return spec, err
+	}
+	if spec.PageSize > MaxPageSize {
+		spec.PageSize = MaxPageSize
+	}
+
+	raw := q.Get("sort")
+	if raw == "" {
+		raw = opt.DefaultSort
+	}
+	if spec.Orders, err = parseSort(raw, opt); err != nil {
+		return spec, err
+	}
+
+	if q.Has("cursor") {
+		c := q.Get("cursor")
+		if q.Get("sort") == "" {
+			// cursor ใช้ลำดับตาม id เสมอ (ไม่สนค่าเริ่มต้นของ endpoint)
+			spec.Orders = []Order{{Column: spec.idColumn, Desc: true}}
+		} else if len(spec.Orders) != 1 || spec.Orders[0].Column != spec.idColumn {
+			return spec, ErrCursorSort
+		}
+		spec.Cursor = true
+		spec.Page = 1
+		if c != "" {
+			if spec.After, err = DecodeCursor(c); err != nil {
+				return spec, err
+			}
+		}
+	}
+
+	for _, name := range opt.Filters {
+		if v := strings.TrimSpace(q.Get(name)); v != "" {
+			spec.Filters[name] = v
+		}
+	}
+	return spec, nil
+}
+
+// ToOrder คอลัมน์เรียงในรูป SQL (ตามด้วย id เพื่อให้ลำดับคงที่ระหว่างหน้า)
+func (s Spec) ToOrder() string {
+	parts := make([]string, 0, len(s.Orders)+1)
+	hasID := false
+	for _, o := range s.Orders {
+		dir := " asc"
+		if o.Desc {
+			dir = " desc"
+		}
+		parts = append(parts, o.Column+dir)
+		hasID = hasID || o.Column == s.idColumn
+	}
+	if !hasID {
+		parts = append(parts, s.idColumn+" desc")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Find นับทั้งหมดแล้วดึงหน้าที่ขอ (q ต้องมีตัวกรองครบแล้ว แต่ยังไม่เรียง/ไม่ limit)
+// idOf ใช้สร้าง next_cursor จากแถวสุดท้าย, preloads ใส่ตอนดึงเท่านั้น (ไม่ให้ติดไปกับ COUNT)
+func Find[T any](q *gorm.DB, spec Spec, idOf func(T) uint, preloads ...func(*gorm.DB) *gorm.DB) ([]T, dto.PageMeta, error) {
+	meta := dto.PageMeta{Page: spec.Page, PageSize: spec.PageSize}
+	if err := q.Session(&gorm.Session{}).Count(&meta.Total).Error; err != nil {
+		return nil, meta, err
+	}
+
+	q = q.Scopes(preloads...).Order(spec.ToOrder()).Limit(spec.PageSize)
+	if spec.Cursor {
+		if spec.After > 0 {
+			op := " < ?"
+			if !spec.Orders[0].Desc {
+				op = " > ?"
+			}
+			q = q.Where(spec.idColumn+op, spec.After)
+		}
+	} else {
+		q = q.Offset((spec.Page - 1) * spec.PageSize)
+	}
+
+	out := []T{}
+	if err := q.Find(&out).Error; err != nil {
+		return nil, meta, err
+	}
+	if spec.Cursor && len(out) == spec.PageSize {
+		next := EncodeCursor(idOf(out[len(out)-1]))
+		meta.NextCursor = &next
+	}
+	return out, meta, nil
+}
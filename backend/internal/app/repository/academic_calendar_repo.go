@@ -27,8 +27,11 @@ func (r *academicCalendarRepository) FindEventsByDateRange(queryStart, queryEnd
 	var calendars []entity.AcademicCalendar
 	// ✅ Logic: หา Event ที่ช่วงเวลา "ทับซ้อน" (Overlap) กับช่วงที่ Query
 	// (Start_Event <= Query_End) AND (End_Event >= Query_Start)
+	// event ซ้ำ (มี rrule) ดึงมาทุกตัวที่เริ่มก่อน Query_End แล้วให้ service กระจาย occurrence เอง
 	err := r.DB.
-		Where("start_date_time <= ? AND end_date_time >= ?", queryEnd, queryStart).
+		Where("(start_date_time <= ? AND end_date_time >= ?) OR (COALESCE(rrule, '') <> '' AND start_date_time <= ?)",
+			queryEnd, queryStart, queryEnd).
+		Order("start_date_time asc").
 		Find(&calendars).Error
 	return calendars, err
 }
@@ -3,6 +3,7 @@ package repository
 import (
 	"backend/internal/app/dto"
 	"backend/internal/app/entity"
+	"backend/internal/app/queryspec"
 	"errors"
 	"gorm.io/gorm"
 )
@@ -18,7 +19,7 @@ type AdminProfileRepository interface {
 	UpdateUser(user *entity.User) error
 
 	// สำหรับ GetAllUsers
-	FindAllUsers(filters dto.UserListFilter) ([]*entity.User, error)
+	FindAllUsers(filters dto.UserListFilter, spec queryspec.Spec) ([]*entity.User, dto.PageMeta, error)
 
 	// สำหรับ GetUserBySutID: ดึงรายละเอียดผู้ใช้งานคนใดคนหนึ่ง
 	FindUserDetailBySutID(sutID string) (*entity.User, error)
@@ -68,14 +69,27 @@ func (r *AdminProfileRepositoryImpl) UpdateUser(user *entity.User) error {
 	return result.Error
 }
 
-// FindAllUsers ดึง User ทั้งหมด (เพื่อแสดงในหน้า Managed Users) พร้อม Filter
-func (r *AdminProfileRepositoryImpl) FindAllUsers(filters dto.UserListFilter) ([]*entity.User, error) {
-	var users []*entity.User
-	query := r.db.
-		Preload("Role").
-		Preload("Department").
-		Preload("Major").
-		Preload("Prefix")
+// UserListQuery sort ที่หน้า Managed Users ใช้ได้ (ตัวกรองอ่านผ่าน dto.UserListFilter)
+var UserListQuery = queryspec.Options{
+	Sorts: map[string]string{
+		"id":         "users.id",
+		"sut_id":     "users.sut_id",
+		"first_name": "users.first_name",
+		"last_name":  "users.last_name",
+		"created_at": "users.created_at",
+	},
+	DefaultSort: "sut_id",
+	IDColumn:    "users.id",
+}
+
+func preloadManagedUser(db *gorm.DB) *gorm.DB {
+	return db.Preload("Role").Preload("Department").Preload("Major").Preload("Prefix")
+}
+
+// FindAllUsers ดึง User (เพื่อแสดงในหน้า Managed Users) พร้อม Filter ทีละหน้า
+func (r *AdminProfileRepositoryImpl) FindAllUsers(filters dto.UserListFilter, spec queryspec.Spec) ([]*entity.User, dto.PageMeta, error) {
+	empty := dto.PageMeta{Page: spec.Page, PageSize: spec.PageSize}
+	query := r.db.Model(&entity.User{})
 
 	// กรองตาม Role
 	if filters.Role != "" {
@@ -93,7 +107,7 @@ func (r *AdminProfileRepositoryImpl) FindAllUsers(filters dto.UserListFilter) ([
 		// ไม่กรอง แสดงทั้งหมด
 	default:
 		// status ไม่ถูกต้อง → คืนค่าว่าง
-		return []*entity.User{}, nil
+		return []*entity.User{}, empty, nil
 	}
 
 	//  Logic การกรอง Major ที่ถูกต้อง (ใช้ Major ID)
@@ -106,9 +120,9 @@ func (r *AdminProfileRepositoryImpl) FindAllUsers(filters dto.UserListFilter) ([
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				// ถ้าหา Major ไม่เจอ, คืนรายการว่าง
-				return []*entity.User{}, nil
+				return []*entity.User{}, empty, nil
 			}
-			return nil, err
+			return nil, empty, err
 		}
 
 		// 2. กรองผู้ใช้ (users) ตาม major_id ที่หามาได้
@@ -125,16 +139,8 @@ func (r *AdminProfileRepositoryImpl) FindAllUsers(filters dto.UserListFilter) ([
 		)
 	}
 
-	// 3. รัน Query
-	result := query.Find(&users)
-
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return []*entity.User{}, nil
-		}
-		return nil, result.Error
-	}
-	return users, nil
+	// 3. รัน Query (นับทั้งหมด + ดึงหน้าที่ขอ)
+	return queryspec.Find(query, spec, func(u *entity.User) uint { return u.ID }, preloadManagedUser)
 }
 
 // FindUserDetailBySutID ดึง User รายคน พร้อมข้อมูลรายละเอียดทั้งหมด (Student, Advisor, Academic)
@@ -1,8 +1,11 @@
 package repository
 
 import (
+	"backend/internal/app/dto"
 	"backend/internal/app/entity"
+	"backend/internal/app/queryspec"
 	"errors"
+	"strings"
 
 	"gorm.io/gorm"
 )
@@ -15,13 +18,25 @@ type AppointmentRepository interface {
 	UpsertAppointmentState(appointmentID uint, statusID uint) error
 	CreateStatusHistory(h *entity.StatusHistory) error
 
-	ListPendingByAdvisor(advisorID uint) ([]entity.Appointment, error)
+	ListPendingByAdvisor(advisorID uint, spec queryspec.Spec) ([]entity.Appointment, dto.PageMeta, error)
 
 	// ✅ เพิ่มใหม่
-	ListDoneByAdvisor(advisorID uint) ([]entity.Appointment, error)
-	ListAllByAdvisor(advisorID uint) ([]entity.Appointment, error)
+	ListDoneByAdvisor(advisorID uint, spec queryspec.Spec) ([]entity.Appointment, dto.PageMeta, error)
+	ListAllByAdvisor(advisorID uint, spec queryspec.Spec) ([]entity.Appointment, dto.PageMeta, error)
+
+	// GetFollowUpChain นัดต้นทางทั้งสายและนัดติดตามผลต่อ ๆ ไปของนัดนี้
+	GetFollowUpChain(id uint) ([]ChainLink, error)
+}
+
+// ChainLink นัดในสายนัดติดตามผล (Depth < 0 = นัดก่อนหน้า, 0 = นัดนี้, > 0 = นัดติดตามผล)
+type ChainLink struct {
+	Appointment entity.Appointment
+	Depth       int
 }
 
+// maxChainDepth กันข้อมูลวน (parent ชี้กลับกันเอง)
+const maxChainDepth = 50
+
 type appointmentRepository struct {
 	db *gorm.DB
 }
@@ -38,6 +53,8 @@ func (r *appointmentRepository) GetByID(id uint) (*entity.Appointment, error) {
 		Preload("Topic").
 		Preload("Category").
 		Preload("AppointmentStatus").
+		Preload("Attachments", func(db *gorm.DB) *gorm.DB { return db.Order("id asc") }).
+		Preload("Attachments.FileAssets").
 		First(&appt, id).Error; err != nil {
 		return nil, err
 	}
@@ -77,40 +94,124 @@ func (r *appointmentRepository) CreateStatusHistory(h *entity.StatusHistory) err
 	return r.db.Create(h).Error
 }
 
-func (r *appointmentRepository) ListPendingByAdvisor(advisorID uint) ([]entity.Appointment, error) {
-	var appts []entity.Appointment
-	err := r.db.
-		Preload("StudentUser").
-		Preload("Topic").
-		Preload("AppointmentStatus").
-		Where("advisor_user_id = ? AND appointment_status_id = ?", advisorID, entity.StatusPendingID).
-		Order("created_at DESC").
-		Find(&appts).Error
-	return appts, err
+// AppointmentListQuery sort/ตัวกรองที่รายการนัดของอาจารย์รับได้
+var AppointmentListQuery = queryspec.Options{
+	Sorts: map[string]string{
+		"id":           "appointments.id",
+		"submitted_at": "appointments.created_at",
+		"start_at":     "appointments.start_at",
+		"status":       "appointments.appointment_status_id",
+	},
+	DefaultSort: "-submitted_at",
+	Filters:     []string{"status", "topic_id", "student_id"},
+	IDColumn:    "appointments.id",
+}
+
+var ErrInvalidAppointmentFilter = errors.New("invalid status, topic_id or student_id filter")
+
+func appointmentID(a entity.Appointment) uint { return a.ID }
+
+func preloadAppointmentRow(db *gorm.DB) *gorm.DB {
+	return db.Preload("StudentUser").Preload("Topic").Preload("AppointmentStatus").
+		// สถานะบันทึกของนัด (หน้ารายการนัดที่เสร็จแล้วไม่ต้องดึงบันทึกทั้งหมดมาจับคู่เอง)
+		Preload("AdvisorLog", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "appointment_id", "status")
+		})
+}
+
+// listByAdvisor นัดของอาจารย์ในสถานะที่กำหนด (ว่าง = ทุกสถานะ) + ตัวกรอง/หน้าตาม spec
+func (r *appointmentRepository) listByAdvisor(advisorID uint, statusIDs []uint, spec queryspec.Spec) ([]entity.Appointment, dto.PageMeta, error) {
+	q := r.db.Model(&entity.Appointment{}).Where("appointments.advisor_user_id = ?", advisorID)
+	if len(statusIDs) > 0 {
+		q = q.Where("appointments.appointment_status_id IN ?", statusIDs)
+	}
+	if code := spec.Filter("status"); code != "" {
+		q = q.Where("appointments.appointment_status_id IN (SELECT id FROM appointment_statuses WHERE status_code = ?)", strings.ToUpper(code))
+	}
+	topicID, err := spec.FilterUint("topic_id")
+	if err != nil {
+		return nil, dto.PageMeta{}, ErrInvalidAppointmentFilter
+	}
+	if topicID != 0 {
+		q = q.Where("appointments.topic_id = ?", topicID)
+	}
+	studentID, err := spec.FilterUint("student_id")
+	if err != nil {
+		return nil, dto.PageMeta{}, ErrInvalidAppointmentFilter
+	}
+	if studentID != 0 {
+		q = q.Where("appointments.student_user_id = ?", studentID)
+	}
+	return queryspec.Find(q, spec, appointmentID, preloadAppointmentRow)
+}
+
+func (r *appointmentRepository) ListPendingByAdvisor(advisorID uint, spec queryspec.Spec) ([]entity.Appointment, dto.PageMeta, error) {
+	return r.listByAdvisor(advisorID, []uint{entity.StatusPendingID}, spec)
 }
 
 // ✅ พิจารณาแล้ว = Approved + Reschedule
-func (r *appointmentRepository) ListDoneByAdvisor(advisorID uint) ([]entity.Appointment, error) {
-	var appts []entity.Appointment
-	err := r.db.
-		Preload("StudentUser").
-		Preload("Topic").
-		Preload("AppointmentStatus").
-		Where("advisor_user_id = ? AND appointment_status_id IN ?", advisorID, []uint{entity.StatusApprovedID, entity.StatusRescheduleID}).
-		Order("created_at DESC").
-		Find(&appts).Error
-	return appts, err
+func (r *appointmentRepository) ListDoneByAdvisor(advisorID uint, spec queryspec.Spec) ([]entity.Appointment, dto.PageMeta, error) {
+	return r.listByAdvisor(advisorID, []uint{entity.StatusApprovedID, entity.StatusRescheduleID}, spec)
 }
 
 // ✅ ทั้งหมด = ไม่กรอง status
-func (r *appointmentRepository) ListAllByAdvisor(advisorID uint) ([]entity.Appointment, error) {
+func (r *appointmentRepository) ListAllByAdvisor(advisorID uint, spec queryspec.Spec) ([]entity.Appointment, dto.PageMeta, error) {
+	return r.listByAdvisor(advisorID, nil, spec)
+}
+
+func (r *appointmentRepository) GetFollowUpChain(id uint) ([]ChainLink, error) {
+	var rows []struct {
+		ID    uint
+		Depth int
+	}
+	err := r.db.Raw(`
+		WITH RECURSIVE up AS (
+			SELECT id, parent_appointment_id, 0 AS depth FROM appointments WHERE id = ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT a.id, a.parent_appointment_id, up.depth - 1
+			FROM appointments a JOIN up ON a.id = up.parent_appointment_id
+			WHERE a.deleted_at IS NULL AND up.depth > ?
+		), down AS (
+			SELECT id, 0 AS depth FROM appointments WHERE id = ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT a.id, down.depth + 1
+			FROM appointments a JOIN down ON a.parent_appointment_id = down.id
+			WHERE a.deleted_at IS NULL AND down.depth < ?
+		)
+		SELECT id, depth FROM up
+		UNION
+		SELECT id, depth FROM down
+		ORDER BY depth ASC, id ASC`,
+		id, -maxChainDepth, id, maxChainDepth).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uint, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+	}
 	var appts []entity.Appointment
-	err := r.db.
-		Preload("StudentUser").
-		Preload("Topic").
-		Preload("AppointmentStatus").
-		Where("advisor_user_id = ?", advisorID).
-		Order("created_at DESC").
-		Find(&appts).Error
-	return appts, err
+	if err := r.db.Preload("AppointmentStatus").Where("id IN ?", ids).Find(&appts).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]entity.Appointment, len(appts))
+	for _, a := range appts {
+		byID[a.ID] = a
+	}
+
+	links := make([]ChainLink, 0, len(rows))
+	seen := map[uint]bool{}
+	for _, row := range rows {
+		a, ok := byID[row.ID]
+		if !ok || seen[row.ID] {
+			continue
+		}
+		seen[row.ID] = true
+		links = append(links, ChainLink{Appointment: a, Depth: row.Depth})
+	}
+	return links, nil
 }
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"backend/internal/app/dto"
+	"backend/internal/app/entity"
+	"backend/internal/app/queryspec"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrLogNotPending  = errors.New("advisor log is not waiting for a report")
+	ErrReportReviewed = errors.New("progress report has already been reviewed or replaced")
+)
+
+type ProgressReportRepository interface {
+	// GetLog บันทึกพร้อมนัดหมาย (ใช้ตรวจเจ้าของ)
+	GetLog(id uint) (*entity.AdvisorLog, error)
+	// ListByLog ทุก version ของบันทึกนี้ (version เก่าก่อน) พร้อมไฟล์และ feedback
+	ListByLog(logID uint) ([]entity.ProgressReport, error)
+	GetReport(id uint) (*entity.ProgressReport, error)
+
+	// Submit สร้างรายงาน เรียก attach (ผูกไฟล์) แล้วเปลี่ยนบันทึกเป็น ReportSubmitted ใน transaction เดียว
+	// ถ้ามีฉบับก่อนหน้า จะตั้ง Version/PreviousID ต่อจากฉบับล่าสุดให้
+	// คืน ErrLogNotPending ถ้าบันทึกไม่ได้รอรายงานอยู่แล้ว (เช่นส่งซ้อนกัน)
+	Submit(report *entity.ProgressReport, attach func(tx *gorm.DB) error) error
+
+	// Review บันทึกผลตรวจของรายงาน (สถานะรายงาน + feedback ถ้ามี) และเปลี่ยนสถานะบันทึกเป็น logStatus
+	// คืน ErrReportReviewed ถ้ารายงานไม่ได้รอตรวจอยู่แล้ว
+	Review(report *entity.ProgressReport, feedback *entity.ReportFeedback, logStatus string) error
+
+	GetFeedback(id uint) (*entity.ReportFeedback, error)
+	// CreateFeedback สร้าง feedback แล้วเรียก attach (ผูกไฟล์) ใน transaction เดียว
+	CreateFeedback(fb *entity.ReportFeedback, attach func(tx *gorm.DB) error) error
+	UpdateFeedbackBody(id uint, body string, editedAt time.Time) error
+
+	SetDueDate(logID uint, due *time.Time) error
+	// ListOverdueByAdvisor บันทึกที่ยังรอรายงานและเลยกำหนดส่งแล้ว ทีละหน้า (ดู OverdueListQuery)
+	ListOverdueByAdvisor(advisorID uint, now time.Time, spec queryspec.Spec) ([]entity.AdvisorLog, dto.PageMeta, error)
+	// ListEscalations ขั้นการแจ้งเตือนที่ส่งไปแล้วของบันทึกเหล่านี้
+	ListEscalations(logIDs []uint) ([]entity.ReportEscalation, error)
+}
+
+type progressReportRepository struct {
+	db *gorm.DB
+}
+
+func NewProgressReportRepository(db *gorm.DB) ProgressReportRepository {
+	return &progressReportRepository{db: db}
+}
+
+func (r *progressReportRepository) GetLog(id uint) (*entity.AdvisorLog, error) {
+	var log entity.AdvisorLog
+	if err := r.db.Preload("Appointment").First(&log, id).Error; err != nil {
+		return nil, err
+	}
+	return &log, nil
+}
+
+func (r *progressReportRepository) ListByLog(logID uint) ([]entity.ProgressReport, error) {
+	var reports []entity.ProgressReport
+	err := r.db.
+		Preload("Feedbacks", func(db *gorm.DB) *gorm.DB { return db.Order("created_at asc, id asc") }).
+		Preload("Feedbacks.Author").
+		Preload("Feedbacks.Files", func(db *gorm.DB) *gorm.DB { return db.Order("position asc") }).
+		Preload("Files", func(db *gorm.DB) *gorm.DB { return db.Order("position asc") }).
+		Where("advisor_logs_id = ?", logID).
+		Order("version asc, id asc").
+		Find(&reports).Error
+	return reports, err
+}
+
+func (r *progressReportRepository) GetReport(id uint) (*entity.ProgressReport, error) {
+	var report entity.ProgressReport
+	if err := r.db.First(&report, id).Error; err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (r *progressReportRepository) Review(report *entity.ProgressReport, feedback *entity.ReportFeedback, logStatus string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// ตรวจได้เฉพาะฉบับที่รอตรวจ และบันทึกยังอยู่ในสถานะ ReportSubmitted
+		res := tx.Model(&entity.AdvisorLog{}).
+			Where("id = ? AND status = ?", report.AdvisorLogsID, "ReportSubmitted").
+			Update("status", logStatus)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrReportReviewed
+		}
+
+		res = tx.Model(&entity.ProgressReport{}).
+			Where("id = ? AND status = ?", report.ID, entity.ProgressReportSubmitted).
+			Updates(map[string]interface{}{
+				"status":         report.Status,
+				"reviewed_at":    report.ReviewedAt,
+				"reviewed_by_id": report.ReviewedByID,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrReportReviewed
+		}
+
+		if feedback == nil {
+			return nil
+		}
+		return tx.Create(feedback).Error
+	})
+}
+
+func (r *progressReportRepository) Submit(report *entity.ProgressReport, attach func(tx *gorm.DB) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&entity.AdvisorLog{}).
+			Where("id = ? AND status = ?", report.AdvisorLogsID, "PendingReport").
+			Update("status", "ReportSubmitted")
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrLogNotPending
+		}
+
+		// แถว log ถูกล็อกจาก UPDATE ข้างบนแล้ว จึงอ่าน version ล่าสุดได้โดยไม่ชนกัน
+		var last entity.ProgressReport
+		err := tx.Where("advisor_logs_id = ?", report.AdvisorLogsID).
+			Order("version desc, id desc").
+			Limit(1).
+			Find(&last).Error
+		if err != nil {
+			return err
+		}
+		report.Version = 1
+		if last.ID != 0 {
+			report.Version = last.Version + 1
+			report.PreviousID = &last.ID
+		}
+
+		if err := tx.Omit("Files", "Feedbacks", "Previous").Create(report).Error; err != nil {
+			return err
+		}
+		return attach(tx)
+	})
+}
+
+func (r *progressReportRepository) GetFeedback(id uint) (*entity.ReportFeedback, error) {
+	var fb entity.ReportFeedback
+	if err := r.db.Preload("Author").First(&fb, id).Error; err != nil {
+		return nil, err
+	}
+	return &fb, nil
+}
+
+func (r *progressReportRepository) CreateFeedback(fb *entity.ReportFeedback, attach func(tx *gorm.DB) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Files", "Author").Create(fb).Error; err != nil {
+			return err
+		}
+		return attach(tx)
+	})
+}
+
+func (r *progressReportRepository) UpdateFeedbackBody(id uint, body string, editedAt time.Time) error {
+	return r.db.Model(&entity.ReportFeedback{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"body": body, "edited_at": editedAt}).Error
+}
+
+func (r *progressReportRepository) SetDueDate(logID uint, due *time.Time) error {
+	return r.db.Model(&entity.AdvisorLog{}).
+		Where("id = ?", logID).
+		Update("report_due_at", due).Error
+}
+
+// OverdueListQuery sort ที่รายการรายงานเลยกำหนดรับได้ (ค่าเริ่มต้นค้างนานสุดก่อน)
+var OverdueListQuery = queryspec.Options{
+	Sorts: map[string]string{
+		"id":     "advisor_logs.id",
+		"due_at": "advisor_logs.report_due_at",
+	},
+	DefaultSort: "due_at",
+	IDColumn:    "advisor_logs.id",
+}
+
+func (r *progressReportRepository) ListOverdueByAdvisor(advisorID uint, now time.Time, spec queryspec.Spec) ([]entity.AdvisorLog, dto.PageMeta, error) {
+	q := r.db.Model(&entity.AdvisorLog{}).
+		Joins("JOIN appointments ON appointments.id = advisor_logs.appointment_id").
+		Where("appointments.advisor_user_id = ?", advisorID).
+		Where("advisor_logs.status = ? AND advisor_logs.report_due_at < ?", "PendingReport", now)
+	return queryspec.Find(q, spec, func(l entity.AdvisorLog) uint { return l.ID }, func(db *gorm.DB) *gorm.DB {
+		return db.Preload("Appointment.StudentUser")
+	})
+}
+
+func (r *progressReportRepository) ListEscalations(logIDs []uint) ([]entity.ReportEscalation, error) {
+	var rows []entity.ReportEscalation
+	if len(logIDs) == 0 {
+		return rows, nil
+	}
+	err := r.db.Where("advisor_log_id IN ?", logIDs).Find(&rows).Error
+	return rows, err
+}
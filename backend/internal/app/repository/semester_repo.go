@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"backend/internal/app/dto"
+	"backend/internal/app/entity"
+	"backend/internal/app/queryspec"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SemesterRepository interface {
+	FindAll(spec queryspec.Spec) ([]entity.Semester, dto.PageMeta, error)
+	// หาภาคการศึกษาที่มีวันที่ day (00:00 เวลาไทย) อยู่ในช่วง
+	FindByDate(day time.Time) (*entity.Semester, error)
+	GetByID(id string) (*entity.Semester, error)
+	// มีภาคการศึกษาอื่น (ที่ยังไม่ถูกลบ) ใช้ปี/เทอมนี้แล้วหรือไม่
+	YearTermTaken(year, term int, exceptID uint) (bool, error)
+	Create(semester *entity.Semester) error
+	Update(semester *entity.Semester) error
+	Delete(semester *entity.Semester) error
+}
+
+type semesterRepository struct {
+	DB *gorm.DB
+}
+
+func NewSemesterRepository(db *gorm.DB) SemesterRepository {
+	return &semesterRepository{DB: db}
+}
+
+// SemesterListQuery sort/ตัวกรองที่รายการภาคการศึกษารับได้
+var SemesterListQuery = queryspec.Options{
+	Sorts: map[string]string{
+		"id":            "id",
+		"start_date":    "start_date",
+		"academic_year": "academic_year",
+	},
+	DefaultSort: "-start_date",
+	Filters:     []string{"academic_year"},
+}
+
+func (r *semesterRepository) FindAll(spec queryspec.Spec) ([]entity.Semester, dto.PageMeta, error) {
+	q := r.DB.Model(&entity.Semester{})
+	year, err := spec.FilterUint("academic_year")
+	if err != nil {
+		return nil, dto.PageMeta{}, err
+	}
+	if year != 0 {
+		q = q.Where("academic_year = ?", year)
+	}
+	return queryspec.Find(q, spec, func(s entity.Semester) uint { return s.ID })
+}
+
+func (r *semesterRepository) FindByDate(day time.Time) (*entity.Semester, error) {
+	var semester entity.Semester
+	err := r.DB.
+		Where("start_date <= ? AND end_date >= ?", day, day).
+		Order("start_date desc").
+		First(&semester).Error
+	if err != nil {
+		return nil, err
+	}
+	return &semester, nil
+}
+
+func (r *semesterRepository) GetByID(id string) (*entity.Semester, error) {
+	var semester entity.Semester
+	err := r.DB.First(&semester, "id = ?", id).Error
+	return &semester, err
+}
+
+func (r *semesterRepository) YearTermTaken(year, term int, exceptID uint) (bool, error) {
+	var count int64
+	err := r.DB.Model(&entity.Semester{}).
+		Where("academic_year = ? AND term_number = ? AND id <> ?", year, term, exceptID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *semesterRepository) Create(semester *entity.Semester) error {
+	return r.DB.Create(semester).Error
+}
+
+func (r *semesterRepository) Update(semester *entity.Semester) error {
+	return r.DB.Save(semester).Error
+}
+
+func (r *semesterRepository) Delete(semester *entity.Semester) error {
+	return r.DB.Delete(semester).Error
+}
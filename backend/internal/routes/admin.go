@@ -7,6 +7,7 @@ import (
 	"backend/internal/middlewares"
 	"backend/internal/service/academiccalendar" // หรือ backend/internal/app/service แล้วแต่โครงสร้างจริง
 	"backend/internal/service/adminprofile"     // ใช้แพ็กเกจ service ของ admin
+	"backend/internal/service/calendarimport"
 	"github.com/gin-gonic/gin"
 )
 
@@ -19,6 +20,12 @@ func SetupAdminRoutes(r *gin.Engine) {
 	academicService := service.NewAcademicCalendarService(academicRepo)
 	academicCtrl := controller.NewAcademicCalendarController(academicService)
 
+	importCtrl := controller.NewCalendarImportController(calendarimport.New(db))
+
+	semesterRepo := repository.NewSemesterRepository(db)
+	semesterService := service.NewSemesterService(semesterRepo, academicService)
+	semesterCtrl := controller.NewSemesterController(semesterService)
+
 	adminRepo := repository.NewAdminProfileRepositoryImpl(db)
 	adminSvc := adminprofile.NewAdminProfileService(adminRepo, db)
 	adminCtrl := controller.NewAdminProfileController(adminSvc)
@@ -26,6 +33,12 @@ func SetupAdminRoutes(r *gin.Engine) {
 	// 3. สร้าง Group Route
 	api := r.Group("/api")
 	api.GET("/holidays", academicCtrl.GetHolidaysByYear)
+	api.GET("/holidays/range", academicCtrl.GetEventsInRange)
+
+	// ภาคการศึกษา (อ่านได้โดยไม่ต้อง login เหมือนปฏิทิน)
+	api.GET("/semesters", semesterCtrl.List)
+	api.GET("/semesters/at", semesterCtrl.At)
+	api.GET("/semesters/teaching-day", semesterCtrl.TeachingDay)
 
 	api.Use(middleware.AuthMiddleware())
 	{
@@ -33,6 +46,14 @@ func SetupAdminRoutes(r *gin.Engine) {
 		api.PUT("/events/:id", academicCtrl.UpdateEvent)
 		api.DELETE("/events/:id", academicCtrl.DeleteEvent)
 
+		api.POST("/semesters", semesterCtrl.Create)
+		api.PUT("/semesters/:id", semesterCtrl.Update)
+		api.DELETE("/semesters/:id", semesterCtrl.Delete)
+
+		// นำเข้าปฏิทินการศึกษาจากไฟล์ .ics / .csv (preview ก่อน แล้วค่อย import)
+		api.POST("/admin/academic-calendar/import/preview", importCtrl.Preview)
+		api.POST("/admin/academic-calendar/import", importCtrl.Import)
+
 		api.GET("/admin/me/profile", adminCtrl.GetMyAdminProfile)
 		api.PUT("/admin/me/profile", adminCtrl.UpdateMyAdminProfile)
 
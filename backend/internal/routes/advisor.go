@@ -1,12 +1,18 @@
 package routes
 
 import (
+	"os"
+
 	"backend/config"
 	"backend/internal/app/controller"
 	"backend/internal/app/repository"
 	"backend/internal/middlewares"
+	"backend/internal/service/advisingrecord"
 	"backend/internal/service/advisorlog"
 	"backend/internal/service/advisorprofile"
+	"backend/internal/service/fileasset"
+	"backend/internal/service/progressreport"
+	"backend/internal/service/signedurl"
 	
 	"github.com/gin-gonic/gin"
 )
@@ -17,10 +23,14 @@ func SetupAdvisorRoutes(r *gin.Engine) {
 	repo := repository.NewAdvisorProfileRepository(db)
 	svc := advisorprofile.NewAdvisorProfileService(repo, db)
 	profileCtrl := controller.NewAdvisorProfileController(svc)
-	logSvc := advisorlog.New(db, "uploads")
-	logCtrl := controller.NewAdvisorLogController(logSvc)
-	reportCtrl := controller.NewProgressReportController()
-	feedbackCtrl := controller.NewReportFeedbackController()
+	files := fileasset.New(db, config.Storage())
+	logSvc := advisorlog.New(db, files)
+	logCtrl := controller.NewAdvisorLogController(logSvc, files, signedurl.FromEnv())
+	reportSvc := progressreport.New(repository.NewProgressReportRepository(db), files)
+	reportCtrl := controller.NewProgressReportController(reportSvc)
+	feedbackCtrl := controller.NewReportFeedbackController(reportSvc, files)
+	recordSvc := advisingrecord.New(db, os.Getenv("PDF_FONT_DIR"))
+	recordCtrl := controller.NewAdvisingRecordController(recordSvc)
 
 	api := r.Group("/api")
 	api.Use(middleware.AuthMiddleware())
@@ -29,6 +39,8 @@ func SetupAdvisorRoutes(r *gin.Engine) {
 	api.PUT("/advisor/me/profile", profileCtrl.UpdateMyAdvisorProfile)
 	api.GET("/advisor/me/students", profileCtrl.GetMyStudents)
 	api.GET("/advisor/me/students/:sut_id", profileCtrl.GetStudentBySutID)
+	api.GET("/advisor/me/students/:sut_id/record.pdf", recordCtrl.StudentRecordPDF)
+	api.GET("/advisor/me/overdue_reports", reportCtrl.ListOverdue)
 
 	// -------------------------
 	// Advisor Logs (เรียงถูกต้อง)
@@ -36,16 +48,25 @@ func SetupAdvisorRoutes(r *gin.Engine) {
 	api.GET("/advisor_logs", logCtrl.ListAll)
 	api.POST("/advisor_logs", logCtrl.Create)
 	api.GET("/advisor_logs/student/:student_id", logCtrl.ListByStudent)
+	api.GET("/advisor_logs/analytics/tags", logCtrl.TagStats)
 	api.PATCH("/advisor_logs/:id", logCtrl.UpdateStatus)
 	api.GET("/advisor_logs/:id", logCtrl.GetByID)
 	api.PATCH("/advisor_logs/:id/edit", logCtrl.Update)
 	api.GET("/advisor_logs/:id/files/:index", logCtrl.DownloadFile)
+	api.GET("/advisor_logs/:id/files/:index/url", logCtrl.FileURL)
+	api.GET("/advisor_logs/:id/revisions", logCtrl.ListRevisions)
+	api.GET("/advisor_logs/:id/revisions/diff", logCtrl.DiffRevisions)
+	api.POST("/advisor_logs/:id/revisions/:revision/restore", logCtrl.RestoreRevision)
+	api.PUT("/advisor_logs/:id/report_due", reportCtrl.SetDueDate)
 
 	// -------------------------
 	// Report & Feedback
 	// -------------------------
 	api.GET("/progress_reports/log/:log_id", reportCtrl.GetByLogID)
+	api.POST("/progress_reports/:id/review", reportCtrl.Review)
 	api.POST("/report_feedbacks", feedbackCtrl.Create)
+	api.PATCH("/report_feedbacks/:id", feedbackCtrl.Update)
+	api.GET("/report_feedbacks/:id/files/:index", feedbackCtrl.DownloadFile)
 
 	
 	
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"backend/config"
+	"backend/internal/app/controller"
+	"backend/internal/app/repository"
+	"backend/internal/middlewares"
+	academic "backend/internal/service/academiccalendar"
+	"backend/internal/service/agenda"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ปฏิทินส่วนตัวของผู้ login (นัด, ปฏิทินการศึกษา, ช่วงไม่ว่าง, กำหนดส่งรายงาน)
+func SetupAgendaRoutes(r *gin.Engine) {
+	db := config.DB()
+	calendar := academic.NewAcademicCalendarService(repository.NewAcademicCalendarRepository(db))
+	ctrl := controller.NewAgendaController(agenda.New(db, calendar))
+
+	api := r.Group("/api")
+	api.Use(middleware.AuthMiddleware())
+
+	api.GET("/me/agenda", ctrl.Agenda)
+}
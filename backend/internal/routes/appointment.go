@@ -8,6 +8,8 @@ import (
 	"backend/internal/app/controller"
 	"backend/internal/app/repository"
 	approvalService "backend/internal/service/approval"
+	"backend/internal/service/booking"
+	"backend/internal/service/fileasset"
 
 	middleware "backend/internal/middlewares"
 )
@@ -18,7 +20,10 @@ func SetupAppointmentRoutes(r *gin.Engine) {
 
 	apptRepo := repository.NewAppointmentRepository(db)
 	apptService := approvalService.NewAppointmentService(apptRepo)
-	apptController := controller.NewAppointmentController(apptService)
+	files := fileasset.New(db, config.Storage())
+	bookingService := booking.New(db, files)
+	apptController := controller.NewAppointmentController(apptService, bookingService)
+	bookingController := controller.NewAppointmentBookingController(bookingService, files)
 
 	appointments := r.Group("/api/appointments")
 	appointments.Use(middleware.AuthMiddleware())
@@ -28,8 +33,17 @@ func SetupAppointmentRoutes(r *gin.Engine) {
 	appointments.GET("/pending", apptController.ListPending)
 	appointments.GET("/done", apptController.ListDone)
 
+	// ✅ นักศึกษาขอนัด (แนบไฟล์ได้)
+	appointments.POST("", bookingController.Request)
+
+	// ✅ นักศึกษาดูนัดของตัวเอง (สถานะ, เวลาที่เสนอ, timeline, บันทึกการปรึกษา)
+	appointments.GET("/mine", bookingController.ListMine)
+	appointments.GET("/mine/:id", bookingController.GetMine)
+
 	// ✅ detail/approve/reschedule
 	appointments.GET("/:id", apptController.GetByID)
 	appointments.PUT("/:id/approve", apptController.ApproveAppointment)
 	appointments.PUT("/:id/reschedule", apptController.ProposeNewTime)
+	appointments.GET("/:id/attachments/:index", bookingController.DownloadAttachment)
+	appointments.GET("/:id/history", bookingController.History)
 }
@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"backend/config"
+	"backend/internal/app/controller"
+	"backend/internal/middlewares"
+	"backend/internal/service/calendarfeed"
+)
+
+// route ของไฟล์ปฏิทิน (.ics) สำหรับ subscribe ใน Google/Apple/Outlook
+func SetupCalendarFeedRoutes(r *gin.Engine) {
+	db := config.DB()
+
+	feedSvc := calendarfeed.New(db)
+	feedCtrl := controller.NewCalendarFeedController(feedSvc)
+
+	// ไม่ต้อง login (แอปปฏิทินส่ง JWT ไม่ได้) ใช้ token ใน URL แทน
+	public := r.Group("/api/calendar")
+	public.GET("/academic.ics", feedCtrl.AcademicFeed)
+	public.GET("/:token", feedCtrl.UserFeed) // /api/calendar/<token>.ics
+
+	me := r.Group("/api/me")
+	me.Use(middleware.AuthMiddleware())
+	me.GET("/calendar-feed", feedCtrl.GetMyFeed)
+	me.POST("/calendar-feed/rotate", feedCtrl.RotateMyFeed)
+
+	appointments := r.Group("/api/appointments")
+	appointments.Use(middleware.AuthMiddleware())
+	appointments.GET("/:id/ics", feedCtrl.AppointmentICS)
+}
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"backend/config"
+	"backend/internal/app/controller"
+	"backend/internal/middlewares"
+	"backend/internal/service/export"
+)
+
+// route สำหรับ export ข้อมูลเป็นไฟล์ (csv/xlsx) ต้อง login
+func SetupExportRoutes(r *gin.Engine) {
+	db := config.DB()
+
+	exportSvc := export.New(db)
+	exportCtrl := controller.NewExportController(exportSvc)
+
+	api := r.Group("/api/export")
+	api.Use(middleware.AuthMiddleware())
+
+	api.GET("/appointments", exportCtrl.Appointments)
+	api.GET("/advisor_logs", exportCtrl.AdvisorLogs)
+	api.GET("/reports", exportCtrl.Reports)
+}
@@ -0,0 +1,37 @@
+package routes
+
+import (
+	"backend/config"
+	"backend/internal/app/controller"
+	"backend/internal/middlewares"
+	"backend/internal/service/fileasset"
+	"backend/internal/service/profileimage"
+	"backend/internal/service/reportimage"
+	"backend/internal/service/signedurl"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupFileRoutes อัปโหลด/ดาวน์โหลดรูปประกอบ report, รูปโปรไฟล์ และลิงก์ดาวน์โหลดแบบมีลายเซ็น
+func SetupFileRoutes(r *gin.Engine) {
+	db := config.DB()
+	files := fileasset.New(db, config.Storage())
+
+	reportImageCtrl := controller.NewReportImageController(reportimage.New(db, files), files)
+	profileImageCtrl := controller.NewProfileImageController(profileimage.New(db, files), files)
+	signedFileCtrl := controller.NewSignedFileController(signedurl.FromEnv(), files)
+
+	// public: รูปโปรไฟล์ใช้ใน <img> โดยตรง
+	r.GET("/api/users/:id/profile-image", profileImageCtrl.Get)
+	// public: ตรวจสิทธิ์จากลายเซ็นในลิงก์แทน JWT
+	r.GET("/api/files/:id", signedFileCtrl.Download)
+
+	api := r.Group("/api")
+	api.Use(middleware.AuthMiddleware())
+
+	api.POST("/me/profile-image", profileImageCtrl.Upload)
+
+	api.POST("/reports/:id/images", reportImageCtrl.Upload)
+	api.GET("/reports/:id/images", reportImageCtrl.List)
+	api.GET("/reports/:id/images/:image_id", reportImageCtrl.Download)
+}
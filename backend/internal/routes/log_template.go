@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"backend/config"
+	"backend/internal/app/controller"
+	"backend/internal/middlewares"
+	"backend/internal/service/logtemplate"
+
+	"github.com/gin-gonic/gin"
+)
+
+// แบบฟอร์มบันทึกการให้คำปรึกษา + คำศัพท์ tag
+func SetupLogTemplateRoutes(r *gin.Engine) {
+	ctrl := controller.NewLogTemplateController(logtemplate.New(config.DB()))
+
+	api := r.Group("/api")
+	api.Use(middleware.AuthMiddleware())
+
+	api.GET("/log-templates", ctrl.ListTemplates)
+	api.GET("/log-templates/:id", ctrl.GetTemplate)
+	api.GET("/log-tags", ctrl.ListTags)
+
+	// admin เท่านั้น (ตรวจใน controller)
+	api.POST("/admin/log-templates", ctrl.CreateTemplate)
+	api.PUT("/admin/log-templates/:id", ctrl.UpdateTemplate)
+	api.DELETE("/admin/log-templates/:id", ctrl.DeleteTemplate)
+	api.POST("/admin/log-tags", ctrl.CreateTag)
+	api.PUT("/admin/log-tags/:id", ctrl.UpdateTag)
+	api.DELETE("/admin/log-tags/:id", ctrl.DeactivateTag)
+}
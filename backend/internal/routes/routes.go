@@ -15,6 +15,12 @@ func SetupRoutes(r *gin.Engine) {
 	SetupAppointmentRoutes(r)  // /api/appointments/... (ต้อง login)
 	SetupAdminRoutes(r)
 	SetupMasterRoutes(r)
+	SetupExportRoutes(r)       // /api/export/... (csv/xlsx)
+	SetupCalendarFeedRoutes(r) // /api/calendar/... (.ics)
+	SetupFileRoutes(r)         // /api/reports/:id/images, /api/me/profile-image
+	SetupLogTemplateRoutes(r)  // /api/log-templates, /api/log-tags
+	SetupSearchRoutes(r)       // /api/search
+	SetupAgendaRoutes(r)       // /api/me/agenda
 
 	// ===== Report =====	
 	r.GET("/reports", controller.GetAllReport)
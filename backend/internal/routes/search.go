@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"backend/config"
+	"backend/internal/app/controller"
+	"backend/internal/middlewares"
+	"backend/internal/service/search"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ค้นหารวม (ผลลัพธ์กรองตามสิทธิ์ผู้เรียกใน service)
+func SetupSearchRoutes(r *gin.Engine) {
+	ctrl := controller.NewSearchController(search.New(config.DB()))
+
+	api := r.Group("/api")
+	api.Use(middleware.AuthMiddleware())
+
+	api.GET("/search", ctrl.Search)
+}
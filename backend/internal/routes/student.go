@@ -5,6 +5,8 @@ import (
     "backend/config"
     "backend/internal/app/controller" 
     "backend/internal/app/repository"
+    "backend/internal/service/fileasset"
+    "backend/internal/service/progressreport"
     "backend/internal/service/studentprofile"
     "backend/internal/middlewares"
 )
@@ -15,7 +17,8 @@ func SetupStudentRoutes(r *gin.Engine) {
     repo := repository.NewProfileRepository(db)
     svc := service.NewProfileService(repo)
     profileCtrl := controller.NewProfileController(svc)
-    reportCtrl := controller.NewProgressReportController()
+    reportSvc := progressreport.New(repository.NewProgressReportRepository(db), fileasset.New(db, config.Storage()))
+    reportCtrl := controller.NewProgressReportController(reportSvc)
 
     api := r.Group("/api")
     api.Use(middleware.AuthMiddleware())
@@ -4,8 +4,10 @@ import (
 	"backend/internal/app/dto"
 	"backend/internal/app/entity"
 	"backend/internal/app/repository"
+	"backend/internal/service/rrule"
 	
 	"errors"
+	"sort"
 	"time"
 )
 
@@ -53,41 +55,102 @@ func combineDateAndTime(dateStr, timeStr string) (time.Time, error) {
 // Functions
 // ---------------------------------------------------------
 
+// Occurrence 1 ครั้งของ event (event ไม่ซ้ำมี occurrence เดียว)
+type Occurrence struct {
+	Event entity.AcademicCalendar
+	Start time.Time
+	End   time.Time
+}
+
+// ExpandEvents กระจาย event ซ้ำ (RRULE) ให้เป็น occurrence ที่ทับกับช่วง [from, to) เรียงตามเวลาเริ่ม
+func ExpandEvents(events []entity.AcademicCalendar, from, to time.Time) []Occurrence {
+	loc := getLocation()
+
+	var out []Occurrence
+	for _, cal := range events {
+		start, end := cal.StartDateTime.In(loc), cal.EndDateTime.In(loc)
+
+		if cal.RRule == "" {
+			if start.Before(to) && !end.Before(from) {
+				out = append(out, Occurrence{Event: cal, Start: start, End: end})
+			}
+			continue
+		}
+
+		rule, err := rrule.Parse(cal.RRule)
+		if err != nil {
+			// rule เสีย (ไม่ควรเกิดเพราะตรวจตอนบันทึก) ใช้เป็น event ครั้งเดียว
+			if start.Before(to) && !end.Before(from) {
+				out = append(out, Occurrence{Event: cal, Start: start, End: end})
+			}
+			continue
+		}
+
+		dur := end.Sub(start)
+		for _, occ := range rule.Expand(start, dur, from, to) {
+			out = append(out, Occurrence{Event: cal, Start: occ, End: occ.Add(dur)})
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Start.Before(out[j].Start) })
+	return out
+}
+
+// GetOccurrences คืน occurrence ทั้งหมดที่ทับกับช่วง [from, to) (รวม event ซ้ำ)
+func (s *AcademicCalendarService) GetOccurrences(from, to time.Time) ([]Occurrence, error) {
+	events, err := s.Repo.FindEventsByDateRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return ExpandEvents(events, from, to), nil
+}
+
+func toHolidayResponse(occ Occurrence) dto.HolidayResponse {
+	return dto.HolidayResponse{
+		ID: occ.Event.ID,
+
+		// ส่งกลับแยกเป็น Date และ Time
+		StartDate: occ.Start.Format("2006-01-02"),
+		EndDate:   occ.End.Format("2006-01-02"),
+		StartTime: occ.Start.Format("15:04"),
+		EndTime:   occ.End.Format("15:04"),
+
+		LocalName:   occ.Event.EventName,
+		Name:        occ.Event.EventName,
+		CountryCode: "TH",
+		Types:       []string{occ.Event.EventType},
+
+		RRule:     occ.Event.RRule,
+		IsHoliday: occ.Event.IsHoliday,
+	}
+}
+
 func (s *AcademicCalendarService) GetHolidaysByYear(yearStr string) ([]dto.HolidayResponse, error) {
 	// Query ช่วงวันที่ 1 ม.ค. - 31 ธ.ค. ของปีนั้น
-	startDate, err := time.Parse("2006-01-02", yearStr+"-01-01")
+	startDate, err := time.ParseInLocation("2006-01-02", yearStr+"-01-01", getLocation())
 	if err != nil {
 		return nil, errors.New("invalid year format")
 	}
 	endDate := startDate.AddDate(1, 0, 0)
 
-	events, err := s.Repo.FindEventsByDateRange(startDate, endDate)
+	return s.GetEventsInRange(startDate, endDate)
+}
+
+// GetEventsInRange event ทั้งหมดในช่วง [from, to) โดยกระจาย event ซ้ำเป็นรายครั้ง
+func (s *AcademicCalendarService) GetEventsInRange(from, to time.Time) ([]dto.HolidayResponse, error) {
+	if !to.After(from) {
+		return nil, errors.New("to must be after from")
+	}
+
+	occurrences, err := s.GetOccurrences(from, to)
 	if err != nil {
 		return nil, err
 	}
 
-	loc := getLocation() // เตรียมแปลงเวลาเป็นไทยตอนแสดงผล
-
-	var response []dto.HolidayResponse
-	for _, cal := range events {
-		// แปลงเวลาจาก DB (ซึ่งอาจเป็น UTC) ให้เป็นเวลาไทยก่อนส่งกลับ
-		startInThai := cal.StartDateTime.In(loc)
-		endInThai := cal.EndDateTime.In(loc)
-
-		response = append(response, dto.HolidayResponse{
-			ID: cal.ID,
-			
-			// ส่งกลับแยกเป็น Date และ Time
-			StartDate: startInThai.Format("2006-01-02"),
-			EndDate:   endInThai.Format("2006-01-02"),
-			StartTime: startInThai.Format("15:04"),
-			EndTime:   endInThai.Format("15:04"),
-
-			LocalName:   cal.EventName,
-			Name:        cal.EventName,
-			CountryCode: "TH",
-			Types:       []string{cal.EventType},
-		})
+	// ใช้ slice ว่าง (ไม่ใช่ nil) ให้ JSON เป็น [] เสมอ
+	response := make([]dto.HolidayResponse, 0, len(occurrences))
+	for _, occ := range occurrences {
+		response = append(response, toHolidayResponse(occ))
 	}
 	return response, nil
 }
@@ -108,12 +171,18 @@ func (s *AcademicCalendarService) CreateEvent(req dto.EventRequest) (*entity.Aca
 		return nil, errors.New("วันสิ้นสุดต้องมาหลังวันเริ่มต้น")
 	}
 
+	rule, err := rrule.Normalize(req.RRule)
+	if err != nil {
+		return nil, err
+	}
+
 	// 3. สร้าง Entity ลง DB
 	event := entity.AcademicCalendar{
 		EventName:     req.Title,
 		EventType:     req.Type,
 		StartDateTime: startDateTime,
 		EndDateTime:   endDateTime,
+		RRule:         rule,
 		AdminID:       req.AdminID,
 		// IsHoliday: true/false (กำหนด Logic เพิ่มเติมได้ถ้าต้องการ)
 	}
@@ -142,11 +211,18 @@ func (s *AcademicCalendarService) UpdateEvent(id string, req dto.EventRequest) e
 		return errors.New("วันสิ้นสุดต้องมาหลังวันเริ่มต้น")
 	}
 
+	rule, err := rrule.Normalize(req.RRule)
+	if err != nil {
+		return err
+	}
+
 	// 3. Update fields
 	event.EventName = req.Title
 	event.EventType = req.Type
 	event.StartDateTime = startDateTime
 	event.EndDateTime = endDateTime
+	event.RRule = rule
+	event.Sequence++ // ให้ปฏิทินที่ subscribe ไว้อัปเดต event เดิม
 
 	return s.Repo.UpdateEvent(event)
 }
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"backend/internal/app/dto"
+	"backend/internal/app/entity"
+	"backend/internal/app/queryspec"
+	"backend/internal/app/repository"
+
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSemesterNotFound = errors.New("semester not found")
+	ErrNoSemester       = errors.New("date is not in any semester")
+	ErrSemesterExists   = errors.New("a semester for this academic year and term already exists")
+)
+
+// เหตุผลที่ไม่ใช่วันเรียน
+const (
+	ReasonOutsideSemester = "outside_semester"
+	ReasonWeekend         = "weekend"
+	ReasonHoliday         = "holiday"
+	ReasonExamPeriod      = "exam_period"
+)
+
+type SemesterService struct {
+	Repo     repository.SemesterRepository
+	Calendar *AcademicCalendarService
+}
+
+func NewSemesterService(repo repository.SemesterRepository, calendar *AcademicCalendarService) *SemesterService {
+	return &SemesterService{Repo: repo, Calendar: calendar}
+}
+
+// ---------------------------------------------------------
+// Helper
+// ---------------------------------------------------------
+
+// startOfDay ตัดเวลาออก (00:00 เวลาไทย)
+func startOfDay(t time.Time) time.Time {
+	loc := getLocation()
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+func parseDate(s string) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02", s, getLocation())
+}
+
+// parseOptionalDate ค่าว่าง = ไม่ระบุ
+func parseOptionalDate(s, field string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	t, err := parseDate(s)
+	if err != nil {
+		return nil, errors.New("invalid " + field + " (YYYY-MM-DD)")
+	}
+	return &t, nil
+}
+
+func applySemesterRequest(sem *entity.Semester, req dto.SemesterRequest) error {
+	start, err := parseDate(req.StartDate)
+	if err != nil {
+		return errors.New("invalid start_date (YYYY-MM-DD)")
+	}
+	end, err := parseDate(req.EndDate)
+	if err != nil {
+		return errors.New("invalid end_date (YYYY-MM-DD)")
+	}
+
+	sem.Name = req.Name
+	sem.AcademicYear = req.AcademicYear
+	sem.TermNumber = req.TermNumber
+	sem.StartDate = start
+	sem.EndDate = end
+
+	fields := []struct {
+		dst   **time.Time
+		value string
+		name  string
+	}{
+		{&sem.RegistrationStart, req.RegistrationStart, "registration_start"},
+		{&sem.RegistrationEnd, req.RegistrationEnd, "registration_end"},
+		{&sem.MidtermExamStart, req.MidtermExamStart, "midterm_exam_start"},
+		{&sem.MidtermExamEnd, req.MidtermExamEnd, "midterm_exam_end"},
+		{&sem.FinalExamStart, req.FinalExamStart, "final_exam_start"},
+		{&sem.FinalExamEnd, req.FinalExamEnd, "final_exam_end"},
+	}
+	for _, f := range fields {
+		t, err := parseOptionalDate(f.value, f.name)
+		if err != nil {
+			return err
+		}
+		*f.dst = t
+	}
+
+	return sem.Validate()
+}
+
+// ---------------------------------------------------------
+// Functions
+// ---------------------------------------------------------
+
+// checkYearTerm ปี/เทอมห้ามซ้ำกับภาคการศึกษาอื่น (ตรวจก่อนชน unique index จะได้ตอบ 409 ที่อ่านรู้เรื่อง)
+func (s *SemesterService) checkYearTerm(sem *entity.Semester) error {
+	taken, err := s.Repo.YearTermTaken(sem.AcademicYear, sem.TermNumber, sem.ID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrSemesterExists
+	}
+	return nil
+}
+
+// List ภาคการศึกษาทีละหน้า (ดู repository.SemesterListQuery)
+func (s *SemesterService) List(spec queryspec.Spec) (*dto.SemesterListResp, error) {
+	semesters, meta, err := s.Repo.FindAll(spec)
+	if err != nil {
+		return nil, err
+	}
+	out := dto.NewPageResp(semesters, meta)
+	return &out, nil
+}
+
+func (s *SemesterService) Create(req dto.SemesterRequest) (*entity.Semester, error) {
+	sem := entity.Semester{AdminID: req.AdminID}
+	if err := applySemesterRequest(&sem, req); err != nil {
+		return nil, err
+	}
+	if err := s.checkYearTerm(&sem); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.Create(&sem); err != nil {
+		return nil, err
+	}
+	return &sem, nil
+}
+
+func (s *SemesterService) Update(id string, req dto.SemesterRequest) (*entity.Semester, error) {
+	sem, err := s.Repo.GetByID(id)
+	if err != nil {
+		return nil, ErrSemesterNotFound
+	}
+	if err := applySemesterRequest(sem, req); err != nil {
+		return nil, err
+	}
+	if err := s.checkYearTerm(sem); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.Update(sem); err != nil {
+		return nil, err
+	}
+	return sem, nil
+}
+
+func (s *SemesterService) Delete(id string) error {
+	sem, err := s.Repo.GetByID(id)
+	if err != nil {
+		return ErrSemesterNotFound
+	}
+	return s.Repo.Delete(sem)
+}
+
+// SemesterAt ภาคการศึกษาที่วันที่ date อยู่ (ErrNoSemester ถ้าอยู่ช่วงปิดภาค)
+func (s *SemesterService) SemesterAt(date time.Time) (*entity.Semester, error) {
+	sem, err := s.Repo.FindByDate(startOfDay(date))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNoSemester
+		}
+		return nil, err
+	}
+	return sem, nil
+}
+
+// IsTeachingDay วันเรียน = อยู่ในภาคการศึกษา, จันทร์-ศุกร์, ไม่ใช่วันหยุด และไม่อยู่ในช่วงสอบ
+func (s *SemesterService) IsTeachingDay(date time.Time) (*dto.TeachingDayResponse, error) {
+	day := startOfDay(date)
+	res := &dto.TeachingDayResponse{Date: day.Format("2006-01-02")}
+
+	sem, err := s.SemesterAt(day)
+	if err != nil {
+		if errors.Is(err, ErrNoSemester) {
+			res.Reason = ReasonOutsideSemester
+			return res, nil
+		}
+		return nil, err
+	}
+	res.Semester = sem
+
+	if wd := day.Weekday(); wd == time.Saturday || wd == time.Sunday {
+		res.Reason = ReasonWeekend
+		return res, nil
+	}
+
+	occurrences, err := s.Calendar.GetOccurrences(day, day.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	for _, occ := range occurrences {
+		if occ.Event.IsHoliday || occ.Event.EventType == "holiday" {
+			res.Reason = ReasonHoliday
+			res.HolidayName = occ.Event.EventName
+			return res, nil
+		}
+	}
+
+	if sem.InExamPeriod(day) {
+		res.Reason = ReasonExamPeriod
+		return res, nil
+	}
+
+	res.IsTeachingDay = true
+	return res, nil
+}
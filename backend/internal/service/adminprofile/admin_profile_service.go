@@ -3,6 +3,7 @@ package adminprofile
 import (
 	"backend/internal/app/dto"
 	"backend/internal/app/entity"
+	"backend/internal/app/queryspec"
 	"backend/internal/app/repository"
 	"errors"
 	"gorm.io/gorm"
@@ -105,16 +106,14 @@ func (s *AdminProfileService) UpdateMyProfile(
 }
 
 // GetAllUsers ดึงข้อมูลผู้ใช้งานทั้งหมดในระบบ (เพื่อแสดงในหน้า Managed Users)
-// รับ Struct UserListFilter
-func (s *AdminProfileService) GetAllUsers(filters dto.UserListFilter) (*dto.AdminManagedUsersResponse, error) {
-
-	//  ต้องไปแก้ไข Interface และ Implementation ของ FindAllUsers ใน Repository ด้วย
-	users, err := s.Repo.FindAllUsers(filters)
+// รับ Struct UserListFilter + หน้า/การเรียง (queryspec)
+func (s *AdminProfileService) GetAllUsers(filters dto.UserListFilter, spec queryspec.Spec) (*dto.AdminManagedUsersResponse, error) {
+	users, meta, err := s.Repo.FindAllUsers(filters, spec)
 	if err != nil {
 		return nil, err
 	}
 
-	resp := dto.NewAdminManagedUsersResponse(users)
+	resp := dto.NewAdminManagedUsersResponse(users, meta)
 	return &resp, nil
 }
 
@@ -0,0 +1,396 @@
+package advisingrecord
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"gorm.io/gorm"
+
+	"backend/internal/app/entity"
+)
+
+// ไฟล์ฟอนต์ภาษาไทย (TTF) ที่ต้องวางไว้ใน fontDir — ฟอนต์จะถูกฝังลงใน PDF
+const (
+	fontFamily      = "Sarabun"
+	fontRegularFile = "Sarabun-Regular.ttf"
+	fontBoldFile    = "Sarabun-Bold.ttf"
+)
+
+var (
+	ErrStudentNotFound = errors.New("student not found")
+	ErrAdvisorNotFound = errors.New("advisor not found")
+	ErrForbidden       = errors.New("student is not your advisee")
+	ErrFontNotFound    = errors.New("thai font not found (set PDF_FONT_DIR)")
+)
+
+type Service interface {
+	// StudentRecordPDF สร้างบันทึกการให้คำปรึกษาของนักศึกษา 1 คน (เฉพาะ advisor ของนักศึกษาคนนั้น)
+	StudentRecordPDF(ctx context.Context, advisorSutID, studentSutID string) ([]byte, error)
+}
+
+type service struct {
+	db      *gorm.DB
+	fontDir string
+
+	fontMu      sync.Mutex
+	fontRegular []byte
+	fontBold    []byte
+}
+
+func New(db *gorm.DB, fontDir string) Service {
+	if fontDir == "" {
+		fontDir = "assets/fonts"
+	}
+	return &service{db: db, fontDir: fontDir}
+}
+
+// ------------------------------
+// helpers
+// ------------------------------
+
+func bangkok() *time.Location {
+	loc, err := time.LoadLocation("Asia/Bangkok")
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.In(bangkok()).Format("02/01/2006 15:04")
+}
+
+func orDash(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return "-"
+	}
+	return s
+}
+
+// loadFonts อ่านไฟล์ฟอนต์แล้ว cache ไว้ (bold ไม่มีก็ใช้ regular แทน)
+// ถ้ายังไม่มีไฟล์จะไม่ cache ความผิดพลาด: วางฟอนต์เพิ่มภายหลังได้โดยไม่ต้อง restart
+func (s *service) loadFonts() error {
+	s.fontMu.Lock()
+	defer s.fontMu.Unlock()
+
+	if s.fontRegular != nil {
+		return nil
+	}
+	regular, err := os.ReadFile(filepath.Join(s.fontDir, fontRegularFile))
+	if err != nil || len(regular) == 0 {
+		return ErrFontNotFound
+	}
+	bold, err := os.ReadFile(filepath.Join(s.fontDir, fontBoldFile))
+	if err != nil || len(bold) == 0 {
+		bold = regular
+	}
+	s.fontRegular = regular
+	s.fontBold = bold
+	return nil
+}
+
+// ------------------------------
+// load data
+// ------------------------------
+
+type recordData struct {
+	advisor entity.User
+	student entity.User
+	logs    []entity.AdvisorLog
+}
+
+func (s *service) load(ctx context.Context, advisorSutID, studentSutID string) (*recordData, error) {
+	var d recordData
+
+	if err := s.db.WithContext(ctx).
+		Preload("Prefix").
+		Preload("AdvisorProfile").
+		Where("sut_id = ?", advisorSutID).
+		First(&d.advisor).Error; err != nil {
+		return nil, ErrAdvisorNotFound
+	}
+	if d.advisor.AdvisorProfile == nil {
+		return nil, ErrAdvisorNotFound
+	}
+
+	if err := s.db.WithContext(ctx).
+		Preload("Prefix").
+		Preload("Major").
+		Preload("Department").
+		Preload("StudentProfile").
+		Preload("StudentProfile.StudentAcademicRecords", func(db *gorm.DB) *gorm.DB {
+			return db.Order("academic_year asc, semester asc")
+		}).
+		Where("sut_id = ?", studentSutID).
+		First(&d.student).Error; err != nil {
+		return nil, ErrStudentNotFound
+	}
+
+	// 🛡️ ต้องเป็นนักศึกษาในที่ปรึกษาของอาจารย์คนนี้เท่านั้น
+	sp := d.student.StudentProfile
+	if sp == nil || sp.AdvisorProfileID == nil || *sp.AdvisorProfileID != d.advisor.AdvisorProfile.ID {
+		return nil, ErrForbidden
+	}
+
+	// บันทึกที่ไม่ใช่ Draft ของนักศึกษาทั้งหมด (รวมนัดกับอาจารย์ที่ปรึกษาคนก่อน) + รายงานความก้าวหน้า + feedback
+	// สิทธิ์อ่านมาจากการเป็นอาจารย์ที่ปรึกษาปัจจุบันของนักศึกษา (ตรวจไว้ด้านบนแล้ว)
+	if err := s.db.WithContext(ctx).
+		Preload("Appointment.Topic").
+		Preload("Appointment.AdvisorUser.Prefix").
+		Preload("ProgressReports", func(db *gorm.DB) *gorm.DB {
+			return db.Order("submitted_at asc")
+		}).
+		Preload("ProgressReports.Feedbacks", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at asc")
+		}).
+		Preload("ProgressReports.Feedbacks.Author.Prefix").
+		Preload("ProgressReports.Feedbacks.Author.Role").
+		Joins("JOIN appointments ON appointments.id = advisor_logs.appointment_id").
+		Where("appointments.student_user_id = ?", d.student.ID).
+		Where("advisor_logs.status <> ?", "Draft").
+		Order("advisor_logs.created_at asc").
+		Find(&d.logs).Error; err != nil {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+// ------------------------------
+// PDF
+// ------------------------------
+
+func (s *service) StudentRecordPDF(ctx context.Context, advisorSutID, studentSutID string) ([]byte, error) {
+	if err := s.loadFonts(); err != nil {
+		return nil, err
+	}
+
+	d, err := s.load(ctx, advisorSutID, studentSutID)
+	if err != nil {
+		return nil, err
+	}
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes(fontFamily, "", s.fontRegular)
+	pdf.AddUTF8FontFromBytes(fontFamily, "B", s.fontBold)
+	pdf.SetTitle(fmt.Sprintf("Advising record %s", d.student.SutId), true)
+	pdf.SetAuthor(fullName(d.advisor), true)
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.AliasNbPages("{nb}")
+
+	generatedAt := time.Now().In(bangkok()).Format("02/01/2006 15:04")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont(fontFamily, "", 9)
+		pdf.SetTextColor(120, 120, 120)
+		pdf.CellFormat(90, 5, fmt.Sprintf("พิมพ์เมื่อ %s", generatedAt), "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 5, fmt.Sprintf("หน้า %d/{nb}", pdf.PageNo()), "", 0, "R", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	})
+
+	pdf.AddPage()
+
+	// หัวเอกสาร
+	pdf.SetFont(fontFamily, "B", 18)
+	pdf.CellFormat(0, 10, "บันทึกการให้คำปรึกษานักศึกษา", "", 1, "C", false, 0, "")
+	pdf.SetFont(fontFamily, "", 12)
+	pdf.CellFormat(0, 7, fmt.Sprintf("อาจารย์ที่ปรึกษา: %s", fullName(d.advisor)), "", 1, "C", false, 0, "")
+	pdf.Ln(4)
+
+	writeProfile(pdf, d.student)
+	writeGPAHistory(pdf, d.student)
+	writeLogs(pdf, d.logs)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func fullName(u entity.User) string {
+	prefix := ""
+	if u.Prefix != nil {
+		prefix = u.Prefix.Prefix
+	}
+	return strings.TrimSpace(prefix + u.FirstName + " " + u.LastName)
+}
+
+func sectionTitle(pdf *fpdf.Fpdf, title string) {
+	pdf.Ln(2)
+	pdf.SetFont(fontFamily, "B", 14)
+	pdf.SetFillColor(230, 236, 245)
+	pdf.CellFormat(0, 8, title, "", 1, "L", true, 0, "")
+	pdf.Ln(1)
+}
+
+func keyValue(pdf *fpdf.Fpdf, key, value string) {
+	pdf.SetFont(fontFamily, "B", 12)
+	pdf.CellFormat(45, 7, key, "", 0, "L", false, 0, "")
+	pdf.SetFont(fontFamily, "", 12)
+	pdf.MultiCell(0, 7, orDash(value), "", "L", false)
+}
+
+func writeProfile(pdf *fpdf.Fpdf, st entity.User) {
+	sectionTitle(pdf, "1. ข้อมูลนักศึกษา")
+
+	major, department := "", ""
+	if st.Major != nil {
+		major = st.Major.Major
+	}
+	if st.Department != nil {
+		department = st.Department.Department
+	}
+	year := ""
+	if st.StudentProfile != nil && st.StudentProfile.YearOfStudy > 0 {
+		year = fmt.Sprint(st.StudentProfile.YearOfStudy)
+	}
+
+	keyValue(pdf, "รหัสนักศึกษา", st.SutId)
+	keyValue(pdf, "ชื่อ-นามสกุล", fullName(st))
+	keyValue(pdf, "สาขาวิชา", major)
+	keyValue(pdf, "สำนักวิชา", department)
+	keyValue(pdf, "ชั้นปี", year)
+	keyValue(pdf, "อีเมล", st.Email)
+	keyValue(pdf, "โทรศัพท์", st.Phone)
+}
+
+func writeGPAHistory(pdf *fpdf.Fpdf, st entity.User) {
+	sectionTitle(pdf, "2. ผลการเรียน")
+
+	var records []entity.StudentAcademicRecord
+	if st.StudentProfile != nil {
+		records = st.StudentProfile.StudentAcademicRecords
+	}
+	if len(records) == 0 {
+		pdf.SetFont(fontFamily, "", 12)
+		pdf.CellFormat(0, 7, "ไม่มีข้อมูลผลการเรียน", "", 1, "L", false, 0, "")
+		return
+	}
+
+	widths := []float64{35, 25, 30, 30, 60}
+	headers := []string{"ปีการศึกษา", "ภาคการศึกษา", "GPA", "GPAX", "สถานะ"}
+
+	pdf.SetFont(fontFamily, "B", 12)
+	pdf.SetFillColor(245, 245, 245)
+	for i, h := range headers {
+		pdf.CellFormat(widths[i], 7, h, "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont(fontFamily, "", 12)
+	for _, r := range records {
+		pdf.CellFormat(widths[0], 7, r.AcademicYear, "1", 0, "C", false, 0, "")
+		pdf.CellFormat(widths[1], 7, fmt.Sprint(r.Semester), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(widths[2], 7, fmt.Sprintf("%.2f", r.TermGPA), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(widths[3], 7, fmt.Sprintf("%.2f", r.CumulativeGPA), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(widths[4], 7, orDash(r.AcademicStatus), "1", 0, "L", false, 0, "")
+		pdf.Ln(-1)
+	}
+}
+
+func writeLogs(pdf *fpdf.Fpdf, logs []entity.AdvisorLog) {
+	sectionTitle(pdf, "3. บันทึกการให้คำปรึกษา")
+
+	if len(logs) == 0 {
+		pdf.SetFont(fontFamily, "", 12)
+		pdf.CellFormat(0, 7, "ยังไม่มีบันทึกการให้คำปรึกษา", "", 1, "L", false, 0, "")
+		return
+	}
+
+	for i, l := range logs {
+		topic, advisor := "", ""
+		if l.Appointment != nil {
+			topic = l.Appointment.Topic.Topic
+			advisor = fullName(l.Appointment.AdvisorUser)
+		}
+
+		pdf.SetFont(fontFamily, "B", 13)
+		pdf.MultiCell(0, 7, fmt.Sprintf("3.%d %s", i+1, l.Title), "", "L", false)
+		pdf.SetFont(fontFamily, "", 11)
+		pdf.SetTextColor(90, 90, 90)
+		pdf.MultiCell(0, 6, fmt.Sprintf("วันที่ %s  |  อาจารย์: %s  |  หัวข้อนัดหมาย: %s  |  สถานะ: %s",
+			formatDate(l.CreatedAt), orDash(advisor), orDash(topic), l.Status), "", "L", false)
+		pdf.SetTextColor(0, 0, 0)
+
+		pdf.SetFont(fontFamily, "", 12)
+		pdf.MultiCell(0, 6, orDash(l.Body), "", "L", false)
+
+		for j, r := range l.ProgressReports {
+			pdf.SetX(22)
+			pdf.SetFont(fontFamily, "B", 12)
+			pdf.CellFormat(0, 6, fmt.Sprintf("รายงานความก้าวหน้า #%d (%s) ส่งเมื่อ %s",
+				j+1, r.Status, formatDate(r.SubmittedAt)), "", 1, "L", false, 0, "")
+			pdf.SetX(22)
+			pdf.SetFont(fontFamily, "", 12)
+			pdf.MultiCell(0, 6, orDash(r.Body), "", "L", false)
+
+			writeFeedbacks(pdf, r.Feedbacks)
+		}
+		pdf.Ln(3)
+	}
+}
+
+// feedbackLabel ป้ายผู้เขียนตามบทบาท (feedback เก่าที่ไม่มีผู้เขียนมาจากอาจารย์เท่านั้น)
+func feedbackLabel(fb entity.ReportFeedback) string {
+	if fb.Author == nil {
+		return "ความเห็นอาจารย์"
+	}
+	role := ""
+	if fb.Author.Role != nil {
+		role = strings.ToLower(fb.Author.Role.Role)
+	}
+	switch role {
+	case "student":
+		return "ข้อความจากนักศึกษา " + fullName(*fb.Author)
+	case "advisor":
+		return "ความเห็นอาจารย์ " + fullName(*fb.Author)
+	}
+	return fullName(*fb.Author)
+}
+
+// writeFeedbacks เขียน feedback เป็น thread: ตอบกลับย่อหน้าอยู่ใต้ข้อความที่ตอบ
+// (ตอบกลับที่หาต้นทางไม่เจอจะขึ้นเป็นหัวข้อ เหมือน progressreport.BuildThreads)
+func writeFeedbacks(pdf *fpdf.Fpdf, fbs []entity.ReportFeedback) {
+	known := make(map[uint]bool, len(fbs))
+	for _, fb := range fbs {
+		known[fb.ID] = true
+	}
+	children := map[uint][]entity.ReportFeedback{}
+	var roots []entity.ReportFeedback
+	for _, fb := range fbs {
+		if fb.ParentID != nil && known[*fb.ParentID] && *fb.ParentID != fb.ID {
+			children[*fb.ParentID] = append(children[*fb.ParentID], fb)
+			continue
+		}
+		roots = append(roots, fb)
+	}
+
+	seen := map[uint]bool{}
+	var write func(fb entity.ReportFeedback, depth int)
+	write = func(fb entity.ReportFeedback, depth int) {
+		seen[fb.ID] = true
+		pdf.SetX(29 + float64(min(depth, 4))*6)
+		pdf.MultiCell(0, 6, fmt.Sprintf("• %s (%s): %s", feedbackLabel(fb), formatDate(fb.CreatedAt), fb.Body), "", "L", false)
+		for _, c := range children[fb.ID] {
+			if !seen[c.ID] {
+				write(c, depth+1)
+			}
+		}
+	}
+	for _, r := range roots {
+		write(r, 0)
+	}
+}
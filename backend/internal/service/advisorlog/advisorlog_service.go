@@ -2,30 +2,42 @@ package advisorlog
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
-	"io"
-	"mime/multipart"
-	"os"
-	"path/filepath"
 	"strings"
+	"time"
 	"github.com/asaskevich/govalidator"
-	"github.com/google/uuid"
 	"gorm.io/gorm"
 
 	"backend/internal/app/dto"
 	"backend/internal/app/entity"
+	"backend/internal/app/queryspec"
+	"backend/internal/service/fileasset"
+	"backend/internal/service/logtemplate"
 )
 
 type Service interface {
     // ✅ เพิ่ม requesterID เพื่อเช็คว่าเป็นเจ้าของนัดหมายจริงไหม
 	Create(ctx context.Context, req dto.AdvisorLogCreateReq, requesterID uint, requesterRole string) (*dto.AdvisorLogCreateResp, error)
 	GetByID(ctx context.Context, id uint, requesterID uint, requesterRole string) (*dto.AdvisorLogGetResp, error)
-	ListByStudent(ctx context.Context, studentUserID uint, requesterRole string) ([]dto.AdvisorLogListItemResp, error)
-	ListAll(ctx context.Context) ([]dto.AdvisorLogListItemResp, error)
-	UpdateStatus(ctx context.Context, id uint, status string) error
+	ListByStudent(ctx context.Context, studentUserID uint, requesterID uint, requesterRole string, f dto.AdvisorLogListFilter, spec queryspec.Spec) (*dto.AdvisorLogListResp, error)
+	// ListAll บันทึกในขอบเขตของผู้เรียก (advisor: นัดของตัวเอง/นักศึกษาในที่ปรึกษา, admin: หน่วยงานตัวเอง)
+	ListAll(ctx context.Context, f dto.AdvisorLogListFilter, spec queryspec.Spec, requesterID uint, requesterRole string) (*dto.AdvisorLogListResp, error)
+	UpdateStatus(ctx context.Context, id uint, status string, requesterID uint, requesterRole string) error
+	// CompleteWithFollowUp ปิดบันทึกพร้อมสร้างนัดติดตามผล (อาจารย์ของนัดเท่านั้น ดู followup.go)
+	CompleteWithFollowUp(ctx context.Context, id uint, req dto.FollowUpReq, requesterID uint, requesterRole string) (*dto.FollowUpResp, error)
     // ✅ เพิ่ม requesterID/Role เพื่อเช็คสิทธิ์ก่อนแก้
 	Update(ctx context.Context, id uint, req dto.AdvisorLogUpdateReq, requesterID uint, requesterRole string) (*dto.AdvisorLogUpdateResp, error)
-	GetFileForLog(ctx context.Context, logID uint, index int, sutID string) (string, string, error)
+	// GetFileForLog ตรวจสิทธิ์แล้วคืนไฟล์ลำดับที่ index (เปิดไฟล์ด้วย fileasset.Service.Open)
+	GetFileForLog(ctx context.Context, logID uint, index int, sutID string) (*entity.FileAssets, error)
+
+	// ประวัติการแก้ไข (ดู revision.go)
+	ListRevisions(ctx context.Context, logID uint, spec queryspec.Spec, requesterID uint, requesterRole string) (*dto.AdvisorLogRevisionListResp, error)
+	DiffRevisions(ctx context.Context, logID uint, from, to int, requesterID uint, requesterRole string) (*dto.AdvisorLogRevisionDiffResp, error)
+	RestoreRevision(ctx context.Context, logID uint, revision int, requesterID uint, requesterRole string) (*dto.AdvisorLogUpdateResp, error)
+
+	// จำนวนบันทึกต่อ tag (advisor เห็นของตัวเอง, admin เห็นทั้งหมด)
+	TagStats(ctx context.Context, f dto.AdvisorLogTagStatsFilter, requesterID uint, requesterRole string) (*dto.LogTagStatsResp, error)
 }
 
 var (
@@ -35,57 +47,79 @@ var (
 	ErrAppointmentNotCompletedYet = errors.New("cannot create log: appointment is not completed yet")
 	ErrForbidden                  = errors.New("forbidden")
 	ErrFileNotFound               = errors.New("file not found")
+	ErrInvalidTemplateValues      = errors.New("templateValues must be a JSON object")
+	ErrTemplateRequired           = errors.New("templateValues requires templateId")
+	ErrBodyRequired               = errors.New("body is required")
+	ErrReportPendingReview        = errors.New("a progress report is awaiting review: use POST /progress_reports/:id/review")
 )
 
 type service struct {
-	db        *gorm.DB
-	uploadDir string
+	db    *gorm.DB
+	files fileasset.Service
 }
 
-func New(db *gorm.DB, uploadDir string) Service {
-	if uploadDir == "" {
-		uploadDir = "uploads"
-	}
-	return &service{db: db, uploadDir: uploadDir}
+func New(db *gorm.DB, files fileasset.Service) Service {
+	return &service{db: db, files: files}
 }
 
 // ------------------------------
 // helpers
 // ------------------------------
 
-func (s *service) cleanupFiles(paths []string) {
-	for _, p := range paths {
-		if p == "" {
-			continue
-		}
-		_ = os.Remove(p)
-	}
+// preloadFiles โหลดไฟล์แนบเรียงตามลำดับที่อัปโหลด
+func preloadFiles(db *gorm.DB) *gorm.DB {
+	return db.Order("position asc, id asc")
 }
 
-func writeFile(dest string, r io.Reader) error {
-	f, err := os.Create(dest)
-	if err != nil {
-		return err
+func preloadTags(db *gorm.DB) *gorm.DB {
+	return db.Order("log_tags.label asc")
+}
+
+func toTagResp(tags []entity.LogTag) []dto.LogTagResp {
+	out := make([]dto.LogTagResp, 0, len(tags))
+	for _, t := range tags {
+		out = append(out, dto.LogTagResp{ID: t.ID, Name: t.Name, Label: t.Label})
 	}
-	defer f.Close()
+	return out
+}
 
-	_, err = io.Copy(f, r)
-	return err
+// applyListFilter กรองตาม tag (ต้องมีครบทุกตัว) และหัวข้อนัด
+func applyListFilter(q *gorm.DB, f dto.AdvisorLogListFilter) *gorm.DB {
+	if tags := logtemplate.NormalizeTags(f.Tags); len(tags) > 0 {
+		q = q.Where(`advisor_logs.id IN (
+			SELECT alt.advisor_log_id FROM advisor_log_tags alt
+			JOIN log_tags ON log_tags.id = alt.log_tag_id
+			WHERE log_tags.name IN ?
+			GROUP BY alt.advisor_log_id
+			HAVING COUNT(DISTINCT log_tags.id) = ?)`, tags, len(tags))
+	}
+	if f.TopicID != 0 {
+		q = q.Where("advisor_logs.appointment_id IN (SELECT id FROM appointments WHERE topic_id = ?)", f.TopicID)
+	}
+	return q
 }
 
-func splitCsv(s string) []string {
-	if s == "" {
-		return nil
+// parseTemplateValues แปลง JSON ของค่าที่กรอกในแบบฟอร์ม
+func parseTemplateValues(raw string) (map[string]any, error) {
+	values := map[string]any{}
+	if strings.TrimSpace(raw) == "" {
+		return values, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &values); err != nil || values == nil {
+		return nil, ErrInvalidTemplateValues
 	}
-	parts := strings.Split(s, ",")
-	out := make([]string, 0, len(parts))
-	for _, p := range parts {
-		p = strings.TrimSpace(p)
-		if p != "" {
-			out = append(out, p)
+	return values, nil
+}
+
+// ownSize ขนาดไฟล์เดิมที่ผู้ใช้คนนี้อัปโหลด (จะถูกแทนที่ จึงไม่นับในโควต้า)
+func ownSize(assets []entity.FileAssets, uploaderID uint) int64 {
+	var mine []entity.FileAssets
+	for _, a := range assets {
+		if a.UploaderID != nil && *a.UploaderID == uploaderID {
+			mine = append(mine, a)
 		}
 	}
-	return out
+	return fileasset.SizeOf(mine)
 }
 
 func toBase(log entity.AdvisorLog) dto.AdvisorLogRespBase {
@@ -96,38 +130,32 @@ func toBase(log entity.AdvisorLog) dto.AdvisorLogRespBase {
 		Body:           log.Body,
 		Status:         log.Status,
 		RequiresReport: log.RequiresReport,
-		FileName:       log.FileName,
-		FilePath:       log.FilePath,
+		FileName:       joinNames(log.Files),
+		Files:          fileasset.ToResp(log.Files),
+		TemplateID:     log.TemplateID,
+		TemplateValues: log.TemplateValues,
+		Tags:           toTagResp(log.Tags),
+		ReportDueAt:    formatDue(log.ReportDueAt),
         // ✅ แก้ไข: เพิ่ม Date Mapping
         CreatedAt:      log.CreatedAt.Format("2006-01-02 15:04:05"),
         UpdatedAt:      log.UpdatedAt.Format("2006-01-02 15:04:05"),
 	}
 }
 
-// saveFiles: ปิด src ให้ถูกต้อง (ไม่ defer ใน loop)
-func (s *service) saveFiles(files []*multipart.FileHeader, orig *[]string, paths *[]string) error {
-	for _, fh := range files {
-		ext := filepath.Ext(fh.Filename)
-		if ext == "" {
-			ext = ".bin"
-		}
-		dest := filepath.Join(s.uploadDir, uuid.New().String()+ext)
-
-		src, err := fh.Open()
-		if err != nil {
-			return ErrSaveFileFailed
-		}
-
-		if err := writeFile(dest, src); err != nil {
-			_ = src.Close()
-			return ErrSaveFileFailed
-		}
-		_ = src.Close()
+func formatDue(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	v := t.Format(time.RFC3339)
+	return &v
+}
 
-		*orig = append(*orig, fh.Filename)
-		*paths = append(*paths, dest)
+func joinNames(files []entity.FileAssets) string {
+	names := make([]string, 0, len(files))
+	for _, f := range files {
+		names = append(names, f.OriginalName)
 	}
-	return nil
+	return strings.Join(names, ",")
 }
 
 // ------------------------------
@@ -161,12 +189,48 @@ func (s *service) Create(ctx context.Context, req dto.AdvisorLogCreateReq, reque
 		return nil, ErrAppointmentNotCompletedYet
 	}
 
+	// บันทึกส่วนตัวเขียนได้เฉพาะอาจารย์ที่ปรึกษาของนัด
+	if req.PrivateNotes != "" && !CanSeePrivateNotes(&appt, requesterID, requesterRole) {
+		return nil, ErrPrivateNotesForbidden
+	}
+
 	// 2. สร้าง Log Object
 	log := entity.AdvisorLog{
 		AppointmentID:  req.AppointmentID,
 		Title:          req.Title,
 		Body:           req.Body,
 		RequiresReport: req.RequiresReport,
+		PrivateNotes:   req.PrivateNotes,
+	}
+
+	// แบบฟอร์มของหัวข้อนัด: ตรวจค่าที่กรอก ถ้าไม่ได้เขียน body เองให้สร้างจากค่าที่กรอก
+	values, err := parseTemplateValues(req.TemplateValues)
+	if err != nil {
+		return nil, err
+	}
+	if req.TemplateID != 0 {
+		tmpl, err := logtemplate.LoadTemplate(s.db.WithContext(ctx), req.TemplateID, appt.TopicID)
+		if err != nil {
+			return nil, err
+		}
+		if values, err = logtemplate.ValidateValues(*tmpl, values); err != nil {
+			return nil, err
+		}
+		log.TemplateID = &tmpl.ID
+		log.TemplateValues = values
+		if strings.TrimSpace(log.Body) == "" {
+			log.Body = logtemplate.RenderBody(*tmpl, values)
+		}
+	} else if len(values) > 0 {
+		return nil, ErrTemplateRequired
+	}
+	if strings.TrimSpace(log.Body) == "" {
+		return nil, ErrBodyRequired
+	}
+
+	// tag ต้องอยู่ในคำศัพท์ที่ admin กำหนด
+	if log.Tags, err = logtemplate.ResolveTags(s.db.WithContext(ctx), req.Tags); err != nil {
+		return nil, err
 	}
 
 	// status logic
@@ -184,116 +248,92 @@ func (s *service) Create(ctx context.Context, req dto.AdvisorLogCreateReq, reque
 		return nil, err
 	}
 
-	// upload files
-	_ = os.MkdirAll(s.uploadDir, 0755)
-
-	var savedOrig, savedPaths []string
-	if err := s.saveFiles(req.Files, &savedOrig, &savedPaths); err != nil {
-		s.cleanupFiles(savedPaths)
+	// ตรวจชนิด/ขนาด/โควต้าก่อนเขียนไฟล์
+	if err := s.files.Validate(ctx, fileasset.PolicyAdvisorLog, req.Files, requesterID, 0); err != nil {
 		return nil, err
 	}
 
-	if len(savedPaths) > 0 {
-		log.FileName = strings.Join(savedOrig, ",")
-		log.FilePath = strings.Join(savedPaths, ",")
+	// upload files (เขียนไฟล์ก่อน แล้วบันทึก log + file_assets ใน transaction เดียว)
+	assets, err := s.files.StoreAll(req.Files, requesterID)
+	if err != nil {
+		return nil, ErrSaveFileFailed
 	}
 
-	if err := s.db.WithContext(ctx).Create(&log).Error; err != nil {
-		s.cleanupFiles(savedPaths)
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Tags.* = ผูก tag ที่มีอยู่แล้ว ไม่สร้าง/แก้แถว log_tags
+		if err := tx.Omit("Tags.*").Create(&log).Error; err != nil {
+			return err
+		}
+		if err := s.files.Attach(tx, assets, entity.FileOwnerAdvisorLog, log.ID); err != nil {
+			return err
+		}
+		_, err := s.recordRevision(tx, log, assets, entity.RevisionActionCreate, &requesterID, nil)
+		return err
+	})
+	if err != nil {
+		s.files.Discard(assets)
 		return nil, err
 	}
+	log.Files = assets
+	log.Appointment = &appt
 
 	return &dto.AdvisorLogCreateResp{
-		AdvisorLogRespBase: toBase(log),
+		AdvisorLogRespBase: ToResp(log, requesterID, requesterRole),
 	}, nil
 }
 
 // ------------------------------
-// LIST BY STUDENT ID (🔒 Secure)
+// LIST BY STUDENT ID (🔒 Secure: จำกัดตามขอบเขตของผู้เรียก ดู scope.go)
 // ------------------------------
-func (s *service) ListByStudent(ctx context.Context, studentUserID uint, requesterRole string) ([]dto.AdvisorLogListItemResp, error) {
-	var logs []entity.AdvisorLog
-
-	query := s.db.WithContext(ctx).
-		Joins("JOIN appointments ON appointments.id = advisor_logs.appointment_id").
-		Where("appointments.student_user_id = ?", studentUserID)
-
-	// กรอง Draft ออก ถ้านักศึกษาเรียก
-	if strings.ToLower(requesterRole) == "student" {
-		query = query.Where("advisor_logs.status != ?", "Draft")
+func (s *service) ListByStudent(ctx context.Context, studentUserID uint, requesterID uint, requesterRole string, f dto.AdvisorLogListFilter, spec queryspec.Spec) (*dto.AdvisorLogListResp, error) {
+	// นักศึกษาดูได้แค่ของตัวเอง
+	if normRole(requesterRole) == "student" && studentUserID != requesterID {
+		return nil, ErrForbidden
 	}
 
-	err := query.Order("advisor_logs.id desc").Find(&logs).Error
+	query, err := s.scoped(ctx, requesterID, requesterRole)
 	if err != nil {
 		return nil, err
 	}
+	query = query.Where("appointments.student_user_id = ?", studentUserID)
 
-	out := make([]dto.AdvisorLogListItemResp, 0, len(logs))
-	for _, l := range logs {
-		out = append(out, dto.AdvisorLogListItemResp{
-			AdvisorLogRespBase: toBase(l),
-		})
-	}
-	return out, nil
+	return s.list(ctx, query, f, spec, requesterID, requesterRole)
 }
 
 // ------------------------------
 // GET BY ID (🔒 Secure)
 // ------------------------------
 func (s *service) GetByID(ctx context.Context, id uint, requesterID uint, requesterRole string) (*dto.AdvisorLogGetResp, error) {
-	var log entity.AdvisorLog
-	
-	if err := s.db.WithContext(ctx).
-		Preload("Appointment").
-		First(&log, id).Error; err != nil {
-
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, ErrAdvisorLogNotFound
-		}
+	log, err := s.loadLog(ctx, id)
+	if err != nil {
 		return nil, err
 	}
 
-	// 🛡️ Logic ความปลอดภัย
-	if strings.ToLower(requesterRole) == "student" {
-		if log.Status == "Draft" {
-			return nil, ErrAdvisorLogNotFound
-		}
-		if log.Appointment == nil || log.Appointment.StudentUserID != requesterID {
-			return nil, ErrForbidden
-		}
+	// 🛡️ นักศึกษา: เฉพาะของตัวเองที่ไม่ใช่ Draft / อาจารย์, admin: ตามขอบเขต
+	if err := s.authorizeRead(ctx, *log, requesterID, requesterRole); err != nil {
+		return nil, err
 	}
-	
+
 	return &dto.AdvisorLogGetResp{
-		AdvisorLogRespBase: toBase(log),
+		AdvisorLogRespBase: ToResp(*log, requesterID, requesterRole),
 	}, nil
 }
 
 // ------------------------------
-// LIST ALL (advisor)
+// LIST ALL (เฉพาะบันทึกในขอบเขตของผู้เรียก)
 // ------------------------------
-func (s *service) ListAll(ctx context.Context) ([]dto.AdvisorLogListItemResp, error) {
-	var logs []entity.AdvisorLog
-	err := s.db.WithContext(ctx).
-		Preload("Appointment").
-		Order("advisor_logs.id desc").
-		Find(&logs).Error
+func (s *service) ListAll(ctx context.Context, f dto.AdvisorLogListFilter, spec queryspec.Spec, requesterID uint, requesterRole string) (*dto.AdvisorLogListResp, error) {
+	query, err := s.scoped(ctx, requesterID, requesterRole)
 	if err != nil {
 		return nil, err
 	}
-
-	out := make([]dto.AdvisorLogListItemResp, 0, len(logs))
-	for _, l := range logs {
-		out = append(out, dto.AdvisorLogListItemResp{
-			AdvisorLogRespBase: toBase(l),
-		})
-	}
-	return out, nil
+	return s.list(ctx, query, f, spec, requesterID, requesterRole)
 }
 
 // ------------------------------
-// UPDATE STATUS ONLY
+// UPDATE STATUS ONLY (🔒 อาจารย์ที่ปรึกษาของนัด หรือ admin ของหน่วยงาน)
 // ------------------------------
-func (s *service) UpdateStatus(ctx context.Context, id uint, status string) error {
+func (s *service) UpdateStatus(ctx context.Context, id uint, status string, requesterID uint, requesterRole string) error {
 	allowed := map[string]bool{
 		"Draft":         true,
 		"PendingReport": true,
@@ -303,41 +343,103 @@ func (s *service) UpdateStatus(ctx context.Context, id uint, status string) erro
 		return ErrInvalidStatus
 	}
 
+	log, err := s.loadLog(ctx, id)
+	if err != nil {
+		return err
+	}
+	switch normRole(requesterRole) {
+	case "advisor":
+		if log.Appointment == nil || log.Appointment.AdvisorUserID != requesterID {
+			return ErrForbidden
+		}
+	case "admin":
+		ok, err := s.inScope(ctx, log.ID, requesterID, requesterRole)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrForbidden
+		}
+	default:
+		return ErrForbidden
+	}
+	if err := CheckStatusChange(log.Status); err != nil {
+		return err
+	}
+
+	// เงื่อนไข status กันการเปลี่ยนแข่งกับการส่งรายงานที่เกิดขึ้นระหว่างนี้
 	res := s.db.WithContext(ctx).
 		Model(&entity.AdvisorLog{}).
-		Where("id = ?", id).
+		Where("id = ? AND status <> ?", id, "ReportSubmitted").
 		Update("status", status)
 
 	if res.Error != nil {
 		return res.Error
 	}
 	if res.RowsAffected == 0 {
-		return ErrAdvisorLogNotFound
+		return ErrReportPendingReview
 	}
 	return nil
 }
 
+// CheckStatusChange บันทึกที่มีรายงานรอตรวจ (ReportSubmitted) เปลี่ยนสถานะตรงไม่ได้
+// ต้องผ่าน POST /progress_reports/:id/review เพื่อปิดรายงานฉบับนั้นก่อน
+func CheckStatusChange(current string) error {
+	if current == "ReportSubmitted" {
+		return ErrReportPendingReview
+	}
+	return nil
+}
+
+// CheckEditor ผู้แก้เนื้อหาบันทึก (หัวข้อ/เนื้อหา/ไฟล์): อาจารย์ของนัด หรือ admin (ขอบเขตหน่วยงานตรวจแยก)
+// นักศึกษาแก้ไม่ได้ และ Draft ที่ยังไม่เห็นต้องตอบ not found เหมือน GetByID ไม่ให้รู้ว่ามีอยู่
+func CheckEditor(log entity.AdvisorLog, requesterID uint, requesterRole string) error {
+	switch normRole(requesterRole) {
+	case "student":
+		if err := canRead(log, requesterID, requesterRole); err != nil {
+			return err
+		}
+		return ErrForbidden
+	case "advisor":
+		if log.Appointment == nil || log.Appointment.AdvisorUserID != requesterID {
+			return ErrForbidden
+		}
+		return nil
+	case "admin":
+		return nil
+	}
+	return ErrForbidden
+}
+
 // ------------------------------
 // UPDATE FULL LOG (🔒 Secure: เช็คเจ้าของก่อนแก้)
 // ------------------------------
 func (s *service) Update(ctx context.Context, id uint, req dto.AdvisorLogUpdateReq, requesterID uint, requesterRole string) (*dto.AdvisorLogUpdateResp, error) {
 	var log entity.AdvisorLog
-	if err := s.db.WithContext(ctx).Preload("Appointment").First(&log, id).Error; err != nil {
+	if err := s.db.WithContext(ctx).Preload("Appointment").Preload("Files", preloadFiles).Preload("Tags", preloadTags).First(&log, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrAdvisorLogNotFound
 		}
 		return nil, err
 	}
 
-    // 🛡️ Security Check:
-    if strings.ToLower(requesterRole) == "student" {
-        // 1. ต้องเป็นเจ้าของ Appointment
-        if log.Appointment == nil || log.Appointment.StudentUserID != requesterID {
-            return nil, ErrForbidden
-        }
-        // 2. (Optional) ถ้า Completed แล้ว ห้ามแก้? หรือถ้าเป็น Draft เท่านั้นถึงแก้ได้?
-        // (ตรงนี้แล้วแต่ Business Logic ของคุณ ปกติถ้านักศึกษาแก้ได้ตลอดก็ไม่ต้องเช็ค Status)
-    }
+	// 🛡️ Security Check: แก้เนื้อหาได้เฉพาะอาจารย์ของนัด, admin ตามขอบเขตหน่วยงาน
+	if err := CheckEditor(log, requesterID, requesterRole); err != nil {
+		return nil, err
+	}
+	if normRole(requesterRole) == "admin" {
+		ok, err := s.inScope(ctx, log.ID, requesterID, requesterRole)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, ErrForbidden
+		}
+	}
+
+	// สถานะก่อนแก้ (ใช้เป็น baseline ของ log เก่าที่ยังไม่มี revision)
+	before := log
+	editor := requesterID
 
 	// update text fields
 	if req.Title != nil && *req.Title != "" {
@@ -349,83 +451,90 @@ func (s *service) Update(ctx context.Context, id uint, req dto.AdvisorLogUpdateR
 	if req.RequiresReport != nil {
 		log.RequiresReport = *req.RequiresReport
 	}
+	if req.PrivateNotes != nil {
+		if !CanSeePrivateNotes(log.Appointment, requesterID, requesterRole) {
+			return nil, ErrPrivateNotesForbidden
+		}
+		log.PrivateNotes = *req.PrivateNotes
+	}
 
-	// Files Logic (เหมือนเดิม)
+	// Files: ส่งไฟล์ใหม่มา = แทนที่ชุดเดิมทั้งหมด
+	// ไฟล์เดิมถูก soft delete แต่ไม่ลบไฟล์จริง เพราะ revision ก่อนหน้ายังอ้างถึง (กู้คืนได้)
 	if len(req.Files) > 0 {
-		_ = os.MkdirAll(s.uploadDir, 0755)
+		current, err := s.files.ListByOwner(ctx, entity.FileOwnerAdvisorLog, log.ID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.files.Validate(ctx, fileasset.PolicyAdvisorLog, req.Files, requesterID, ownSize(current, requesterID)); err != nil {
+			return nil, err
+		}
 
-		var newNames []string
-		var newPaths []string
+		assets, err := s.files.StoreAll(req.Files, requesterID)
+		if err != nil {
+			return nil, ErrSaveFileFailed
+		}
 
-		for _, fh := range req.Files {
-			ext := filepath.Ext(fh.Filename)
-			if ext == "" {
-				ext = ".bin"
+		err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := s.ensureBaseline(tx, before, before.Files); err != nil {
+				return err
 			}
-			dest := filepath.Join(s.uploadDir, uuid.New().String()+ext)
-
-			src, err := fh.Open()
-			if err != nil {
-				s.cleanupFiles(newPaths)
-				return nil, ErrSaveFileFailed
+			if err := tx.Omit("Files", "Appointment", "Tags", "Template").Save(&log).Error; err != nil {
+				return err
 			}
-
-			if err := writeFile(dest, src); err != nil {
-				_ = src.Close()
-				s.cleanupFiles(newPaths)
-				return nil, ErrSaveFileFailed
+			if _, err := s.files.Detach(tx, entity.FileOwnerAdvisorLog, log.ID); err != nil {
+				return err
 			}
-			_ = src.Close()
-
-			newNames = append(newNames, fh.Filename)
-			newPaths = append(newPaths, dest)
-		}
-
-		var oldPaths []string
-		if log.FilePath != "" {
-			oldPaths = strings.Split(log.FilePath, ",")
-		}
-
-		log.FileName = strings.Join(newNames, ",")
-		log.FilePath = strings.Join(newPaths, ",")
-
-		if err := s.db.WithContext(ctx).Save(&log).Error; err != nil {
-			s.cleanupFiles(newPaths) 
+			if err := s.files.Attach(tx, assets, entity.FileOwnerAdvisorLog, log.ID); err != nil {
+				return err
+			}
+			_, err := s.recordRevision(tx, log, assets, entity.RevisionActionUpdate, &editor, nil)
+			return err
+		})
+		if err != nil {
+			s.files.Discard(assets)
 			return nil, err
 		}
-		s.cleanupFiles(oldPaths)
+		log.Files = assets
 
 	} else {
-		if err := s.db.WithContext(ctx).Save(&log).Error; err != nil {
+		err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := s.ensureBaseline(tx, before, before.Files); err != nil {
+				return err
+			}
+			if err := tx.Omit("Files", "Appointment", "Tags", "Template").Save(&log).Error; err != nil {
+				return err
+			}
+			_, err := s.recordRevision(tx, log, log.Files, entity.RevisionActionUpdate, &editor, nil)
+			return err
+		})
+		if err != nil {
 			return nil, err
 		}
 	}
 
 	return &dto.AdvisorLogUpdateResp{
-		AdvisorLogRespBase: toBase(log),
+		AdvisorLogRespBase: ToResp(log, requesterID, requesterRole),
 	}, nil
 }
 
 // ------------------------------
 // GET FILE (Optimization: ถ้าทำได้ ควรใช้ user_id แบบ uint แต่ใช้แบบเดิมก็ไม่ผิด)
 // ------------------------------
-func (s *service) GetFileForLog(ctx context.Context, logID uint, index int, sutID string) (string, string, error) {
-    // ... (Code เดิมส่วนนี้ใช้ได้ครับ ถ้า Controller ส่งมาแค่ sutID) ...
-    // ... (logic การเช็คสิทธิ์ในนี้ถือว่าถูกต้องแล้ว) ...
+func (s *service) GetFileForLog(ctx context.Context, logID uint, index int, sutID string) (*entity.FileAssets, error) {
 	var log entity.AdvisorLog
 	if err := s.db.WithContext(ctx).
 		Preload("Appointment").
 		First(&log, logID).Error; err != nil {
 
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return "", "", ErrAdvisorLogNotFound
+			return nil, ErrAdvisorLogNotFound
 		}
-		return "", "", err
+		return nil, err
 	}
 
 	sutID = strings.TrimSpace(sutID)
 	if sutID == "" {
-		return "", "", ErrForbidden
+		return nil, ErrForbidden
 	}
 
 	var me entity.User
@@ -433,53 +542,24 @@ func (s *service) GetFileForLog(ctx context.Context, logID uint, index int, sutI
 		Preload("Role").
 		Where("sut_id = ?", sutID).
 		First(&me).Error; err != nil {
-		return "", "", ErrForbidden
-	}
-
-	appt := log.Appointment
-	if appt == nil {
-		return "", "", ErrForbidden
-	}
-	if me.Role == nil {
-		return "", "", ErrForbidden
-	}
-
-	role := strings.ToLower(strings.TrimSpace(me.Role.Role))
-	switch role {
-	case "admin":
-		// allow
-	case "advisor":
-		if me.ID != appt.AdvisorUserID {
-			return "", "", ErrForbidden
-		}
-	case "student":
-		if me.ID != appt.StudentUserID {
-			return "", "", ErrForbidden
-		}
-	default:
-		return "", "", ErrForbidden
+		return nil, ErrForbidden
 	}
 
-	paths := splitCsv(log.FilePath)
-	names := splitCsv(log.FileName)
-
-	if index < 0 || index >= len(paths) {
-		return "", "", ErrFileNotFound
+	if log.Appointment == nil || me.Role == nil {
+		return nil, ErrForbidden
 	}
-
-	absPath := strings.TrimSpace(paths[index])
-	if absPath == "" {
-		return "", "", ErrFileNotFound
+	// สิทธิ์เดียวกับการอ่านบันทึก (GetByID): นักศึกษาไม่เห็นไฟล์ของ Draft, admin ตามหน่วยงาน
+	if err := s.authorizeRead(ctx, log, me.ID, me.Role.Role); err != nil {
+		return nil, err
 	}
 
-	fileName := absPath
-	if index < len(names) && strings.TrimSpace(names[index]) != "" {
-		fileName = strings.TrimSpace(names[index])
+	files, err := s.files.ListByOwner(ctx, entity.FileOwnerAdvisorLog, log.ID)
+	if err != nil {
+		return nil, err
 	}
-
-	if _, err := os.Stat(absPath); err != nil {
-		return "", "", ErrFileNotFound
+	if index < 0 || index >= len(files) {
+		return nil, ErrFileNotFound
 	}
 
-	return fileName, absPath, nil
+	return &files[index], nil
 }
\ No newline at end of file
@@ -0,0 +1,95 @@
+package advisorlog
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"backend/internal/app/dto"
+)
+
+var ErrInvalidDateRange = errors.New("invalid date range (YYYY-MM-DD)")
+
+// parseDay อ่านวันที่แบบ YYYY-MM-DD ตามเวลาไทย
+func parseDay(v string) (time.Time, error) {
+	loc, err := time.LoadLocation("Asia/Bangkok")
+	if err != nil {
+		loc = time.Local
+	}
+	return time.ParseInLocation("2006-01-02", v, loc)
+}
+
+// statsScope บันทึกที่ผู้เรียกมีสิทธิ์นับ (ไม่รวม Draft)
+func (s *service) statsScope(ctx context.Context, f dto.AdvisorLogTagStatsFilter, requesterID uint, requesterRole string) (*gorm.DB, error) {
+	q := s.db.WithContext(ctx).
+		Table("advisor_logs").
+		Joins("JOIN appointments ON appointments.id = advisor_logs.appointment_id").
+		Where("advisor_logs.deleted_at IS NULL AND advisor_logs.status <> ?", "Draft")
+
+	switch strings.ToLower(strings.TrimSpace(requesterRole)) {
+	case "admin":
+		var err error
+		if q, err = DepartmentScope(s.db.WithContext(ctx), q, requesterID); err != nil {
+			return nil, err
+		}
+	case "advisor":
+		q = q.Where("appointments.advisor_user_id = ?", requesterID)
+	default:
+		return nil, ErrForbidden
+	}
+
+	if f.From != "" {
+		from, err := parseDay(f.From)
+		if err != nil {
+			return nil, ErrInvalidDateRange
+		}
+		q = q.Where("advisor_logs.created_at >= ?", from)
+	}
+	if f.To != "" {
+		to, err := parseDay(f.To)
+		if err != nil {
+			return nil, ErrInvalidDateRange
+		}
+		q = q.Where("advisor_logs.created_at < ?", to.AddDate(0, 0, 1))
+	}
+	if f.TopicID != 0 {
+		q = q.Where("appointments.topic_id = ?", f.TopicID)
+	}
+	return q, nil
+}
+
+func (s *service) TagStats(ctx context.Context, f dto.AdvisorLogTagStatsFilter, requesterID uint, requesterRole string) (*dto.LogTagStatsResp, error) {
+	scope := func() (*gorm.DB, error) { return s.statsScope(ctx, f, requesterID, requesterRole) }
+
+	out := &dto.LogTagStatsResp{Tags: []dto.LogTagStat{}}
+
+	q, err := scope()
+	if err != nil {
+		return nil, err
+	}
+	if err := q.Count(&out.TotalLogs).Error; err != nil {
+		return nil, err
+	}
+
+	q, _ = scope()
+	if err := q.Where("NOT EXISTS (SELECT 1 FROM advisor_log_tags alt WHERE alt.advisor_log_id = advisor_logs.id)").
+		Count(&out.Untagged).Error; err != nil {
+		return nil, err
+	}
+
+	q, _ = scope()
+	err = q.
+		Joins("JOIN advisor_log_tags alt ON alt.advisor_log_id = advisor_logs.id").
+		Joins("JOIN log_tags ON log_tags.id = alt.log_tag_id").
+		Select("log_tags.id, log_tags.name, log_tags.label, COUNT(DISTINCT advisor_logs.id) AS count").
+		Group("log_tags.id, log_tags.name, log_tags.label").
+		Order("count desc, log_tags.label asc").
+		Scan(&out.Tags).Error
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,182 @@
+package advisorlog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"backend/internal/app/dto"
+	"backend/internal/app/entity"
+)
+
+// นัดติดตามผล: อาจารย์ปิดบันทึก (Completed) พร้อมสร้างนัดใหม่ที่อนุมัติแล้วในคำขอเดียว
+// นัดใหม่ชี้กลับไปนัดต้นทาง (ParentAppointmentID) และบันทึกนี้ (SourceAdvisorLogID)
+// นักศึกษาได้รับ notification FOLLOWUP
+
+var (
+	ErrFollowUpNotCompleted = errors.New("follow-up can only be scheduled when completing the log")
+	ErrFollowUpExists       = errors.New("a follow-up appointment already exists for this appointment")
+	ErrInvalidFollowUpTime  = errors.New("start_at and end_at must be RFC3339, end_at after start_at and in the future")
+	ErrFollowUpTopic        = errors.New("appointment topic not found")
+	ErrFollowUpCategory     = errors.New("appointment category not found")
+)
+
+// ParseFollowUpTime ตรวจช่วงเวลานัดติดตามผล (ต้องอยู่หลัง now)
+func ParseFollowUpTime(startStr, endStr string, now time.Time) (time.Time, time.Time, error) {
+	start, err1 := time.Parse(time.RFC3339, strings.TrimSpace(startStr))
+	end, err2 := time.Parse(time.RFC3339, strings.TrimSpace(endStr))
+	if err1 != nil || err2 != nil || !end.After(start) || !start.After(now) {
+		return time.Time{}, time.Time{}, ErrInvalidFollowUpTime
+	}
+	return start, end, nil
+}
+
+func followUpMessage(advisor entity.User, start time.Time) string {
+	loc, err := time.LoadLocation("Asia/Bangkok")
+	if err != nil {
+		loc = time.Local
+	}
+	return fmt.Sprintf("%s นัดติดตามผลวันที่ %s เวลา %s น.",
+		strings.TrimSpace(advisor.FirstName+" "+advisor.LastName),
+		start.In(loc).Format("02/01/2006"), start.In(loc).Format("15:04"))
+}
+
+// mustExist คืน notFound เมื่อไม่มีแถว id นี้ในตารางของ model
+func (s *service) mustExist(ctx context.Context, model any, id uint, notFound error) error {
+	var count int64
+	if err := s.db.WithContext(ctx).Model(model).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return notFound
+	}
+	return nil
+}
+
+func (s *service) CompleteWithFollowUp(ctx context.Context, id uint, req dto.FollowUpReq, requesterID uint, requesterRole string) (*dto.FollowUpResp, error) {
+	log, err := s.loadLog(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	parent := log.Appointment
+	if normRole(requesterRole) != "advisor" || parent == nil || parent.AdvisorUserID != requesterID {
+		return nil, ErrForbidden
+	}
+	if err := CheckStatusChange(log.Status); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	start, end, err := ParseFollowUpTime(req.StartAt, req.EndAt, now)
+	if err != nil {
+		return nil, err
+	}
+
+	var advisor entity.User
+	if err := s.db.WithContext(ctx).First(&advisor, requesterID).Error; err != nil {
+		return nil, err
+	}
+
+	followUp := entity.Appointment{
+		Description:         strings.TrimSpace(req.Description),
+		StartAt:             &start,
+		EndAt:               &end,
+		AdvisorUserID:       parent.AdvisorUserID,
+		StudentUserID:       parent.StudentUserID,
+		TopicID:             parent.TopicID,
+		CategoryID:          parent.CategoryID,
+		AppointmentStatusID: entity.StatusApprovedID,
+		ParentAppointmentID: &parent.ID,
+		SourceAdvisorLogID:  &log.ID,
+	}
+	// ตรวจก่อนเข้า transaction ไม่งั้น id ที่ไม่มีจริงจะพังที่ foreign key กลายเป็น 500
+	if req.TopicID != 0 {
+		if err := s.mustExist(ctx, &entity.AppointmentTopic{}, req.TopicID, ErrFollowUpTopic); err != nil {
+			return nil, err
+		}
+		followUp.TopicID = req.TopicID
+	}
+	if req.CategoryID != 0 {
+		if err := s.mustExist(ctx, &entity.AppointmentCategory{}, req.CategoryID, ErrFollowUpCategory); err != nil {
+			return nil, err
+		}
+		followUp.CategoryID = req.CategoryID
+	}
+	if followUp.Description == "" {
+		followUp.Description = "นัดติดตามผล: " + log.Title
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// ล็อกนัดต้นทางไว้ กันการสร้างนัดติดตามผลซ้อนกัน (นัดหนึ่งมีนัดติดตามผลได้นัดเดียว)
+		var locked entity.Appointment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&locked, parent.ID).Error; err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&entity.Appointment{}).Where("parent_appointment_id = ?", parent.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrFollowUpExists
+		}
+
+		res := tx.Model(&entity.AdvisorLog{}).
+			Where("id = ? AND status <> ?", log.ID, "ReportSubmitted").
+			Update("status", "Completed")
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrReportPendingReview
+		}
+
+		if err := tx.Omit(clause.Associations).Create(&followUp).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&entity.AppointmentState{
+			AppointmentID:   followUp.ID,
+			CurrentStatusID: entity.StatusApprovedID,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Omit(clause.Associations).Create(&entity.StatusHistory{
+			AppointmentID:   followUp.ID,
+			ChangedByUserID: requesterID,
+			FromStatusID:    entity.StatusPendingID,
+			ToStatusID:      entity.StatusApprovedID,
+			ActionID:        entity.ActionFollowUpID,
+			Reason:          fmt.Sprintf("นัดติดตามผลจากนัด #%d", parent.ID),
+			ChangedAt:       now,
+		}).Error; err != nil {
+			return err
+		}
+
+		return tx.Omit(clause.Associations).Create(&entity.Notification{
+			AppointmentID:   followUp.ID,
+			RecipientUserID: followUp.StudentUserID,
+			SenderUserID:    requesterID,
+			EventType:       entity.EventFollowup,
+			StatusSnapshot:  entity.StatusSnapshotApproved,
+			Topic:           "นัดติดตามผล: " + log.Title,
+			Message:         followUpMessage(advisor, start),
+			SentAt:          &now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.FollowUpResp{
+		AdvisorLogID:        log.ID,
+		Status:              "Completed",
+		AppointmentID:       followUp.ID,
+		ParentAppointmentID: parent.ID,
+		StartAt:             start.Format(time.RFC3339),
+		EndAt:               end.Format(time.RFC3339),
+	}, nil
+}
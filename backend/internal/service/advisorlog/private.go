@@ -0,0 +1,86 @@
+package advisorlog
+
+import (
+	"errors"
+	"strings"
+
+	"backend/internal/app/dto"
+	"backend/internal/app/entity"
+)
+
+// บันทึกส่วนตัวของอาจารย์ (PrivateNotes) แยกจากสรุปการปรึกษา (Body) ที่นักศึกษาเห็น
+// มีแค่อาจารย์ที่ปรึกษาของนัดนั้นที่อ่าน/เขียนได้ (admin และนักศึกษาไม่เห็น)
+// ทุกเส้นทางที่ส่ง log ออกไปต้องผ่าน ToResp / redact ใน package นี้
+
+const FieldPrivateNotes = "private_notes"
+
+var ErrPrivateNotesForbidden = errors.New("only the appointment's advisor can write private notes")
+
+// CanSeePrivateNotes ผู้เรียกเป็นอาจารย์ที่ปรึกษาของนัดนี้หรือไม่
+func CanSeePrivateNotes(appt *entity.Appointment, requesterID uint, requesterRole string) bool {
+	if appt == nil || requesterID == 0 {
+		return false
+	}
+	return strings.ToLower(strings.TrimSpace(requesterRole)) == "advisor" && appt.AdvisorUserID == requesterID
+}
+
+// ToResp แปลง log เป็น DTO ตามสิทธิ์ผู้เรียก (log.Appointment ต้องถูก preload ถ้าต้องการแสดง PrivateNotes)
+func ToResp(log entity.AdvisorLog, requesterID uint, requesterRole string) dto.AdvisorLogRespBase {
+	out := toBase(log)
+	if CanSeePrivateNotes(log.Appointment, requesterID, requesterRole) {
+		notes := log.PrivateNotes
+		out.PrivateNotes = &notes
+	}
+	return out
+}
+
+func withoutPrivate(fields []string) []string {
+	out := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if f != FieldPrivateNotes {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// redactRevision ตัดข้อมูลส่วนตัวออกจาก revision สำหรับผู้ที่ไม่มีสิทธิ์
+// keep = false เมื่อ revision นั้นแก้แค่บันทึกส่วนตัว (ไม่ต้องแสดงเลย)
+func redactRevision(r dto.AdvisorLogRevisionResp) (dto.AdvisorLogRevisionResp, bool) {
+	r.PrivateNotes = nil
+	before := len(r.ChangedFields)
+	r.ChangedFields = withoutPrivate(r.ChangedFields)
+	if before > 0 && len(r.ChangedFields) == 0 && r.Action == entity.RevisionActionUpdate {
+		return r, false
+	}
+	return r, true
+}
+
+// RedactRevisions กรอง revision ตามสิทธิ์ผู้เรียก
+func RedactRevisions(revs []dto.AdvisorLogRevisionResp, showPrivate bool) []dto.AdvisorLogRevisionResp {
+	if showPrivate {
+		return revs
+	}
+	out := make([]dto.AdvisorLogRevisionResp, 0, len(revs))
+	for _, r := range revs {
+		if rr, keep := redactRevision(r); keep {
+			out = append(out, rr)
+		}
+	}
+	return out
+}
+
+// RedactDiff ตัดการเปลี่ยนแปลงของบันทึกส่วนตัวออกจากผลเทียบ
+func RedactDiff(d dto.AdvisorLogRevisionDiffResp, showPrivate bool) dto.AdvisorLogRevisionDiffResp {
+	if showPrivate {
+		return d
+	}
+	changes := make([]dto.AdvisorLogFieldChange, 0, len(d.Changes))
+	for _, c := range d.Changes {
+		if c.Field != FieldPrivateNotes {
+			changes = append(changes, c)
+		}
+	}
+	d.Changes = changes
+	return d
+}
@@ -0,0 +1,534 @@
+package advisorlog
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"backend/internal/app/dto"
+	"backend/internal/app/entity"
+	"backend/internal/app/queryspec"
+	"backend/internal/service/fileasset"
+)
+
+// ประวัติการแก้ไขบันทึก: ทุกครั้งที่สร้าง/แก้ไข/กู้คืน จะเก็บสำเนาเนื้อหาและชุดไฟล์ ณ ตอนนั้นเป็น revision ใหม่
+// ไฟล์ที่ถูกแทนที่จะถูก soft delete แต่ไม่ลบไฟล์จริง เพื่อให้ดู/กู้คืน revision เก่าได้
+
+const (
+	FieldTitle          = "title"
+	FieldBody           = "body"
+	FieldRequiresReport = "requires_report"
+	FieldFiles          = "files"
+)
+
+var (
+	ErrRevisionNotFound   = errors.New("revision not found")
+	ErrRevisionFilesGone  = errors.New("files of this revision are no longer available")
+	ErrNothingToRestore   = errors.New("revision is identical to the current log")
+	ErrInvalidRevisionArg = errors.New("invalid revision number")
+)
+
+// RevisionListQuery sort ที่ประวัติการแก้ไขรับได้ (ค่าเริ่มต้นล่าสุดก่อน)
+var RevisionListQuery = queryspec.Options{
+	Sorts: map[string]string{
+		"id":       "id",
+		"revision": "revision",
+	},
+	DefaultSort: "-revision",
+}
+
+// maxDiffCells จำกัดขนาดตาราง LCS (บรรทัด x บรรทัด) ถ้าเกินจะแสดงเป็นลบทั้งหมด/เพิ่มทั้งหมด
+const maxDiffCells = 4_000_000
+
+// ------------------------------
+// helpers
+// ------------------------------
+
+func fileIDs(files []entity.FileAssets) []uint {
+	ids := make([]uint, 0, len(files))
+	for _, f := range files {
+		ids = append(ids, f.ID)
+	}
+	return ids
+}
+
+func revisionFileIDs(rev entity.AdvisorLogRevision) []uint {
+	ids := make([]uint, 0, len(rev.Files))
+	for _, f := range rev.Files {
+		ids = append(ids, f.FileAssetsID)
+	}
+	return ids
+}
+
+func revisionAssets(rev entity.AdvisorLogRevision) []entity.FileAssets {
+	out := make([]entity.FileAssets, 0, len(rev.Files))
+	for _, f := range rev.Files {
+		if f.FileAssets != nil {
+			out = append(out, *f.FileAssets)
+		}
+	}
+	return out
+}
+
+func sameIDs(a, b []uint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ChangedFields ฟิลด์ที่ต่างกันระหว่าง revision สองอัน (เรียงตามลำดับคงที่)
+func ChangedFields(prev, next entity.AdvisorLogRevision) []string {
+	var out []string
+	if prev.Title != next.Title {
+		out = append(out, FieldTitle)
+	}
+	if prev.Body != next.Body {
+		out = append(out, FieldBody)
+	}
+	if prev.RequiresReport != next.RequiresReport {
+		out = append(out, FieldRequiresReport)
+	}
+	if prev.PrivateNotes != next.PrivateNotes {
+		out = append(out, FieldPrivateNotes)
+	}
+	if !sameIDs(revisionFileIDs(prev), revisionFileIDs(next)) {
+		out = append(out, FieldFiles)
+	}
+	return out
+}
+
+// snapshot สร้าง revision (ยังไม่บันทึก) จากสถานะปัจจุบันของ log
+func snapshot(log entity.AdvisorLog, files []entity.FileAssets) entity.AdvisorLogRevision {
+	rev := entity.AdvisorLogRevision{
+		AdvisorLogID:   log.ID,
+		Title:          log.Title,
+		Body:           log.Body,
+		RequiresReport: log.RequiresReport,
+		PrivateNotes:   log.PrivateNotes,
+	}
+	for i, f := range files {
+		f := f
+		rev.Files = append(rev.Files, entity.AdvisorLogRevisionFile{FileAssetsID: f.ID, Position: i, FileAssets: &f})
+	}
+	return rev
+}
+
+func preloadRevisionFiles(db *gorm.DB) *gorm.DB {
+	return db.Order("position asc, id asc")
+}
+
+// ไฟล์ของ revision เก่าอาจถูก soft delete ไปแล้ว
+func preloadRevisionAssets(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
+
+func (s *service) latestRevision(tx *gorm.DB, logID uint) (*entity.AdvisorLogRevision, error) {
+	var rev entity.AdvisorLogRevision
+	err := tx.
+		Preload("Files", preloadRevisionFiles).
+		Where("advisor_log_id = ?", logID).
+		Order("revision desc").
+		First(&rev).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+func (s *service) findRevision(ctx context.Context, logID uint, number int) (*entity.AdvisorLogRevision, error) {
+	if number <= 0 {
+		return nil, ErrInvalidRevisionArg
+	}
+	var rev entity.AdvisorLogRevision
+	err := s.db.WithContext(ctx).
+		Preload("Editor").
+		Preload("Files", preloadRevisionFiles).
+		Preload("Files.FileAssets", preloadRevisionAssets).
+		Where("advisor_log_id = ? AND revision = ?", logID, number).
+		First(&rev).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRevisionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// recordRevision บันทึกสถานะปัจจุบันเป็น revision ใหม่ ถ้าไม่มีอะไรเปลี่ยนจาก revision ล่าสุดจะไม่บันทึก (คืน nil)
+func (s *service) recordRevision(tx *gorm.DB, log entity.AdvisorLog, files []entity.FileAssets, action string, editorID *uint, restoredFrom *int) (*entity.AdvisorLogRevision, error) {
+	// ล็อกแถว log กันการแก้ไขพร้อมกันได้เลข revision ซ้ำ
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").First(&entity.AdvisorLog{}, log.ID).Error; err != nil {
+		return nil, err
+	}
+
+	rev := snapshot(log, files)
+	rev.Action = action
+	rev.EditorUserID = editorID
+	rev.RestoredFrom = restoredFrom
+
+	latest, err := s.latestRevision(tx, log.ID)
+	if err != nil {
+		return nil, err
+	}
+	if latest == nil {
+		rev.Revision = 1
+		rev.ChangedFields = strings.Join(ChangedFields(entity.AdvisorLogRevision{}, rev), ",")
+	} else {
+		changed := ChangedFields(*latest, rev)
+		if len(changed) == 0 {
+			return nil, nil
+		}
+		rev.Revision = latest.Revision + 1
+		rev.ChangedFields = strings.Join(changed, ",")
+	}
+
+	// ไม่ให้ gorm สร้าง/อัปเดต file_assets ซ้ำผ่าน association
+	for i := range rev.Files {
+		rev.Files[i].FileAssets = nil
+	}
+	if err := tx.Create(&rev).Error; err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// ensureBaseline log ที่สร้างก่อนมีระบบ revision ยังไม่มี revision ใด ๆ
+// ก่อนแก้ไขครั้งแรกจึงเก็บสถานะเดิมไว้เป็น revision 1 (ไม่ทราบผู้เขียน)
+func (s *service) ensureBaseline(tx *gorm.DB, log entity.AdvisorLog, files []entity.FileAssets) error {
+	var count int64
+	if err := tx.Model(&entity.AdvisorLogRevision{}).Where("advisor_log_id = ?", log.ID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	_, err := s.recordRevision(tx, log, files, entity.RevisionActionBaseline, nil, nil)
+	return err
+}
+
+// canRead สิทธิ์ดูบันทึก (เหมือน GetByID: นักศึกษาเห็นเฉพาะของตัวเองที่ไม่ใช่ Draft)
+func canRead(log entity.AdvisorLog, requesterID uint, requesterRole string) error {
+	if strings.ToLower(requesterRole) == "student" {
+		if log.Status == "Draft" {
+			return ErrAdvisorLogNotFound
+		}
+		if log.Appointment == nil || log.Appointment.StudentUserID != requesterID {
+			return ErrForbidden
+		}
+	}
+	return nil
+}
+
+func (s *service) loadLog(ctx context.Context, id uint) (*entity.AdvisorLog, error) {
+	var log entity.AdvisorLog
+	if err := s.db.WithContext(ctx).
+		Preload("Appointment").
+		Preload("Files", preloadFiles).
+		Preload("Tags", preloadTags).
+		First(&log, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAdvisorLogNotFound
+		}
+		return nil, err
+	}
+	return &log, nil
+}
+
+func toRevisionResp(rev entity.AdvisorLogRevision) dto.AdvisorLogRevisionResp {
+	out := dto.AdvisorLogRevisionResp{
+		Revision:       rev.Revision,
+		Action:         rev.Action,
+		RestoredFrom:   rev.RestoredFrom,
+		EditorID:       rev.EditorUserID,
+		ChangedFields:  []string{},
+		Title:          rev.Title,
+		Body:           rev.Body,
+		RequiresReport: rev.RequiresReport,
+		PrivateNotes:   &rev.PrivateNotes,
+		Files:          fileasset.ToResp(revisionAssets(rev)),
+		CreatedAt:      rev.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if rev.ChangedFields != "" {
+		out.ChangedFields = strings.Split(rev.ChangedFields, ",")
+	}
+	if rev.Editor != nil {
+		out.EditorName = strings.TrimSpace(rev.Editor.FirstName + " " + rev.Editor.LastName)
+	}
+	return out
+}
+
+// ------------------------------
+// DIFF (pure)
+// ------------------------------
+
+// Diff เทียบ revision from -> to (รวมบันทึกส่วนตัว ต้องผ่าน RedactDiff ก่อนส่งให้ผู้ที่ไม่มีสิทธิ์)
+func Diff(from, to entity.AdvisorLogRevision) dto.AdvisorLogRevisionDiffResp {
+	out := dto.AdvisorLogRevisionDiffResp{
+		AdvisorLogID: to.AdvisorLogID,
+		From:         from.Revision,
+		To:           to.Revision,
+		Changes:      []dto.AdvisorLogFieldChange{},
+		BodyDiff:     LineDiff(from.Body, to.Body),
+		FilesAdded:   []dto.FileAssetResp{},
+		FilesRemoved: []dto.FileAssetResp{},
+	}
+
+	for _, f := range ChangedFields(from, to) {
+		switch f {
+		case FieldTitle:
+			out.Changes = append(out.Changes, dto.AdvisorLogFieldChange{Field: f, From: from.Title, To: to.Title})
+		case FieldBody:
+			out.Changes = append(out.Changes, dto.AdvisorLogFieldChange{Field: f, From: from.Body, To: to.Body})
+		case FieldRequiresReport:
+			out.Changes = append(out.Changes, dto.AdvisorLogFieldChange{Field: f, From: from.RequiresReport, To: to.RequiresReport})
+		case FieldPrivateNotes:
+			out.Changes = append(out.Changes, dto.AdvisorLogFieldChange{Field: f, From: from.PrivateNotes, To: to.PrivateNotes})
+		case FieldFiles:
+			out.Changes = append(out.Changes, dto.AdvisorLogFieldChange{Field: f, From: len(from.Files), To: len(to.Files)})
+		}
+	}
+
+	// ไฟล์เทียบด้วย sha256 (ไฟล์เดียวกันที่อัปโหลดซ้ำถือว่าไม่เปลี่ยน)
+	key := func(a entity.FileAssets) string {
+		if a.SHA256 != "" {
+			return a.SHA256
+		}
+		return a.OriginalName
+	}
+	fromAssets, toAssets := revisionAssets(from), revisionAssets(to)
+	inFrom := map[string]bool{}
+	for _, a := range fromAssets {
+		inFrom[key(a)] = true
+	}
+	inTo := map[string]bool{}
+	for _, a := range toAssets {
+		inTo[key(a)] = true
+	}
+	for _, r := range fileasset.ToResp(toAssets) {
+		if !inFrom[key(toAssets[r.Index])] {
+			out.FilesAdded = append(out.FilesAdded, r)
+		}
+	}
+	for _, r := range fileasset.ToResp(fromAssets) {
+		if !inTo[key(fromAssets[r.Index])] {
+			out.FilesRemoved = append(out.FilesRemoved, r)
+		}
+	}
+	return out
+}
+
+// LineDiff เทียบข้อความทีละบรรทัดด้วย LCS
+func LineDiff(a, b string) []dto.AdvisorLogDiffLine {
+	out := []dto.AdvisorLogDiffLine{}
+	if a == b {
+		if a == "" {
+			return out
+		}
+		for _, l := range strings.Split(a, "\n") {
+			out = append(out, dto.AdvisorLogDiffLine{Op: "equal", Text: l})
+		}
+		return out
+	}
+
+	var x, y []string
+	if a != "" {
+		x = strings.Split(a, "\n")
+	}
+	if b != "" {
+		y = strings.Split(b, "\n")
+	}
+
+	n, m := len(x), len(y)
+	if n*m > maxDiffCells {
+		for _, l := range x {
+			out = append(out, dto.AdvisorLogDiffLine{Op: "remove", Text: l})
+		}
+		for _, l := range y {
+			out = append(out, dto.AdvisorLogDiffLine{Op: "add", Text: l})
+		}
+		return out
+	}
+
+	// lcs[i][j] = ความยาว LCS ของ x[i:] กับ y[j:]
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case x[i] == y[j]:
+			out = append(out, dto.AdvisorLogDiffLine{Op: "equal", Text: x[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, dto.AdvisorLogDiffLine{Op: "remove", Text: x[i]})
+			i++
+		default:
+			out = append(out, dto.AdvisorLogDiffLine{Op: "add", Text: y[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		out = append(out, dto.AdvisorLogDiffLine{Op: "remove", Text: x[i]})
+	}
+	for ; j < m; j++ {
+		out = append(out, dto.AdvisorLogDiffLine{Op: "add", Text: y[j]})
+	}
+	return out
+}
+
+// ------------------------------
+// LIST / DIFF / RESTORE
+// ------------------------------
+
+func (s *service) ListRevisions(ctx context.Context, logID uint, spec queryspec.Spec, requesterID uint, requesterRole string) (*dto.AdvisorLogRevisionListResp, error) {
+	log, err := s.loadLog(ctx, logID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeRead(ctx, *log, requesterID, requesterRole); err != nil {
+		return nil, err
+	}
+
+	q := s.db.WithContext(ctx).Model(&entity.AdvisorLogRevision{}).Where("advisor_log_id = ?", logID)
+	revs, meta, err := queryspec.Find(q, spec, func(r entity.AdvisorLogRevision) uint { return r.ID }, func(db *gorm.DB) *gorm.DB {
+		return db.Preload("Editor").
+			Preload("Files", preloadRevisionFiles).
+			Preload("Files.FileAssets", preloadRevisionAssets)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.AdvisorLogRevisionResp, 0, len(revs))
+	for _, r := range revs {
+		items = append(items, toRevisionResp(r))
+	}
+	out := dto.NewPageResp(RedactRevisions(items, CanSeePrivateNotes(log.Appointment, requesterID, requesterRole)), meta)
+	return &out, nil
+}
+
+func (s *service) DiffRevisions(ctx context.Context, logID uint, from, to int, requesterID uint, requesterRole string) (*dto.AdvisorLogRevisionDiffResp, error) {
+	log, err := s.loadLog(ctx, logID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeRead(ctx, *log, requesterID, requesterRole); err != nil {
+		return nil, err
+	}
+
+	a, err := s.findRevision(ctx, logID, from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := s.findRevision(ctx, logID, to)
+	if err != nil {
+		return nil, err
+	}
+	out := RedactDiff(Diff(*a, *b), CanSeePrivateNotes(log.Appointment, requesterID, requesterRole))
+	return &out, nil
+}
+
+// RestoreRevision นำเนื้อหาและชุดไฟล์ของ revision เก่ากลับมาใช้ (เฉพาะอาจารย์ที่ปรึกษาของนัดนี้)
+// ไม่แก้สถานะของ log และบันทึกเป็น revision ใหม่ (action = restore) ประวัติเดิมยังอยู่ครบ
+func (s *service) RestoreRevision(ctx context.Context, logID uint, number int, requesterID uint, requesterRole string) (*dto.AdvisorLogUpdateResp, error) {
+	log, err := s.loadLog(ctx, logID)
+	if err != nil {
+		return nil, err
+	}
+	if strings.ToLower(requesterRole) != "advisor" || log.Appointment == nil || log.Appointment.AdvisorUserID != requesterID {
+		return nil, ErrForbidden
+	}
+
+	target, err := s.findRevision(ctx, logID, number)
+	if err != nil {
+		return nil, err
+	}
+	targetIDs := revisionFileIDs(*target)
+	if len(revisionAssets(*target)) != len(targetIDs) {
+		return nil, ErrRevisionFilesGone
+	}
+
+	next := *log
+	next.Title = target.Title
+	next.Body = target.Body
+	next.RequiresReport = target.RequiresReport
+	next.PrivateNotes = target.PrivateNotes
+	if len(ChangedFields(snapshot(*log, log.Files), snapshot(next, revisionAssets(*target)))) == 0 {
+		return nil, ErrNothingToRestore
+	}
+
+	var restored []entity.FileAssets
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.ensureBaseline(tx, *log, log.Files); err != nil {
+			return err
+		}
+		if err := tx.Omit("Files", "Appointment", "Tags", "Template").Save(&next).Error; err != nil {
+			return err
+		}
+
+		if sameIDs(fileIDs(log.Files), targetIDs) {
+			restored = log.Files
+		} else {
+			if _, err := s.files.Detach(tx, entity.FileOwnerAdvisorLog, log.ID); err != nil {
+				return err
+			}
+			// คืนชีพแถว file_assets เดิมตามลำดับของ revision (ไฟล์จริงยังอยู่เพราะไม่ถูกลบตอนแทนที่)
+			for i, id := range targetIDs {
+				res := tx.Unscoped().Model(&entity.FileAssets{}).
+					Where("id = ? AND owner_type = ? AND owner_id = ?", id, entity.FileOwnerAdvisorLog, log.ID).
+					Updates(map[string]any{"deleted_at": nil, "position": i})
+				if res.Error != nil {
+					return res.Error
+				}
+				if res.RowsAffected == 0 {
+					return ErrRevisionFilesGone
+				}
+			}
+			if err := tx.Where("owner_type = ? AND owner_id = ?", entity.FileOwnerAdvisorLog, log.ID).
+				Order("position asc, id asc").Find(&restored).Error; err != nil {
+				return err
+			}
+		}
+
+		editor := requesterID
+		from := target.Revision
+		_, err := s.recordRevision(tx, next, restored, entity.RevisionActionRestore, &editor, &from)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	next.Files = restored
+
+	return &dto.AdvisorLogUpdateResp{
+		AdvisorLogRespBase: ToResp(next, requesterID, requesterRole),
+	}, nil
+}
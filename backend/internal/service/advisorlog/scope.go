@@ -0,0 +1,171 @@
+package advisorlog
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"backend/internal/app/dto"
+	"backend/internal/app/entity"
+	"backend/internal/app/queryspec"
+)
+
+// ขอบเขตบันทึกที่ผู้เรียกเห็นได้
+//   - student: บันทึกของนัดตัวเองที่ไม่ใช่ Draft
+//   - advisor: นัดที่ตัวเองเป็นอาจารย์ หรือนักศึกษาในที่ปรึกษา (Draft เห็นเฉพาะนัดของตัวเอง)
+//   - admin:   นักศึกษาในหน่วยงานเดียวกัน (admin ที่ไม่สังกัดหน่วยงานเห็นทั้งหมด) ไม่รวม Draft
+
+// ListQuery sort/ตัวกรองที่รายการบันทึกรับได้ (tag ส่งซ้ำได้หลายตัวจึงอ่านแยกใน controller)
+var ListQuery = queryspec.Options{
+	Sorts: map[string]string{
+		"id":         "advisor_logs.id",
+		"created_at": "advisor_logs.created_at",
+		"updated_at": "advisor_logs.updated_at",
+		"status":     "advisor_logs.status",
+	},
+	DefaultSort: "-created_at",
+	Filters:     []string{"status", "from", "to", "topic_id"},
+	IDColumn:    "advisor_logs.id",
+}
+
+var validStatuses = map[string]bool{
+	"Draft":           true,
+	"PendingReport":   true,
+	"ReportSubmitted": true,
+	"Completed":       true,
+}
+
+func normRole(role string) string {
+	return strings.ToLower(strings.TrimSpace(role))
+}
+
+// scoped คืน query ของ advisor_logs (JOIN appointments แล้ว) ที่จำกัดตามสิทธิ์ผู้เรียก
+func (s *service) scoped(ctx context.Context, requesterID uint, requesterRole string) (*gorm.DB, error) {
+	return Scope(s.db.WithContext(ctx), requesterID, requesterRole)
+}
+
+// Scope เหมือน scoped แต่ใช้กับ db ที่ส่งมา (ให้ service อื่น เช่น search ใช้กติกาเดียวกัน)
+func Scope(db *gorm.DB, requesterID uint, requesterRole string) (*gorm.DB, error) {
+	q := db.
+		Model(&entity.AdvisorLog{}).
+		Joins("JOIN appointments ON appointments.id = advisor_logs.appointment_id")
+
+	switch normRole(requesterRole) {
+	case "student":
+		return q.Where("appointments.student_user_id = ? AND advisor_logs.status <> ?", requesterID, "Draft"), nil
+
+	case "advisor":
+		return q.Where(`(appointments.advisor_user_id = ? OR appointments.student_user_id IN (
+				SELECT sp.user_id FROM student_profiles sp
+				JOIN advisor_profiles ap ON ap.id = sp.advisor_profile_id
+				WHERE ap.user_id = ? AND sp.deleted_at IS NULL))`, requesterID, requesterID).
+			Where("(advisor_logs.status <> ? OR appointments.advisor_user_id = ?)", "Draft", requesterID), nil
+
+	case "admin":
+		return DepartmentScope(db, q.Where("advisor_logs.status <> ?", "Draft"), requesterID)
+	}
+	return nil, ErrForbidden
+}
+
+// DepartmentScope จำกัด query (ที่ JOIN/อยู่บน appointments) ให้ admin เห็นเฉพาะนัดของนักศึกษาในหน่วยงานตัวเอง
+// ใช้ร่วมกับการเข้าถึงนัดหมาย (booking) และสถิติ tag เพื่อให้กติกาของ admin ตรงกันทุกที่
+func DepartmentScope(db, q *gorm.DB, adminID uint) (*gorm.DB, error) {
+	var me entity.User
+	if err := db.Session(&gorm.Session{NewDB: true}).Select("id", "department_id").First(&me, adminID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrForbidden
+		}
+		return nil, err
+	}
+	return InDepartment(q, me.DepartmentID), nil
+}
+
+// InDepartment กรองนัดของนักศึกษาในหน่วยงาน departmentID (nil = admin ไม่สังกัดหน่วยงาน เห็นทั้งหมด)
+func InDepartment(q *gorm.DB, departmentID *uint) *gorm.DB {
+	if departmentID == nil {
+		return q
+	}
+	return q.Where("appointments.student_user_id IN (SELECT id FROM users WHERE department_id = ?)", *departmentID)
+}
+
+// inScope บันทึกนี้อยู่ในขอบเขตของผู้เรียกหรือไม่
+func (s *service) inScope(ctx context.Context, logID, requesterID uint, requesterRole string) (bool, error) {
+	q, err := s.scoped(ctx, requesterID, requesterRole)
+	if err != nil {
+		return false, err
+	}
+	var count int64
+	if err := q.Where("advisor_logs.id = ?", logID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// authorizeRead ตรวจสิทธิ์อ่านบันทึกรายตัว (นักศึกษาเห็น Draft เป็น not found)
+func (s *service) authorizeRead(ctx context.Context, log entity.AdvisorLog, requesterID uint, requesterRole string) error {
+	if normRole(requesterRole) == "student" {
+		return canRead(log, requesterID, requesterRole)
+	}
+	ok, err := s.inScope(ctx, log.ID, requesterID, requesterRole)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// applyPageFilter กรองสถานะ/ช่วงวันที่สร้าง (YYYY-MM-DD เวลาไทย, to รวมทั้งวัน)
+func applyPageFilter(q *gorm.DB, f dto.AdvisorLogListFilter) (*gorm.DB, error) {
+	if f.Status != "" {
+		if !validStatuses[f.Status] {
+			return nil, ErrInvalidStatus
+		}
+		q = q.Where("advisor_logs.status = ?", f.Status)
+	}
+	var from, to time.Time
+	var err error
+	if f.From != "" {
+		if from, err = parseDay(f.From); err != nil {
+			return nil, ErrInvalidDateRange
+		}
+		q = q.Where("advisor_logs.created_at >= ?", from)
+	}
+	if f.To != "" {
+		if to, err = parseDay(f.To); err != nil {
+			return nil, ErrInvalidDateRange
+		}
+		if !from.IsZero() && to.Before(from) {
+			return nil, ErrInvalidDateRange
+		}
+		q = q.Where("advisor_logs.created_at < ?", to.AddDate(0, 0, 1))
+	}
+	return applyListFilter(q, f), nil
+}
+
+// list ดึงบันทึกตามขอบเขต + ตัวกรอง + หน้า
+func (s *service) list(ctx context.Context, q *gorm.DB, f dto.AdvisorLogListFilter, spec queryspec.Spec, requesterID uint, requesterRole string) (*dto.AdvisorLogListResp, error) {
+	q, err := applyPageFilter(q, f)
+	if err != nil {
+		return nil, err
+	}
+
+	logs, meta, err := queryspec.Find(q, spec, func(l entity.AdvisorLog) uint { return l.ID }, func(db *gorm.DB) *gorm.DB {
+		return db.Preload("Appointment").Preload("Files", preloadFiles).Preload("Tags", preloadTags)
+	})
+	if err != nil {
+		return nil, err
+	}
+	items := make([]dto.AdvisorLogListItemResp, 0, len(logs))
+	for _, l := range logs {
+		items = append(items, dto.AdvisorLogListItemResp{
+			AdvisorLogRespBase: ToResp(l, requesterID, requesterRole),
+		})
+	}
+	out := dto.NewPageResp(items, meta)
+	return &out, nil
+}
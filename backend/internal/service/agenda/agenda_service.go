@@ -0,0 +1,318 @@
+package agenda
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"backend/internal/app/dto"
+	"backend/internal/app/entity"
+	academic "backend/internal/service/academiccalendar"
+)
+
+// ปฏิทินส่วนตัว: นัดที่อนุมัติแล้ว + ปฏิทินการศึกษา + ช่วงไม่ว่างของอาจารย์ + กำหนดส่งรายงานความคืบหน้า
+// ทุกเวลาส่งออกเป็นเวลาไทย (Asia/Bangkok)
+
+const (
+	TypeAppointment   = "appointment"
+	TypeAcademicEvent = "academic_event"
+	TypeUnavailable   = "unavailable"
+	TypeReportDue     = "report_due"
+
+	DefaultDays = 30
+	MaxDays     = 366
+)
+
+var (
+	ErrInvalidRange = errors.New("from/to must be YYYY-MM-DD, to on or after from, at most 366 days")
+	ErrForbidden    = errors.New("forbidden")
+)
+
+type Service interface {
+	// Agenda รายการของผู้เรียกในช่วง [from, to] (YYYY-MM-DD เวลาไทย, ว่าง = วันนี้ถึงอีก 30 วัน)
+	Agenda(ctx context.Context, from, to string, requesterID uint, requesterRole string) (*dto.AgendaResp, error)
+}
+
+type service struct {
+	db       *gorm.DB
+	calendar *academic.AcademicCalendarService
+}
+
+func New(db *gorm.DB, calendar *academic.AcademicCalendarService) Service {
+	return &service{db: db, calendar: calendar}
+}
+
+// ------------------------------
+// helpers
+// ------------------------------
+
+func bangkok() *time.Location {
+	loc, err := time.LoadLocation("Asia/Bangkok")
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+func format(t time.Time) string {
+	return t.In(bangkok()).Format(time.RFC3339)
+}
+
+func formatPtr(t time.Time) *string {
+	s := format(t)
+	return &s
+}
+
+func fullName(u entity.User) string {
+	return strings.TrimSpace(u.FirstName + " " + u.LastName)
+}
+
+// ParseRange ช่วงวันที่ [from, to+1 วัน) ตามเวลาไทย
+func ParseRange(fromStr, toStr string, now time.Time) (time.Time, time.Time, error) {
+	loc := bangkok()
+	now = now.In(loc)
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	var err error
+	if fromStr != "" {
+		if from, err = time.ParseInLocation("2006-01-02", fromStr, loc); err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidRange
+		}
+	}
+	to := from.AddDate(0, 0, DefaultDays-1)
+	if toStr != "" {
+		if to, err = time.ParseInLocation("2006-01-02", toStr, loc); err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidRange
+		}
+	}
+	if to.Before(from) || to.After(from.AddDate(0, 0, MaxDays-1)) {
+		return time.Time{}, time.Time{}, ErrInvalidRange
+	}
+	return from, to.AddDate(0, 0, 1), nil
+}
+
+// isAllDay เริ่มเที่ยงคืนและจบ 23:59 หรือเที่ยงคืนวันถัดไป (ตามเวลาไทย)
+func isAllDay(start, end time.Time) bool {
+	loc := bangkok()
+	s, e := start.In(loc), end.In(loc)
+	if s.Hour() != 0 || s.Minute() != 0 {
+		return false
+	}
+	return (e.Hour() == 23 && e.Minute() == 59) || (e.Hour() == 0 && e.Minute() == 0 && e.After(s))
+}
+
+var typeRank = map[string]int{
+	TypeAcademicEvent: 0,
+	TypeUnavailable:   1,
+	TypeAppointment:   2,
+	TypeReportDue:     3,
+}
+
+// Sort เรียงตามเวลาเริ่ม (ทุกค่าเป็น RFC3339 เวลาไทยจึงเทียบเป็นข้อความได้) เวลาเท่ากันเรียงตามประเภทแล้ว id
+func Sort(items []dto.AgendaItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if a.Start != b.Start {
+			return a.Start < b.Start
+		}
+		if typeRank[a.Type] != typeRank[b.Type] {
+			return typeRank[a.Type] < typeRank[b.Type]
+		}
+		return a.ID < b.ID
+	})
+}
+
+// ExpandUnavailable ช่วงไม่ว่างของอาจารย์ในช่วง [from, to)
+// Day = วันที่, TimeNonAvailabillity = เวลาเริ่ม/จบในวันนั้น, IsRecurring = ซ้ำทุกสัปดาห์ในวันเดียวกันตั้งแต่ Day
+func ExpandUnavailable(blocks []entity.AdvisorNonAvailabillity, from, to time.Time) []dto.AgendaItem {
+	loc := bangkok()
+	var out []dto.AgendaItem
+	for _, b := range blocks {
+		day := b.Day.In(loc)
+		st, et := b.TimeNonAvailabillity.StartTime.In(loc), b.TimeNonAvailabillity.EndTime.In(loc)
+		at := func(d time.Time, clock time.Time) time.Time {
+			return time.Date(d.Year(), d.Month(), d.Day(), clock.Hour(), clock.Minute(), 0, 0, loc)
+		}
+
+		title := strings.TrimSpace(b.Description)
+		if title == "" {
+			title = "ไม่ว่าง"
+		}
+		detail := strings.TrimSpace(strings.TrimSpace(b.TypeAvailabillity + " " + b.TimeNonAvailabillity.Subjects))
+
+		emit := func(d time.Time) {
+			start, end := at(d, st), at(d, et)
+			if !end.After(start) {
+				// ไม่มีเวลาที่ใช้ได้ ถือว่าไม่ว่างทั้งวัน
+				start = time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, loc)
+				end = start.AddDate(0, 0, 1)
+			}
+			if !start.Before(to) || !end.After(from) {
+				return
+			}
+			out = append(out, dto.AgendaItem{
+				Type:   TypeUnavailable,
+				ID:     b.ID,
+				Title:  title,
+				Start:  format(start),
+				End:    formatPtr(end),
+				AllDay: isAllDay(start, end),
+				Detail: detail,
+			})
+		}
+
+		if !b.IsRecurring {
+			emit(day)
+			continue
+		}
+		// เลื่อนไปสัปดาห์แรกที่อาจทับช่วง (วันก่อนหน้า from หนึ่งวัน เผื่อช่วงข้ามเที่ยงคืน)
+		d := day
+		if first := from.AddDate(0, 0, -1); d.Before(first) {
+			weeks := int(first.Sub(d).Hours()/24) / 7
+			d = d.AddDate(0, 0, 7*weeks)
+		}
+		for ; d.Before(to); d = d.AddDate(0, 0, 7) {
+			emit(d)
+		}
+	}
+	return out
+}
+
+// ------------------------------
+// AGENDA
+// ------------------------------
+
+func (s *service) Agenda(ctx context.Context, fromStr, toStr string, requesterID uint, requesterRole string) (*dto.AgendaResp, error) {
+	role := strings.ToLower(strings.TrimSpace(requesterRole))
+	switch role {
+	case "student", "advisor", "admin":
+	default:
+		return nil, ErrForbidden
+	}
+	from, to, err := ParseRange(fromStr, toStr, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	items := []dto.AgendaItem{}
+
+	appts, err := s.appointments(ctx, from, to, requesterID)
+	if err != nil {
+		return nil, err
+	}
+	items = append(items, appts...)
+
+	occs, err := s.calendar.GetOccurrences(from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range occs {
+		items = append(items, dto.AgendaItem{
+			Type:   TypeAcademicEvent,
+			ID:     o.Event.ID,
+			Title:  o.Event.EventName,
+			Start:  format(o.Start),
+			End:    formatPtr(o.End),
+			AllDay: isAllDay(o.Start, o.End),
+			Detail: o.Event.EventType,
+		})
+	}
+
+	if role == "advisor" {
+		var blocks []entity.AdvisorNonAvailabillity
+		if err := s.db.WithContext(ctx).
+			Preload("TimeNonAvailabillity").
+			Where("advisor_id = ?", requesterID).
+			Where("(is_recurring AND day < ?) OR (day >= ? AND day < ?)", to, from.AddDate(0, 0, -1), to).
+			Find(&blocks).Error; err != nil {
+			return nil, err
+		}
+		items = append(items, ExpandUnavailable(blocks, from, to)...)
+	}
+
+	dues, err := s.reportDues(ctx, from, to, requesterID, role)
+	if err != nil {
+		return nil, err
+	}
+	items = append(items, dues...)
+
+	Sort(items)
+	return &dto.AgendaResp{
+		From:  from.Format("2006-01-02"),
+		To:    to.AddDate(0, 0, -1).Format("2006-01-02"),
+		Items: items,
+	}, nil
+}
+
+// appointments นัดที่อนุมัติแล้วและมีเวลา ที่ผู้เรียกเป็นนักศึกษาหรืออาจารย์
+func (s *service) appointments(ctx context.Context, from, to time.Time, userID uint) ([]dto.AgendaItem, error) {
+	var appts []entity.Appointment
+	if err := s.db.WithContext(ctx).
+		Preload("StudentUser").
+		Preload("AdvisorUser").
+		Preload("Topic").
+		Where("(student_user_id = ? OR advisor_user_id = ?)", userID, userID).
+		Where("appointment_status_id = ?", entity.StatusApprovedID).
+		Where("start_at IS NOT NULL AND end_at IS NOT NULL").
+		Where("start_at < ? AND end_at > ?", to, from).
+		Find(&appts).Error; err != nil {
+		return nil, err
+	}
+	out := make([]dto.AgendaItem, 0, len(appts))
+	for _, a := range appts {
+		// ชื่อคู่นัด (อีกฝ่ายของผู้เรียก)
+		other := a.StudentUser
+		if a.StudentUserID == userID {
+			other = a.AdvisorUser
+		}
+		id := a.ID
+		out = append(out, dto.AgendaItem{
+			Type:          TypeAppointment,
+			ID:            a.ID,
+			Title:         a.Topic.Topic,
+			Start:         format(*a.StartAt),
+			End:           formatPtr(*a.EndAt),
+			Detail:        fullName(other),
+			AppointmentID: &id,
+		})
+	}
+	return out, nil
+}
+
+// reportDues กำหนดส่งรายงานที่ยังไม่ได้ส่ง (นักศึกษา: ของตัวเอง, อาจารย์: นัดที่ตัวเองดูแล)
+func (s *service) reportDues(ctx context.Context, from, to time.Time, userID uint, role string) ([]dto.AgendaItem, error) {
+	var col string
+	switch role {
+	case "student":
+		col = "appointments.student_user_id"
+	case "advisor":
+		col = "appointments.advisor_user_id"
+	default:
+		return nil, nil
+	}
+	var logs []entity.AdvisorLog
+	if err := s.db.WithContext(ctx).
+		Joins("JOIN appointments ON appointments.id = advisor_logs.appointment_id").
+		Where(col+" = ?", userID).
+		Where("advisor_logs.status = ?", "PendingReport").
+		Where("advisor_logs.report_due_at >= ? AND advisor_logs.report_due_at < ?", from, to).
+		Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	out := make([]dto.AgendaItem, 0, len(logs))
+	for _, l := range logs {
+		apptID := l.AppointmentID
+		out = append(out, dto.AgendaItem{
+			Type:          TypeReportDue,
+			ID:            l.ID,
+			Title:         l.Title,
+			Start:         format(*l.ReportDueAt),
+			Detail:        l.Status,
+			AppointmentID: &apptID,
+		})
+	}
+	return out, nil
+}
@@ -1,7 +1,9 @@
 package service
 
 import (
+	"backend/internal/app/dto"
 	"backend/internal/app/entity"
+	"backend/internal/app/queryspec"
 	"backend/internal/app/repository"
 	"errors"
 	"time"
@@ -19,13 +21,18 @@ const (
 type AppointmentService interface {
 	ApproveAppointment(AppointmentID uint, ActorID uint, Role string, Description string) (*entity.Appointment, error)
 	ProposeNewTime(AppointmentID uint, ActorID uint, Role string, Description string) (*entity.Appointment, error)
+	// ✅ เสนอเวลาใหม่พร้อมช่วงเวลา (ใช้กับไฟล์ .ics)
+	ProposeNewTimeAt(AppointmentID uint, ActorID uint, Role string, Description string, StartAt, EndAt *time.Time) (*entity.Appointment, error)
 
 	GetByID(id uint) (*entity.Appointment, error)
-	ListPendingByAdvisor(advisorID uint) ([]entity.Appointment, error)
+	ListPendingByAdvisor(advisorID uint, spec queryspec.Spec) ([]entity.Appointment, dto.PageMeta, error)
 
 	// ✅ เพิ่มใหม่
-	ListDoneByAdvisor(advisorID uint) ([]entity.Appointment, error)
-	ListAllByAdvisor(advisorID uint) ([]entity.Appointment, error)
+	ListDoneByAdvisor(advisorID uint, spec queryspec.Spec) ([]entity.Appointment, dto.PageMeta, error)
+	ListAllByAdvisor(advisorID uint, spec queryspec.Spec) ([]entity.Appointment, dto.PageMeta, error)
+
+	// GetFollowUpChain สายนัดต้นทาง/นัดติดตามผลของนัดนี้
+	GetFollowUpChain(id uint) ([]repository.ChainLink, error)
 }
 
 type appointmentService struct {
@@ -64,6 +71,7 @@ func (s *appointmentService) ApproveAppointment(
 
 	updateFields := map[string]interface{}{
 		"appointment_status_id": StatusApproved,
+		"sequence":              appt.Sequence + 1,
 	}
 	if Description != "" {
 		updateFields["description"] = Description
@@ -105,6 +113,17 @@ func (s *appointmentService) ProposeNewTime(
 	Role string,
 	Description string,
 ) (*entity.Appointment, error) {
+	return s.ProposeNewTimeAt(AppointmentID, ActorID, Role, Description, nil, nil)
+}
+
+func (s *appointmentService) ProposeNewTimeAt(
+	AppointmentID uint,
+	ActorID uint,
+	Role string,
+	Description string,
+	StartAt *time.Time,
+	EndAt *time.Time,
+) (*entity.Appointment, error) {
 
 	appt, err := s.repo.GetByID(AppointmentID)
 	if err != nil {
@@ -123,14 +142,27 @@ func (s *appointmentService) ProposeNewTime(
 		return nil, errors.New("appointment is not in pending status")
 	}
 
+	if (StartAt == nil) != (EndAt == nil) {
+		return nil, errors.New("start_at and end_at must be sent together")
+	}
+	if StartAt != nil && !EndAt.After(*StartAt) {
+		return nil, errors.New("end_at must be after start_at")
+	}
+
 	oldStatus := appt.AppointmentStatusID
 
+	// sequence +1 ให้ปฏิทิน (.ics) อัปเดต event เดิม (UID เดิม) แทนการสร้างซ้ำ
 	updateFields := map[string]interface{}{
 		"appointment_status_id": StatusReschedule,
+		"sequence":              appt.Sequence + 1,
 	}
 	if Description != "" {
 		updateFields["description"] = Description
 	}
+	if StartAt != nil {
+		updateFields["start_at"] = *StartAt
+		updateFields["end_at"] = *EndAt
+	}
 
 	if err := s.repo.UpdateFields(appt.ID, updateFields); err != nil {
 		return nil, err
@@ -166,15 +198,19 @@ func (s *appointmentService) GetByID(id uint) (*entity.Appointment, error) {
 	return s.repo.GetByID(id)
 }
 
-func (s *appointmentService) ListPendingByAdvisor(advisorID uint) ([]entity.Appointment, error) {
-	return s.repo.ListPendingByAdvisor(advisorID)
+func (s *appointmentService) ListPendingByAdvisor(advisorID uint, spec queryspec.Spec) ([]entity.Appointment, dto.PageMeta, error) {
+	return s.repo.ListPendingByAdvisor(advisorID, spec)
 }
 
 // ✅ เพิ่มใหม่
-func (s *appointmentService) ListDoneByAdvisor(advisorID uint) ([]entity.Appointment, error) {
-	return s.repo.ListDoneByAdvisor(advisorID)
+func (s *appointmentService) ListDoneByAdvisor(advisorID uint, spec queryspec.Spec) ([]entity.Appointment, dto.PageMeta, error) {
+	return s.repo.ListDoneByAdvisor(advisorID, spec)
+}
+
+func (s *appointmentService) ListAllByAdvisor(advisorID uint, spec queryspec.Spec) ([]entity.Appointment, dto.PageMeta, error) {
+	return s.repo.ListAllByAdvisor(advisorID, spec)
 }
 
-func (s *appointmentService) ListAllByAdvisor(advisorID uint) ([]entity.Appointment, error) {
-	return s.repo.ListAllByAdvisor(advisorID)
+func (s *appointmentService) GetFollowUpChain(id uint) ([]repository.ChainLink, error) {
+	return s.repo.GetFollowUpChain(id)
 }
@@ -0,0 +1,235 @@
+package booking
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"backend/internal/app/dto"
+	"backend/internal/app/entity"
+	"backend/internal/app/queryspec"
+	"backend/internal/service/advisorlog"
+	"backend/internal/service/fileasset"
+)
+
+// Service การขอนัดหมายของนักศึกษา + ไฟล์แนบของนัดหมาย
+type Service interface {
+	Request(ctx context.Context, req dto.AppointmentRequestReq, studentID uint, studentRole string) (*entity.Appointment, error)
+	// GetAttachment ตรวจสิทธิ์แบบเดียวกับไฟล์ของ advisor log แล้วคืนไฟล์ลำดับที่ index
+	GetAttachment(ctx context.Context, appointmentID uint, index int, requesterID uint, requesterRole string) (*entity.FileAssets, error)
+
+	// ListMine นัดของนักศึกษาที่ login ทีละหน้า (ตัวกรอง status = รหัสสถานะ, ดู MineListQuery)
+	ListMine(ctx context.Context, studentID uint, studentRole string, spec queryspec.Spec) (*dto.StudentAppointmentListResp, error)
+	// GetMine รายละเอียดนัดของตัวเอง + timeline สถานะ + บันทึกการปรึกษาที่ไม่ใช่ Draft
+	GetMine(ctx context.Context, id, studentID uint, studentRole string) (*dto.StudentAppointmentDetailResp, error)
+	// History timeline สถานะของนัด (นักศึกษา/อาจารย์ของนัด และแอดมิน)
+	History(ctx context.Context, id, requesterID uint, requesterRole string) ([]dto.AppointmentTimelineItem, error)
+	// Authorize ตรวจสิทธิ์เห็นนัดหมาย: คู่นัด (CanAccess) หรือแอดมินในหน่วยงานของนักศึกษา (กติกาเดียวกับ advisorlog.Scope)
+	Authorize(ctx context.Context, appt *entity.Appointment, requesterID uint, requesterRole string) error
+}
+
+var (
+	ErrAppointmentNotFound = errors.New("appointment not found")
+	ErrAttachmentNotFound  = errors.New("attachment not found")
+	ErrForbidden           = errors.New("forbidden")
+	ErrStudentOnly         = errors.New("only students can request appointments")
+	ErrNoAdvisor           = errors.New("advisor_user_id is required (no advisor in student profile)")
+	ErrInvalidAdvisor      = errors.New("advisor_user_id is not an advisor")
+	ErrInvalidTime         = errors.New("start_at and end_at must be RFC3339 and sent together, end_at after start_at")
+)
+
+type service struct {
+	db    *gorm.DB
+	files fileasset.Service
+}
+
+func New(db *gorm.DB, files fileasset.Service) Service {
+	return &service{db: db, files: files}
+}
+
+// ------------------------------
+// helpers
+// ------------------------------
+
+// CanAccess คู่นัดที่เห็นนัดหมาย/ไฟล์แนบได้: อาจารย์ของนัด, นักศึกษาเจ้าของนัด
+// แอดมินต้องตรวจหน่วยงานกับ DB จึงอยู่ใน Service.Authorize
+func CanAccess(appt *entity.Appointment, userID uint, role string) bool {
+	switch strings.ToLower(strings.TrimSpace(role)) {
+	case "advisor":
+		return appt.AdvisorUserID == userID
+	case "student":
+		return appt.StudentUserID == userID
+	}
+	return false
+}
+
+func (s *service) Authorize(ctx context.Context, appt *entity.Appointment, requesterID uint, requesterRole string) error {
+	if CanAccess(appt, requesterID, requesterRole) {
+		return nil
+	}
+	if strings.ToLower(strings.TrimSpace(requesterRole)) != "admin" {
+		return ErrForbidden
+	}
+
+	db := s.db.WithContext(ctx)
+	q, err := advisorlog.DepartmentScope(db, db.Model(&entity.Appointment{}).Where("appointments.id = ?", appt.ID), requesterID)
+	if err != nil {
+		if errors.Is(err, advisorlog.ErrForbidden) {
+			return ErrForbidden
+		}
+		return err
+	}
+	var count int64
+	if err := q.Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func parseRequestTime(startStr, endStr string) (*time.Time, *time.Time, error) {
+	if startStr == "" && endStr == "" {
+		return nil, nil, nil
+	}
+	start, err1 := time.Parse(time.RFC3339, startStr)
+	end, err2 := time.Parse(time.RFC3339, endStr)
+	if err1 != nil || err2 != nil || !end.After(start) {
+		return nil, nil, ErrInvalidTime
+	}
+	return &start, &end, nil
+}
+
+// resolveAdvisor ใช้อาจารย์ที่ระบุมา หรืออาจารย์ที่ปรึกษาในโปรไฟล์นักศึกษา
+func (s *service) resolveAdvisor(ctx context.Context, advisorUserID, studentID uint) (uint, error) {
+	if advisorUserID == 0 {
+		var profile entity.StudentProfile
+		if err := s.db.WithContext(ctx).
+			Preload("AdvisorProfile").
+			Where("user_id = ?", studentID).
+			First(&profile).Error; err != nil || profile.AdvisorProfile == nil {
+			return 0, ErrNoAdvisor
+		}
+		advisorUserID = profile.AdvisorProfile.UserID
+	}
+
+	var advisor entity.User
+	if err := s.db.WithContext(ctx).Preload("Role").First(&advisor, advisorUserID).Error; err != nil {
+		return 0, ErrInvalidAdvisor
+	}
+	if advisor.Role == nil || !strings.EqualFold(advisor.Role.Role, "advisor") {
+		return 0, ErrInvalidAdvisor
+	}
+	return advisor.ID, nil
+}
+
+// ------------------------------
+// REQUEST
+// ------------------------------
+
+func (s *service) Request(ctx context.Context, req dto.AppointmentRequestReq, studentID uint, studentRole string) (*entity.Appointment, error) {
+	if !strings.EqualFold(studentRole, "student") {
+		return nil, ErrStudentOnly
+	}
+
+	advisorID, err := s.resolveAdvisor(ctx, req.AdvisorUserID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	startAt, endAt, err := parseRequestTime(req.StartAt, req.EndAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.files.Validate(ctx, fileasset.PolicyAppointmentAttachment, req.Files, studentID, 0); err != nil {
+		return nil, err
+	}
+	assets, err := s.files.StoreAll(req.Files, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	appt := entity.Appointment{
+		Description:         strings.TrimSpace(req.Description),
+		StartAt:             startAt,
+		EndAt:               endAt,
+		AdvisorUserID:       advisorID,
+		StudentUserID:       studentID,
+		TopicID:             req.TopicID,
+		CategoryID:          req.CategoryID,
+		AppointmentStatusID: entity.StatusPendingID,
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Attachments").Create(&appt).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&entity.AppointmentState{
+			AppointmentID:   appt.ID,
+			CurrentStatusID: entity.StatusPendingID,
+		}).Error; err != nil {
+			return err
+		}
+		if len(assets) == 0 {
+			return nil
+		}
+
+		if err := s.files.Attach(tx, assets, entity.FileOwnerAppointment, appt.ID); err != nil {
+			return err
+		}
+		attachments := make([]entity.AppointmentAttachment, 0, len(assets))
+		for i := range assets {
+			attachments = append(attachments, entity.AppointmentAttachment{
+				FileAssetsID:  assets[i].ID,
+				AppointmentID: appt.ID,
+			})
+		}
+		if err := tx.Omit("FileAssets", "Appointment").Create(&attachments).Error; err != nil {
+			return err
+		}
+		for i := range attachments {
+			attachments[i].FileAssets = &assets[i]
+		}
+		appt.Attachments = attachments
+		return nil
+	})
+	if err != nil {
+		s.files.Discard(assets)
+		return nil, err
+	}
+	return &appt, nil
+}
+
+// ------------------------------
+// ATTACHMENT
+// ------------------------------
+
+func (s *service) GetAttachment(ctx context.Context, appointmentID uint, index int, requesterID uint, requesterRole string) (*entity.FileAssets, error) {
+	var appt entity.Appointment
+	if err := s.db.WithContext(ctx).First(&appt, appointmentID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAppointmentNotFound
+		}
+		return nil, err
+	}
+	if err := s.Authorize(ctx, &appt, requesterID, requesterRole); err != nil {
+		return nil, err
+	}
+
+	var attachments []entity.AppointmentAttachment
+	if err := s.db.WithContext(ctx).
+		Preload("FileAssets").
+		Where("appointment_id = ?", appt.ID).
+		Order("id asc").
+		Find(&attachments).Error; err != nil {
+		return nil, err
+	}
+	if index < 0 || index >= len(attachments) || attachments[index].FileAssets == nil {
+		return nil, ErrAttachmentNotFound
+	}
+	return attachments[index].FileAssets, nil
+}
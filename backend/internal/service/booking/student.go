@@ -0,0 +1,204 @@
+package booking
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"backend/internal/app/dto"
+	"backend/internal/app/entity"
+	"backend/internal/app/queryspec"
+	"backend/internal/service/advisorlog"
+	"backend/internal/service/fileasset"
+)
+
+// มุมมองของนักศึกษา: นัดของตัวเอง สถานะ เวลาที่เสนอ timeline และบันทึกการปรึกษาที่เผยแพร่แล้ว
+
+var ErrInvalidStatusFilter = errors.New("invalid status (PENDING, APPROVED, RESCHEDULE)")
+
+// MineListQuery sort/ตัวกรองที่รายการนัดของนักศึกษารับได้
+var MineListQuery = queryspec.Options{
+	Sorts: map[string]string{
+		"id":           "id",
+		"submitted_at": "created_at",
+		"updated_at":   "updated_at",
+		"start_at":     "start_at",
+	},
+	DefaultSort: "-submitted_at",
+	Filters:     []string{"status"},
+}
+
+func fullName(u entity.User) string {
+	return strings.TrimSpace(u.FirstName + " " + u.LastName)
+}
+
+func roleName(u entity.User) string {
+	if u.Role == nil {
+		return ""
+	}
+	return u.Role.Role
+}
+
+// ToStudentItem แปลงนัด (preload Topic, Category, AdvisorUser, AppointmentStatus แล้ว) เป็นรายการของนักศึกษา
+func ToStudentItem(a entity.Appointment) dto.StudentAppointmentItem {
+	return dto.StudentAppointmentItem{
+		ID:                  a.ID,
+		Topic:               a.Topic.Topic,
+		Category:            a.Category.Category,
+		Description:         a.Description,
+		AdvisorID:           a.AdvisorUserID,
+		AdvisorName:         fullName(a.AdvisorUser),
+		StatusCode:          a.AppointmentStatus.StatusCode,
+		StatusName:          a.AppointmentStatus.StatusName,
+		StartAt:             a.StartAt,
+		EndAt:               a.EndAt,
+		ParentAppointmentID: a.ParentAppointmentID,
+		HasAdvisorLog:       a.AdvisorLog != nil && a.AdvisorLog.Status != "Draft",
+		SubmittedAt:         a.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:           a.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// ToTimeline แปลง StatusHistory (preload สถานะ/action/ผู้เปลี่ยนและ role แล้ว) ตามลำดับที่ได้รับ
+func ToTimeline(rows []entity.StatusHistory) []dto.AppointmentTimelineItem {
+	out := make([]dto.AppointmentTimelineItem, 0, len(rows))
+	for _, h := range rows {
+		at := h.ChangedAt
+		if at.IsZero() {
+			at = h.CreatedAt
+		}
+		out = append(out, dto.AppointmentTimelineItem{
+			FromStatusCode: h.FromStatus.StatusCode,
+			FromStatusName: h.FromStatus.StatusName,
+			ToStatusCode:   h.ToStatus.StatusCode,
+			ToStatusName:   h.ToStatus.StatusName,
+			ActionCode:     h.Action.ActionCode,
+			ActionName:     h.Action.ActionName,
+			ChangedByID:    h.ChangedByUserID,
+			ChangedByName:  fullName(h.ChangedByUser),
+			ChangedByRole:  roleName(h.ChangedByUser),
+			Reason:         h.Reason,
+			ChangedAt:      at.Format("2006-01-02 15:04:05"),
+		})
+	}
+	return out
+}
+
+func preloadStudentView(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Topic").
+		Preload("Category").
+		Preload("AdvisorUser").
+		Preload("AppointmentStatus").
+		Preload("AdvisorLog", "status <> ?", "Draft")
+}
+
+// loadTimeline ประวัติสถานะเรียงตามเวลา (preload เป็น batch ไม่ยิง query ต่อแถว)
+func (s *service) loadTimeline(ctx context.Context, appointmentID uint) ([]entity.StatusHistory, error) {
+	var rows []entity.StatusHistory
+	err := s.db.WithContext(ctx).
+		Preload("FromStatus").
+		Preload("ToStatus").
+		Preload("Action").
+		Preload("ChangedByUser").
+		Preload("ChangedByUser.Role").
+		Where("appointment_id = ?", appointmentID).
+		Order("changed_at asc, id asc").
+		Find(&rows).Error
+	return rows, err
+}
+
+func (s *service) ListMine(ctx context.Context, studentID uint, studentRole string, spec queryspec.Spec) (*dto.StudentAppointmentListResp, error) {
+	if !strings.EqualFold(studentRole, "student") {
+		return nil, ErrStudentOnly
+	}
+	q := s.db.WithContext(ctx).Model(&entity.Appointment{}).Where("student_user_id = ?", studentID)
+	if status := strings.ToUpper(spec.Filter("status")); status != "" {
+		var st entity.AppointmentStatus
+		if err := s.db.WithContext(ctx).Where("status_code = ?", status).First(&st).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrInvalidStatusFilter
+			}
+			return nil, err
+		}
+		q = q.Where("appointment_status_id = ?", st.ID)
+	}
+
+	appts, meta, err := queryspec.Find(q, spec, func(a entity.Appointment) uint { return a.ID }, preloadStudentView)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]dto.StudentAppointmentItem, 0, len(appts))
+	for _, a := range appts {
+		items = append(items, ToStudentItem(a))
+	}
+	out := dto.NewPageResp(items, meta)
+	return &out, nil
+}
+
+func (s *service) GetMine(ctx context.Context, id, studentID uint, studentRole string) (*dto.StudentAppointmentDetailResp, error) {
+	if !strings.EqualFold(studentRole, "student") {
+		return nil, ErrStudentOnly
+	}
+	var appt entity.Appointment
+	err := preloadStudentView(s.db.WithContext(ctx)).
+		Preload("AdvisorLog.Files", func(db *gorm.DB) *gorm.DB { return db.Order("position asc, id asc") }).
+		Preload("AdvisorLog.Tags", func(db *gorm.DB) *gorm.DB { return db.Order("log_tags.label asc") }).
+		Preload("Attachments", func(db *gorm.DB) *gorm.DB { return db.Order("id asc") }).
+		Preload("Attachments.FileAssets").
+		First(&appt, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAppointmentNotFound
+		}
+		return nil, err
+	}
+	// นัดของคนอื่นตอบเหมือนไม่มี (ไม่บอกว่ามี id นี้อยู่)
+	if appt.StudentUserID != studentID {
+		return nil, ErrAppointmentNotFound
+	}
+
+	history, err := s.loadTimeline(ctx, appt.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	assets := make([]entity.FileAssets, 0, len(appt.Attachments))
+	for _, a := range appt.Attachments {
+		if a.FileAssets != nil {
+			assets = append(assets, *a.FileAssets)
+		}
+	}
+
+	out := &dto.StudentAppointmentDetailResp{
+		StudentAppointmentItem: ToStudentItem(appt),
+		Attachments:            fileasset.ToResp(assets),
+		Timeline:               ToTimeline(history),
+	}
+	if appt.AdvisorLog != nil {
+		// ToResp ตัดบันทึกส่วนตัวของอาจารย์ออกเมื่อผู้เรียกเป็นนักศึกษา
+		log := advisorlog.ToResp(*appt.AdvisorLog, studentID, studentRole)
+		out.AdvisorLog = &log
+	}
+	return out, nil
+}
+
+func (s *service) History(ctx context.Context, id, requesterID uint, requesterRole string) ([]dto.AppointmentTimelineItem, error) {
+	var appt entity.Appointment
+	if err := s.db.WithContext(ctx).Select("id", "advisor_user_id", "student_user_id").First(&appt, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAppointmentNotFound
+		}
+		return nil, err
+	}
+	if err := s.Authorize(ctx, &appt, requesterID, requesterRole); err != nil {
+		return nil, err
+	}
+	rows, err := s.loadTimeline(ctx, appt.ID)
+	if err != nil {
+		return nil, err
+	}
+	return ToTimeline(rows), nil
+}
@@ -0,0 +1,284 @@
+package calendarfeed
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"backend/internal/app/entity"
+)
+
+// โดเมนท้าย UID ของทุก event (ห้ามเปลี่ยน ไม่งั้นปฏิทินที่ subscribe ไว้จะสร้าง event ซ้ำ)
+const uidDomain = "advisor.sut.ac.th"
+
+var (
+	ErrInvalidToken        = errors.New("invalid calendar token")
+	ErrAppointmentNotFound = errors.New("appointment not found")
+	ErrAppointmentNoTime   = errors.New("appointment has no scheduled time")
+	ErrForbidden           = errors.New("forbidden")
+	ErrInvalidEventType    = errors.New("invalid event type (exam|activity|holiday)")
+)
+
+type Service interface {
+	// token ของ user (สร้างใหม่ถ้ายังไม่มี)
+	GetOrCreateToken(ctx context.Context, userID uint) (string, error)
+	// สร้าง token ใหม่ (token เดิมใช้ไม่ได้ทันที)
+	RotateToken(ctx context.Context, userID uint) (string, error)
+
+	// feed ส่วนตัว: นัดหมายที่อนุมัติแล้ว/เสนอเวลาใหม่ของ user เจ้าของ token
+	UserFeed(ctx context.Context, token string) ([]byte, error)
+	// feed สาธารณะของปฏิทินการศึกษา (eventType ว่าง = ทุกประเภท)
+	AcademicFeed(ctx context.Context, eventType string) ([]byte, error)
+	// ไฟล์ .ics ของนัดหมายเดียว (ต้องเป็นนักศึกษา/อาจารย์ของนัด หรือ admin)
+	AppointmentICS(ctx context.Context, appointmentID uint, requesterID uint, requesterRole string) ([]byte, error)
+}
+
+type service struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) Service {
+	return &service{db: db}
+}
+
+// ------------------------------
+// helpers
+// ------------------------------
+
+func bangkok() *time.Location {
+	loc, err := time.LoadLocation("Asia/Bangkok")
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+func newToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func AppointmentUID(id uint) string {
+	return fmt.Sprintf("appointment-%d@%s", id, uidDomain)
+}
+
+func AcademicEventUID(id uint) string {
+	return fmt.Sprintf("academic-%d@%s", id, uidDomain)
+}
+
+// FeedStatusIDs สถานะนัดหมายที่อยู่ใน feed ส่วนตัว: นัดที่ถูกเสนอเวลาใหม่ต้องยังอยู่ใน feed
+// (STATUS:TENTATIVE ที่ SEQUENCE ใหม่) ไม่งั้นปฏิทินที่ subscribe ไว้จะไม่เห็นเวลาที่เปลี่ยน
+var FeedStatusIDs = []uint{entity.StatusApprovedID, entity.StatusRescheduleID}
+
+// AppointmentEvent แปลงนัดหมายเป็น event (อนุมัติแล้ว = CONFIRMED, อื่น ๆ = TENTATIVE)
+func AppointmentEvent(a entity.Appointment) Event {
+	status := "TENTATIVE"
+	if a.AppointmentStatusID == entity.StatusApprovedID {
+		status = "CONFIRMED"
+	}
+
+	summary := "นัดปรึกษา"
+	if a.Topic.Topic != "" {
+		summary = "นัดปรึกษา: " + a.Topic.Topic
+	}
+
+	var desc []string
+	if n := strings.TrimSpace(a.StudentUser.FirstName + " " + a.StudentUser.LastName); n != "" {
+		desc = append(desc, "นักศึกษา: "+n+" ("+a.StudentUser.SutId+")")
+	}
+	if n := strings.TrimSpace(a.AdvisorUser.FirstName + " " + a.AdvisorUser.LastName); n != "" {
+		desc = append(desc, "อาจารย์ที่ปรึกษา: "+n)
+	}
+	if a.Description != "" {
+		desc = append(desc, a.Description)
+	}
+
+	return Event{
+		UID:          AppointmentUID(a.ID),
+		Sequence:     a.Sequence,
+		Summary:      summary,
+		Description:  strings.Join(desc, "\n"),
+		Categories:   a.Category.Category,
+		Status:       status,
+		Start:        *a.StartAt,
+		End:          *a.EndAt,
+		LastModified: a.UpdatedAt,
+	}
+}
+
+// isAllDay: event ที่เริ่ม 00:00 และจบ 23:59 (หรือ 00:00 ของวันถัดไป) ตามเวลาไทย
+func isAllDay(start, end time.Time) bool {
+	loc := bangkok()
+	s, e := start.In(loc), end.In(loc)
+	if s.Hour() != 0 || s.Minute() != 0 {
+		return false
+	}
+	return (e.Hour() == 23 && e.Minute() == 59) || (e.Hour() == 0 && e.Minute() == 0 && e.After(s))
+}
+
+func academicEvent(cal entity.AcademicCalendar) Event {
+	e := Event{
+		UID:          AcademicEventUID(cal.ID),
+		Sequence:     cal.Sequence,
+		Summary:      cal.EventName,
+		Categories:   cal.EventType,
+		Status:       "CONFIRMED",
+		Start:        cal.StartDateTime,
+		End:          cal.EndDateTime,
+		RRule:        cal.RRule,
+		LastModified: cal.UpdatedAt,
+	}
+
+	if isAllDay(cal.StartDateTime, cal.EndDateTime) {
+		loc := bangkok()
+		s, end := cal.StartDateTime.In(loc), cal.EndDateTime.In(loc)
+		e.AllDay = true
+		e.Start = time.Date(s.Year(), s.Month(), s.Day(), 0, 0, 0, 0, loc)
+		// DTEND ของ all-day เป็นวันถัดจากวันสุดท้าย (exclusive)
+		last := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, loc)
+		if end.Hour() == 0 && end.Minute() == 0 {
+			e.End = last
+		} else {
+			e.End = last.AddDate(0, 0, 1)
+		}
+	}
+	return e
+}
+
+// ------------------------------
+// TOKEN
+// ------------------------------
+
+func (s *service) GetOrCreateToken(ctx context.Context, userID uint) (string, error) {
+	var t entity.CalendarFeedToken
+	err := s.db.WithContext(ctx).Where("user_id = ?", userID).First(&t).Error
+	if err == nil {
+		return t.Token, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", err
+	}
+	return s.RotateToken(ctx, userID)
+}
+
+func (s *service) RotateToken(ctx context.Context, userID uint) (string, error) {
+	token, err := newToken()
+	if err != nil {
+		return "", err
+	}
+
+	var t entity.CalendarFeedToken
+	err = s.db.WithContext(ctx).Where("user_id = ?", userID).First(&t).Error
+	switch {
+	case err == nil:
+		t.Token = token
+		err = s.db.WithContext(ctx).Save(&t).Error
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		t = entity.CalendarFeedToken{UserID: userID, Token: token}
+		err = s.db.WithContext(ctx).Create(&t).Error
+	}
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// ------------------------------
+// FEEDS
+// ------------------------------
+
+func (s *service) UserFeed(ctx context.Context, token string) ([]byte, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, ErrInvalidToken
+	}
+
+	var t entity.CalendarFeedToken
+	if err := s.db.WithContext(ctx).Where("token = ?", token).First(&t).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	var appts []entity.Appointment
+	if err := s.db.WithContext(ctx).
+		Preload("StudentUser").
+		Preload("AdvisorUser").
+		Preload("Topic").
+		Preload("Category").
+		Where("(student_user_id = ? OR advisor_user_id = ?)", t.UserID, t.UserID).
+		Where("appointment_status_id IN ?", FeedStatusIDs).
+		Where("start_at IS NOT NULL AND end_at IS NOT NULL").
+		Order("start_at asc").
+		Find(&appts).Error; err != nil {
+		return nil, err
+	}
+
+	cal := Calendar{Name: "นัดหมายปรึกษา"}
+	for _, a := range appts {
+		cal.Events = append(cal.Events, AppointmentEvent(a))
+	}
+	return cal.Bytes(), nil
+}
+
+func (s *service) AcademicFeed(ctx context.Context, eventType string) ([]byte, error) {
+	q := s.db.WithContext(ctx).Order("start_date_time asc")
+
+	switch strings.ToLower(strings.TrimSpace(eventType)) {
+	case "":
+	case "holiday":
+		// วันหยุดที่ seed มามี EventType อื่น แต่ IsHoliday = true
+		q = q.Where("event_type = ? OR is_holiday = ?", "holiday", true)
+	case "exam", "activity":
+		q = q.Where("event_type = ?", strings.ToLower(eventType))
+	default:
+		return nil, ErrInvalidEventType
+	}
+
+	var events []entity.AcademicCalendar
+	if err := q.Find(&events).Error; err != nil {
+		return nil, err
+	}
+
+	cal := Calendar{Name: "ปฏิทินการศึกษา"}
+	for _, e := range events {
+		cal.Events = append(cal.Events, academicEvent(e))
+	}
+	return cal.Bytes(), nil
+}
+
+func (s *service) AppointmentICS(ctx context.Context, appointmentID uint, requesterID uint, requesterRole string) ([]byte, error) {
+	var a entity.Appointment
+	if err := s.db.WithContext(ctx).
+		Preload("StudentUser").
+		Preload("AdvisorUser").
+		Preload("Topic").
+		Preload("Category").
+		First(&a, appointmentID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAppointmentNotFound
+		}
+		return nil, err
+	}
+
+	if strings.ToLower(requesterRole) != "admin" &&
+		a.StudentUserID != requesterID && a.AdvisorUserID != requesterID {
+		return nil, ErrForbidden
+	}
+	if a.StartAt == nil || a.EndAt == nil {
+		return nil, ErrAppointmentNoTime
+	}
+
+	cal := Calendar{Events: []Event{AppointmentEvent(a)}}
+	return cal.Bytes(), nil
+}
@@ -0,0 +1,149 @@
+package calendarfeed
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ตัวเขียนไฟล์ iCalendar (RFC 5545) แบบง่าย ใช้เฉพาะ VEVENT
+
+const icsProdID = "-//SUT Advisor System//Calendar//TH"
+
+// Event 1 รายการใน .ics
+// UID ต้องคงที่ของ record เดิม และ Sequence ต้องเพิ่มทุกครั้งที่แก้ ให้ client อัปเดตแทนสร้างซ้ำ
+type Event struct {
+	UID          string
+	Sequence     int
+	Summary      string
+	Description  string
+	Location     string
+	Categories   string
+	Status       string // CONFIRMED | TENTATIVE | CANCELLED
+	Start        time.Time
+	End          time.Time
+	AllDay       bool
+	RRule        string // ว่าง = ไม่ซ้ำ
+	LastModified time.Time
+}
+
+type Calendar struct {
+	Name   string
+	Method string // PUBLISH (default)
+	Events []Event
+}
+
+// Bytes สร้างเนื้อไฟล์ .ics (บรรทัดลงท้ายด้วย CRLF และพับบรรทัดที่ยาวเกิน 75 octets)
+func (c *Calendar) Bytes() []byte {
+	var b bytes.Buffer
+
+	method := c.Method
+	if method == "" {
+		method = "PUBLISH"
+	}
+
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:"+icsProdID)
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:"+method)
+	if c.Name != "" {
+		writeLine(&b, "X-WR-CALNAME:"+escapeText(c.Name))
+	}
+	writeLine(&b, "X-WR-TIMEZONE:Asia/Bangkok")
+
+	for _, e := range c.Events {
+		writeEvent(&b, e)
+	}
+
+	writeLine(&b, "END:VCALENDAR")
+	return b.Bytes()
+}
+
+func writeEvent(b *bytes.Buffer, e Event) {
+	stamp := e.LastModified
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+
+	writeLine(b, "BEGIN:VEVENT")
+	writeLine(b, "UID:"+e.UID)
+	writeLine(b, fmt.Sprintf("SEQUENCE:%d", e.Sequence))
+	writeLine(b, "DTSTAMP:"+formatUTC(stamp))
+	writeLine(b, "LAST-MODIFIED:"+formatUTC(stamp))
+
+	if e.AllDay {
+		writeLine(b, "DTSTART;VALUE=DATE:"+e.Start.Format("20060102"))
+		writeLine(b, "DTEND;VALUE=DATE:"+e.End.Format("20060102"))
+	} else {
+		writeLine(b, "DTSTART:"+formatUTC(e.Start))
+		writeLine(b, "DTEND:"+formatUTC(e.End))
+	}
+	if e.RRule != "" {
+		writeLine(b, "RRULE:"+e.RRule)
+	}
+
+	writeLine(b, "SUMMARY:"+escapeText(e.Summary))
+	if e.Description != "" {
+		writeLine(b, "DESCRIPTION:"+escapeText(e.Description))
+	}
+	if e.Location != "" {
+		writeLine(b, "LOCATION:"+escapeText(e.Location))
+	}
+	if e.Categories != "" {
+		writeLine(b, "CATEGORIES:"+escapeText(e.Categories))
+	}
+	if e.Status != "" {
+		writeLine(b, "STATUS:"+e.Status)
+	}
+	writeLine(b, "END:VEVENT")
+}
+
+func formatUTC(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// escapeText escape ตาม RFC 5545 (\\ ; , และขึ้นบรรทัดใหม่)
+func escapeText(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	)
+	return r.Replace(s)
+}
+
+// writeLine พับบรรทัดทุก 75 octets โดยไม่ตัดกลางตัวอักษร UTF-8 (สำคัญกับภาษาไทย)
+func writeLine(b *bytes.Buffer, line string) {
+	const limit = 75
+
+	first := true
+	for len(line) > 0 {
+		max := limit
+		if !first {
+			max = limit - 1 // บรรทัดต่อเนื่องขึ้นต้นด้วย space 1 ตัว
+		}
+
+		cut := len(line)
+		if cut > max {
+			cut = max
+			for cut > 0 && !utf8.RuneStart(line[cut]) {
+				cut--
+			}
+		}
+
+		if !first {
+			b.WriteByte(' ')
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n")
+
+		line = line[cut:]
+		first = false
+	}
+}
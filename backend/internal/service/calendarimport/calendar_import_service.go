@@ -0,0 +1,219 @@
+package calendarimport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"backend/internal/app/dto"
+	"backend/internal/app/entity"
+	"backend/internal/service/rrule"
+)
+
+const (
+	RowNew       = "new"
+	RowDuplicate = "duplicate"
+	RowInvalid   = "invalid"
+)
+
+var ErrInvalidDefaultType = errors.New("invalid default type (exam|activity|holiday)")
+
+type Service interface {
+	// Preview อ่านไฟล์แล้วบอกว่าแถวไหนจะถูกเพิ่ม/ซ้ำ/ผิดรูปแบบ (ยังไม่บันทึก)
+	Preview(ctx context.Context, filename string, r io.Reader, defaultType string) (*dto.CalendarImportResult, error)
+	// Import บันทึกเฉพาะแถวที่เป็น new (แถวซ้ำตาม วันที่+ชื่อ จะถูกข้าม)
+	Import(ctx context.Context, filename string, r io.Reader, defaultType string, adminID uint) (*dto.CalendarImportResult, error)
+}
+
+type service struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) Service {
+	return &service{db: db}
+}
+
+// dedupKey ใช้ "วันที่เริ่ม (เวลาไทย) + ชื่อ" ตัดสินว่าเป็น event เดียวกัน
+func dedupKey(title string, ev entity.AcademicCalendar) string {
+	return ev.StartDateTime.In(bangkok()).Format("2006-01-02") + "|" + strings.TrimSpace(title)
+}
+
+type plannedRow struct {
+	row   dto.CalendarImportRow
+	event *entity.AcademicCalendar // nil ถ้าแถวนี้จะไม่ถูกบันทึก
+}
+
+func (s *service) plan(ctx context.Context, filename string, r io.Reader, defaultType string) (string, []plannedRow, error) {
+	if defaultType != "" {
+		t, ok := NormalizeEventType(defaultType)
+		if !ok {
+			return "", nil, ErrInvalidDefaultType
+		}
+		defaultType = t
+	}
+
+	format, err := DetectFormat(filename)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var events []Event
+	if format == FormatICS {
+		events, err = ParseICS(r)
+	} else {
+		events, err = ParseCSV(r)
+	}
+	if err != nil {
+		return format, nil, err
+	}
+
+	// ดึง event เดิมในช่วงเวลาของไฟล์มาครั้งเดียว เพื่อเช็คซ้ำ (เผื่อขอบวันละ 1 วันเรื่อง timezone)
+	existing := map[string]bool{}
+	var from, to time.Time
+	for _, ev := range events {
+		if ev.Err != nil {
+			continue
+		}
+		if from.IsZero() || ev.Start.Before(from) {
+			from = ev.Start
+		}
+		if to.IsZero() || ev.Start.After(to) {
+			to = ev.Start
+		}
+	}
+	if !from.IsZero() {
+		var rows []entity.AcademicCalendar
+		if err := s.db.WithContext(ctx).
+			Select("id", "event_name", "start_date_time").
+			Where("start_date_time >= ? AND start_date_time < ?", from.AddDate(0, 0, -1), to.AddDate(0, 0, 1)).
+			Find(&rows).Error; err != nil {
+			return format, nil, err
+		}
+		for _, row := range rows {
+			existing[dedupKey(row.EventName, row)] = true
+		}
+	}
+
+	loc := bangkok()
+	planned := make([]plannedRow, 0, len(events))
+	for _, ev := range events {
+		row := dto.CalendarImportRow{Line: ev.Line, Title: ev.Title}
+
+		if ev.Err != nil {
+			row.Status, row.Error = RowInvalid, ev.Err.Error()
+			planned = append(planned, plannedRow{row: row})
+			continue
+		}
+
+		st, en := ev.Start.In(loc), ev.End.In(loc)
+		row.StartDate, row.EndDate = st.Format("2006-01-02"), en.Format("2006-01-02")
+		row.StartTime, row.EndTime = st.Format("15:04"), en.Format("15:04")
+
+		typ := defaultType
+		if ev.RawType != "" {
+			t, ok := NormalizeEventType(ev.RawType)
+			if !ok {
+				row.Status, row.Error = RowInvalid, "unknown type "+ev.RawType+" (exam|activity|holiday)"
+				planned = append(planned, plannedRow{row: row})
+				continue
+			}
+			typ = t
+		}
+		if typ == "" {
+			row.Status, row.Error = RowInvalid, "type is required (exam|activity|holiday)"
+			planned = append(planned, plannedRow{row: row})
+			continue
+		}
+		row.Type = typ
+		row.IsHoliday = typ == "holiday"
+
+		rule, err := rrule.Normalize(ev.RRule)
+		if err != nil {
+			row.Status, row.Error = RowInvalid, err.Error()
+			planned = append(planned, plannedRow{row: row})
+			continue
+		}
+		row.RRule = rule
+
+		event := &entity.AcademicCalendar{
+			EventName:     ev.Title,
+			EventType:     typ,
+			StartDateTime: ev.Start,
+			EndDateTime:   ev.End,
+			IsHoliday:     row.IsHoliday,
+			RRule:         rule,
+		}
+		if err := event.Validate(); err != nil {
+			row.Status, row.Error = RowInvalid, err.Error()
+			planned = append(planned, plannedRow{row: row})
+			continue
+		}
+
+		// ซ้ำกับในฐานข้อมูล หรือซ้ำกันเองในไฟล์
+		key := dedupKey(ev.Title, *event)
+		if existing[key] {
+			row.Status = RowDuplicate
+			planned = append(planned, plannedRow{row: row})
+			continue
+		}
+		existing[key] = true
+
+		row.Status = RowNew
+		planned = append(planned, plannedRow{row: row, event: event})
+	}
+
+	return format, planned, nil
+}
+
+func summarize(format string, planned []plannedRow) *dto.CalendarImportResult {
+	res := &dto.CalendarImportResult{Format: format, Total: len(planned), Rows: make([]dto.CalendarImportRow, 0, len(planned))}
+	for _, p := range planned {
+		switch p.row.Status {
+		case RowNew:
+			res.New++
+		case RowDuplicate:
+			res.Duplicates++
+		case RowInvalid:
+			res.Invalid++
+		}
+		res.Rows = append(res.Rows, p.row)
+	}
+	return res
+}
+
+func (s *service) Preview(ctx context.Context, filename string, r io.Reader, defaultType string) (*dto.CalendarImportResult, error) {
+	format, planned, err := s.plan(ctx, filename, r, defaultType)
+	if err != nil {
+		return nil, err
+	}
+	return summarize(format, planned), nil
+}
+
+func (s *service) Import(ctx context.Context, filename string, r io.Reader, defaultType string, adminID uint) (*dto.CalendarImportResult, error) {
+	format, planned, err := s.plan(ctx, filename, r, defaultType)
+	if err != nil {
+		return nil, err
+	}
+
+	var toCreate []*entity.AcademicCalendar
+	for _, p := range planned {
+		if p.event != nil {
+			p.event.AdminID = adminID
+			toCreate = append(toCreate, p.event)
+		}
+	}
+
+	if len(toCreate) > 0 {
+		if err := s.db.WithContext(ctx).CreateInBatches(toCreate, 100).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	res := summarize(format, planned)
+	res.Imported = len(toCreate)
+	return res, nil
+}
@@ -0,0 +1,453 @@
+package calendarimport
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	FormatICS = "ics"
+	FormatCSV = "csv"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported file format (.ics|.csv)")
+	ErrEmptyFile         = errors.New("no events found in file")
+	ErrMissingColumn     = errors.New("csv header must contain title and start_date columns")
+)
+
+// Event 1 รายการที่อ่านได้จากไฟล์ (ยังไม่ได้บันทึก)
+// Err != nil แปลว่าแถวนี้อ่านไม่ผ่าน แต่แถวอื่นยังใช้ได้
+type Event struct {
+	Line    int
+	Title   string
+	RawType string
+	Start   time.Time
+	End     time.Time
+	AllDay  bool
+	RRule   string // จาก RRULE ในไฟล์ .ics
+	Err     error
+}
+
+func bangkok() *time.Location {
+	loc, err := time.LoadLocation("Asia/Bangkok")
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// DetectFormat เดา format จากนามสกุลไฟล์
+func DetectFormat(filename string) (string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".ics", ".ical", ".ifb":
+		return FormatICS, nil
+	case ".csv":
+		return FormatCSV, nil
+	}
+	return "", ErrUnsupportedFormat
+}
+
+// NormalizeEventType แปลงประเภทจากไฟล์ให้เป็นค่าที่ระบบรองรับ (exam|activity|holiday)
+func NormalizeEventType(raw string) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "holiday", "public holiday", "holidays", "วันหยุด", "วันหยุดราชการ", "วันหยุดนักขัตฤกษ์":
+		return "holiday", true
+	case "exam", "exams", "examination", "สอบ", "การสอบ":
+		return "exam", true
+	case "activity", "activities", "event", "กิจกรรม":
+		return "activity", true
+	}
+	return "", false
+}
+
+// allDayRange วันหยุด/กิจกรรมทั้งวันเก็บเป็น 00:00 - 23:59:59 ของวันสุดท้าย (เหมือนข้อมูล seed เดิม)
+func allDayRange(first, last time.Time) (time.Time, time.Time) {
+	loc := bangkok()
+	s := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, loc)
+	e := time.Date(last.Year(), last.Month(), last.Day(), 23, 59, 59, 0, loc)
+	return s, e
+}
+
+// ------------------------------
+// CSV
+// ------------------------------
+
+// ชื่อคอลัมน์ที่รับได้ (ไม่สนตัวพิมพ์เล็ก/ใหญ่)
+var csvColumns = map[string][]string{
+	"title":      {"title", "name", "event_name", "ชื่อ", "ชื่อกิจกรรม"},
+	"type":       {"type", "event_type", "ประเภท"},
+	"start_date": {"start_date", "date", "วันที่", "วันเริ่ม"},
+	"end_date":   {"end_date", "วันสิ้นสุด"},
+	"start_time": {"start_time", "เวลาเริ่ม"},
+	"end_time":   {"end_time", "เวลาสิ้นสุด"},
+}
+
+// parseCSVDate รับ YYYY-MM-DD หรือ DD/MM/YYYY (ปี พ.ศ. จะถูกแปลงเป็น ค.ศ.)
+func parseCSVDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	loc := bangkok()
+
+	var (
+		t   time.Time
+		err error
+	)
+	if strings.Contains(s, "/") {
+		t, err = time.ParseInLocation("2/1/2006", s, loc)
+	} else {
+		t, err = time.ParseInLocation("2006-01-02", s, loc)
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q (YYYY-MM-DD or DD/MM/YYYY)", s)
+	}
+	if t.Year() > 2400 {
+		t = t.AddDate(-543, 0, 0)
+	}
+	return t, nil
+}
+
+func parseClock(day time.Time, s string) (time.Time, error) {
+	c, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q (HH:MM)", s)
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), c.Hour(), c.Minute(), 0, 0, day.Location()), nil
+}
+
+// ParseCSV อ่านไฟล์ CSV ที่มีแถวหัวตาราง (อย่างน้อยต้องมี title และ start_date)
+func ParseCSV(r io.Reader) ([]Event, error) {
+	br := bufio.NewReader(r)
+	// ข้าม BOM ที่ Excel ใส่มา
+	if b, err := br.Peek(3); err == nil && string(b) == "\xEF\xBB\xBF" {
+		_, _ = br.Discard(3)
+	}
+
+	cr := csv.NewReader(br)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, ErrEmptyFile
+		}
+		return nil, err
+	}
+
+	idx := map[string]int{}
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(h))
+		for key, names := range csvColumns {
+			for _, n := range names {
+				if h == n {
+					if _, ok := idx[key]; !ok {
+						idx[key] = i
+					}
+				}
+			}
+		}
+	}
+	if _, ok := idx["title"]; !ok {
+		return nil, ErrMissingColumn
+	}
+	if _, ok := idx["start_date"]; !ok {
+		return nil, ErrMissingColumn
+	}
+
+	get := func(rec []string, key string) string {
+		i, ok := idx[key]
+		if !ok || i >= len(rec) {
+			return ""
+		}
+		return strings.TrimSpace(rec[i])
+	}
+
+	var events []Event
+	line := 1
+	for {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line++
+		if err != nil {
+			events = append(events, Event{Line: line, Err: err})
+			continue
+		}
+
+		// ข้ามบรรทัดว่าง
+		if strings.TrimSpace(strings.Join(rec, "")) == "" {
+			continue
+		}
+
+		events = append(events, csvEvent(line, get(rec, "title"), get(rec, "type"),
+			get(rec, "start_date"), get(rec, "end_date"), get(rec, "start_time"), get(rec, "end_time")))
+	}
+
+	if len(events) == 0 {
+		return nil, ErrEmptyFile
+	}
+	return events, nil
+}
+
+func csvEvent(line int, title, typ, startDate, endDate, startTime, endTime string) Event {
+	ev := Event{Line: line, Title: title, RawType: typ}
+
+	if title == "" {
+		ev.Err = errors.New("title is required")
+		return ev
+	}
+
+	first, err := parseCSVDate(startDate)
+	if err != nil {
+		ev.Err = err
+		return ev
+	}
+	last := first
+	if endDate != "" {
+		if last, err = parseCSVDate(endDate); err != nil {
+			ev.Err = err
+			return ev
+		}
+	}
+
+	// ไม่ระบุเวลา = ทั้งวัน
+	if startTime == "" && endTime == "" {
+		ev.AllDay = true
+		ev.Start, ev.End = allDayRange(first, last)
+	} else {
+		if startTime == "" {
+			startTime = "00:00"
+		}
+		if ev.Start, err = parseClock(first, startTime); err != nil {
+			ev.Err = err
+			return ev
+		}
+		if endTime == "" {
+			ev.End = time.Date(last.Year(), last.Month(), last.Day(), 23, 59, 59, 0, last.Location())
+		} else if ev.End, err = parseClock(last, endTime); err != nil {
+			ev.Err = err
+			return ev
+		}
+	}
+
+	if ev.End.Before(ev.Start) {
+		ev.Err = errors.New("end must not be before start")
+	}
+	return ev
+}
+
+// ------------------------------
+// iCalendar (.ics)
+// ------------------------------
+
+type icsProp struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// unfoldICS รวมบรรทัดที่ถูกพับ (บรรทัดที่ขึ้นต้นด้วย space/tab คือบรรทัดต่อ)
+func unfoldICS(r io.Reader) ([]string, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var lines []string
+	for sc.Scan() {
+		l := strings.TrimRight(sc.Text(), "\r")
+		if (strings.HasPrefix(l, " ") || strings.HasPrefix(l, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += l[1:]
+			continue
+		}
+		lines = append(lines, l)
+	}
+	return lines, sc.Err()
+}
+
+func parseICSLine(l string) (icsProp, bool) {
+	// หา ':' ตัวแรกที่ไม่อยู่ในเครื่องหมายคำพูด
+	inQuote := false
+	colon := -1
+	for i, ch := range l {
+		if ch == '"' {
+			inQuote = !inQuote
+		}
+		if ch == ':' && !inQuote {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return icsProp{}, false
+	}
+
+	parts := strings.Split(l[:colon], ";")
+	p := icsProp{
+		name:   strings.ToUpper(parts[0]),
+		params: map[string]string{},
+		value:  l[colon+1:],
+	}
+	for _, kv := range parts[1:] {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			p.params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+	}
+	return p, true
+}
+
+func unescapeICS(s string) string {
+	r := strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+	return r.Replace(s)
+}
+
+// parseICSTime คืนเวลา และบอกว่าเป็นค่าแบบ DATE (ทั้งวัน) หรือไม่
+func parseICSTime(p icsProp) (time.Time, bool, error) {
+	v := strings.TrimSpace(p.value)
+
+	if p.params["VALUE"] == "DATE" || len(v) == 8 {
+		t, err := time.ParseInLocation("20060102", v, bangkok())
+		return t, true, err
+	}
+
+	if strings.HasSuffix(v, "Z") {
+		t, err := time.Parse("20060102T150405Z", v)
+		return t, false, err
+	}
+
+	loc := bangkok()
+	if tz := p.params["TZID"]; tz != "" {
+		if l, err := time.LoadLocation(tz); err == nil {
+			loc = l
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", v, loc)
+	return t, false, err
+}
+
+// ParseICS อ่าน VEVENT ทั้งหมดจากไฟล์ .ics (event ที่ STATUS:CANCELLED จะถูกข้าม)
+func ParseICS(r io.Reader) ([]Event, error) {
+	lines, err := unfoldICS(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		events []Event
+		props  []icsProp
+		inside bool
+		n      int
+	)
+
+	for _, l := range lines {
+		switch strings.ToUpper(strings.TrimSpace(l)) {
+		case "BEGIN:VEVENT":
+			inside = true
+			props = props[:0]
+			continue
+		case "END:VEVENT":
+			if inside {
+				n++
+				if ev, skip := icsEvent(n, props); !skip {
+					events = append(events, ev)
+				}
+			}
+			inside = false
+			continue
+		}
+		if !inside {
+			continue
+		}
+		if p, ok := parseICSLine(l); ok {
+			props = append(props, p)
+		}
+	}
+
+	if len(events) == 0 {
+		return nil, ErrEmptyFile
+	}
+	return events, nil
+}
+
+func icsEvent(n int, props []icsProp) (Event, bool) {
+	ev := Event{Line: n}
+
+	var (
+		start, end       *icsProp
+		startDate, isEnd bool
+	)
+	for i := range props {
+		p := props[i]
+		switch p.name {
+		case "SUMMARY":
+			ev.Title = strings.TrimSpace(unescapeICS(p.value))
+		case "CATEGORIES":
+			// ใช้หมวดแรกที่ระบบรู้จัก
+			for _, c := range strings.Split(p.value, ",") {
+				if _, ok := NormalizeEventType(unescapeICS(c)); ok {
+					ev.RawType = unescapeICS(c)
+					break
+				}
+			}
+		case "STATUS":
+			if strings.EqualFold(strings.TrimSpace(p.value), "CANCELLED") {
+				return ev, true
+			}
+		case "RRULE":
+			ev.RRule = strings.TrimSpace(p.value)
+		case "DTSTART":
+			start = &props[i]
+		case "DTEND":
+			end = &props[i]
+			isEnd = true
+		}
+	}
+
+	if ev.Title == "" {
+		ev.Err = errors.New("SUMMARY is required")
+		return ev, false
+	}
+	if start == nil {
+		ev.Err = errors.New("DTSTART is required")
+		return ev, false
+	}
+
+	s, isDate, err := parseICSTime(*start)
+	if err != nil {
+		ev.Err = fmt.Errorf("invalid DTSTART %q", start.value)
+		return ev, false
+	}
+	startDate = isDate
+
+	e := s
+	if isEnd {
+		if e, _, err = parseICSTime(*end); err != nil {
+			ev.Err = fmt.Errorf("invalid DTEND %q", end.value)
+			return ev, false
+		}
+	} else if startDate {
+		e = s.AddDate(0, 0, 1)
+	}
+
+	if startDate {
+		// DTEND แบบ DATE เป็นวันถัดจากวันสุดท้าย (exclusive)
+		last := e.AddDate(0, 0, -1)
+		if last.Before(s) {
+			last = s
+		}
+		ev.AllDay = true
+		ev.Start, ev.End = allDayRange(s, last)
+	} else {
+		ev.Start, ev.End = s.In(bangkok()), e.In(bangkok())
+	}
+
+	if ev.End.Before(ev.Start) {
+		ev.Err = errors.New("DTEND must not be before DTSTART")
+	}
+	return ev, false
+}
@@ -0,0 +1,105 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrUnknownColumn = errors.New("unknown column")
+
+// Column นิยามคอลัมน์ 1 คอลัมน์ของไฟล์ export
+// Key ใช้ตอนเลือกคอลัมน์ผ่าน ?columns=  ส่วน Header คือหัวตารางที่แสดงในไฟล์
+type Column[T any] struct {
+	Key    string
+	Header string
+	Value  func(T) string
+}
+
+// SelectColumns เลือกคอลัมน์ตามลำดับที่ผู้ใช้ส่งมา (ว่าง = ทุกคอลัมน์)
+func SelectColumns[T any](all []Column[T], keys string) ([]Column[T], error) {
+	keys = strings.TrimSpace(keys)
+	if keys == "" {
+		return all, nil
+	}
+
+	byKey := make(map[string]Column[T], len(all))
+	for _, c := range all {
+		byKey[c.Key] = c
+	}
+
+	var out []Column[T]
+	seen := map[string]bool{}
+	for _, k := range strings.Split(keys, ",") {
+		k = strings.TrimSpace(k)
+		if k == "" || seen[k] {
+			continue
+		}
+		c, ok := byKey[k]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownColumn, k)
+		}
+		seen[k] = true
+		out = append(out, c)
+	}
+	if len(out) == 0 {
+		return all, nil
+	}
+	return out, nil
+}
+
+func headersOf[T any](cols []Column[T]) []string {
+	out := make([]string, len(cols))
+	for i, c := range cols {
+		out[i] = c.Header
+	}
+	return out
+}
+
+func valuesOf[T any](cols []Column[T], item T) []string {
+	out := make([]string, len(cols))
+	for i, c := range cols {
+		out[i] = c.Value(item)
+	}
+	return out
+}
+
+// ColumnKeys คืนรายชื่อ key ทั้งหมด (ใช้แสดงใน error / เอกสาร API)
+func ColumnKeys[T any](all []Column[T]) []string {
+	out := make([]string, len(all))
+	for i, c := range all {
+		out[i] = c.Key
+	}
+	return out
+}
+
+// ------------------------------
+// format helpers (เวลาไทย)
+// ------------------------------
+
+func bangkok() *time.Location {
+	loc, err := time.LoadLocation("Asia/Bangkok")
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+func formatDateTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.In(bangkok()).Format("2006-01-02 15:04")
+}
+
+func formatBool(b bool) string {
+	if b {
+		return "ใช่"
+	}
+	return "ไม่ใช่"
+}
+
+func fullName(first, last string) string {
+	return strings.TrimSpace(first + " " + last)
+}
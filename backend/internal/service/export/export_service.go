@@ -0,0 +1,347 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"backend/internal/app/dto"
+	"backend/internal/app/entity"
+	"backend/internal/service/advisorlog"
+)
+
+// จำนวนแถวที่ดึงจาก DB ต่อรอบ (ไม่โหลดทั้งตารางเข้า memory)
+const batchSize = 500
+
+var (
+	ErrForbidden        = errors.New("forbidden")
+	ErrInvalidDateRange = errors.New("invalid date range")
+)
+
+type Service interface {
+	Appointments(ctx context.Context, f dto.ExportFilter, requesterID uint, requesterRole string) (*Job, error)
+	AdvisorLogs(ctx context.Context, f dto.ExportFilter, requesterID uint, requesterRole string) (*Job, error)
+	Reports(ctx context.Context, f dto.ExportFilter, requesterID uint, requesterRole string) (*Job, error)
+}
+
+// Job คือ export ที่ตรวจสิทธิ์/คอลัมน์ผ่านแล้ว รอเขียนลง writer
+// แยกเป็น 2 ขั้นเพื่อให้ controller ตอบ error เป็น JSON ได้ก่อนเริ่ม stream ไฟล์
+type Job struct {
+	Name    string
+	headers []string
+	run     func(ctx context.Context, w RowWriter) error
+}
+
+// Run เขียน header แล้วดึงข้อมูลทีละ batch เขียนลง writer
+func (j *Job) Run(ctx context.Context, w RowWriter) error {
+	if err := w.WriteHeader(j.headers); err != nil {
+		return err
+	}
+	if err := j.run(ctx, w); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+type service struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) Service {
+	return &service{db: db}
+}
+
+// ------------------------------
+// helpers
+// ------------------------------
+
+// scopeAdvisor: advisor เห็นเฉพาะของตัวเอง, admin เลือก advisor ได้ (ภายในหน่วยงาน ดู scopeDepartment), student ห้าม export
+func scopeAdvisor(f dto.ExportFilter, requesterID uint, requesterRole string) (uint, error) {
+	switch strings.ToLower(strings.TrimSpace(requesterRole)) {
+	case "admin":
+		return f.AdvisorID, nil
+	case "advisor":
+		return requesterID, nil
+	default:
+		return 0, ErrForbidden
+	}
+}
+
+// scopeDepartment จำกัด admin ให้เห็นเฉพาะนัดของนักศึกษาในหน่วยงานตัวเอง
+// (ใช้ advisorlog.DepartmentScope ตัวเดียวกัน, admin ที่ไม่สังกัดหน่วยงานเห็นทั้งหมด)
+func (s *service) scopeDepartment(ctx context.Context, q *gorm.DB, requesterID uint, requesterRole string) (*gorm.DB, error) {
+	if strings.ToLower(strings.TrimSpace(requesterRole)) != "admin" {
+		return q, nil
+	}
+	q, err := advisorlog.DepartmentScope(s.db.WithContext(ctx), q, requesterID)
+	if errors.Is(err, advisorlog.ErrForbidden) {
+		return nil, ErrForbidden
+	}
+	return q, err
+}
+
+func checkRange(f dto.ExportFilter) error {
+	if f.From != nil && f.To != nil && !f.From.Before(*f.To) {
+		return ErrInvalidDateRange
+	}
+	return nil
+}
+
+func applyRange(q *gorm.DB, column string, f dto.ExportFilter) *gorm.DB {
+	if f.From != nil {
+		q = q.Where(column+" >= ?", *f.From)
+	}
+	if f.To != nil {
+		q = q.Where(column+" < ?", *f.To)
+	}
+	return q
+}
+
+// streamBatches ดึงข้อมูลด้วย FindInBatches แล้วเขียนทีละแถว
+func streamBatches[T any](ctx context.Context, q *gorm.DB, cols []Column[T], w RowWriter) error {
+	var batch []T
+	res := q.WithContext(ctx).FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+		for _, item := range batch {
+			if err := w.WriteRow(valuesOf(cols, item)); err != nil {
+				return err
+			}
+		}
+		return w.Flush()
+	})
+	return res.Error
+}
+
+// ------------------------------
+// APPOINTMENTS
+// ------------------------------
+
+var AppointmentColumns = []Column[entity.Appointment]{
+	{"id", "รหัสนัดหมาย", func(a entity.Appointment) string { return fmt.Sprint(a.ID) }},
+	{"student_sut_id", "รหัสนักศึกษา", func(a entity.Appointment) string { return a.StudentUser.SutId }},
+	{"student_name", "ชื่อนักศึกษา", func(a entity.Appointment) string {
+		return fullName(a.StudentUser.FirstName, a.StudentUser.LastName)
+	}},
+	{"advisor_name", "อาจารย์ที่ปรึกษา", func(a entity.Appointment) string {
+		return fullName(a.AdvisorUser.FirstName, a.AdvisorUser.LastName)
+	}},
+	{"topic", "หัวข้อ", func(a entity.Appointment) string { return a.Topic.Topic }},
+	{"category", "ประเภท", func(a entity.Appointment) string { return a.Category.Category }},
+	{"description", "รายละเอียด", func(a entity.Appointment) string { return a.Description }},
+	{"status", "สถานะ", func(a entity.Appointment) string { return a.AppointmentStatus.StatusName }},
+	{"submitted_at", "วันที่ยื่นคำขอ", func(a entity.Appointment) string { return formatDateTime(a.CreatedAt) }},
+}
+
+func (s *service) Appointments(ctx context.Context, f dto.ExportFilter, requesterID uint, requesterRole string) (*Job, error) {
+	advisorID, err := scopeAdvisor(f, requesterID, requesterRole)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRange(f); err != nil {
+		return nil, err
+	}
+	cols, err := SelectColumns(AppointmentColumns, f.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	q := s.db.Model(&entity.Appointment{}).
+		Preload("StudentUser").
+		Preload("AdvisorUser").
+		Preload("Topic").
+		Preload("Category").
+		Preload("AppointmentStatus")
+
+	if q, err = s.scopeDepartment(ctx, q, requesterID, requesterRole); err != nil {
+		return nil, err
+	}
+	if advisorID != 0 {
+		q = q.Where("appointments.advisor_user_id = ?", advisorID)
+	}
+	if f.Status != "" {
+		q = q.Joins("JOIN appointment_statuses ON appointment_statuses.id = appointments.appointment_status_id").
+			Where("appointment_statuses.status_code = ?", strings.ToUpper(f.Status))
+	}
+	q = applyRange(q, "appointments.created_at", f)
+
+	return &Job{
+		Name:    "appointments",
+		headers: headersOf(cols),
+		run: func(ctx context.Context, w RowWriter) error {
+			return streamBatches(ctx, q, cols, w)
+		},
+	}, nil
+}
+
+// ------------------------------
+// ADVISOR LOGS (+ สถานะรายงานความก้าวหน้าล่าสุด)
+// ------------------------------
+
+func latestReport(l entity.AdvisorLog) *entity.ProgressReport {
+	var latest *entity.ProgressReport
+	for i := range l.ProgressReports {
+		r := &l.ProgressReports[i]
+		if latest == nil || r.SubmittedAt.After(latest.SubmittedAt) {
+			latest = r
+		}
+	}
+	return latest
+}
+
+var AdvisorLogColumns = []Column[entity.AdvisorLog]{
+	{"id", "รหัสบันทึก", func(l entity.AdvisorLog) string { return fmt.Sprint(l.ID) }},
+	{"appointment_id", "รหัสนัดหมาย", func(l entity.AdvisorLog) string { return fmt.Sprint(l.AppointmentID) }},
+	{"student_sut_id", "รหัสนักศึกษา", func(l entity.AdvisorLog) string {
+		if l.Appointment == nil {
+			return ""
+		}
+		return l.Appointment.StudentUser.SutId
+	}},
+	{"student_name", "ชื่อนักศึกษา", func(l entity.AdvisorLog) string {
+		if l.Appointment == nil {
+			return ""
+		}
+		return fullName(l.Appointment.StudentUser.FirstName, l.Appointment.StudentUser.LastName)
+	}},
+	{"advisor_name", "อาจารย์ที่ปรึกษา", func(l entity.AdvisorLog) string {
+		if l.Appointment == nil {
+			return ""
+		}
+		return fullName(l.Appointment.AdvisorUser.FirstName, l.Appointment.AdvisorUser.LastName)
+	}},
+	{"title", "หัวข้อ", func(l entity.AdvisorLog) string { return l.Title }},
+	{"body", "สรุปการปรึกษา", func(l entity.AdvisorLog) string { return l.Body }},
+	{"status", "สถานะบันทึก", func(l entity.AdvisorLog) string { return l.Status }},
+	{"requires_report", "ต้องส่งรายงาน", func(l entity.AdvisorLog) string { return formatBool(l.RequiresReport) }},
+	{"tags", "แท็ก", func(l entity.AdvisorLog) string {
+		names := make([]string, 0, len(l.Tags))
+		for _, t := range l.Tags {
+			names = append(names, t.Label)
+		}
+		return strings.Join(names, ", ")
+	}},
+	{"report_status", "สถานะรายงานล่าสุด", func(l entity.AdvisorLog) string {
+		if r := latestReport(l); r != nil {
+			return r.Status
+		}
+		return ""
+	}},
+	{"report_submitted_at", "วันที่ส่งรายงานล่าสุด", func(l entity.AdvisorLog) string {
+		if r := latestReport(l); r != nil {
+			return formatDateTime(r.SubmittedAt)
+		}
+		return ""
+	}},
+	{"created_at", "วันที่บันทึก", func(l entity.AdvisorLog) string { return formatDateTime(l.CreatedAt) }},
+}
+
+func (s *service) AdvisorLogs(ctx context.Context, f dto.ExportFilter, requesterID uint, requesterRole string) (*Job, error) {
+	if _, err := scopeAdvisor(f, requesterID, requesterRole); err != nil {
+		return nil, err
+	}
+	if err := checkRange(f); err != nil {
+		return nil, err
+	}
+	cols, err := SelectColumns(AdvisorLogColumns, f.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	// ขอบเขตเดียวกับหน้ารายการบันทึก (หน่วยงานของ admin, ไม่รวม Draft ของคนอื่น)
+	q, err := advisorlog.Scope(s.db.WithContext(ctx), requesterID, requesterRole)
+	if err != nil {
+		if errors.Is(err, advisorlog.ErrForbidden) {
+			return nil, ErrForbidden
+		}
+		return nil, err
+	}
+	q = q.
+		Preload("Appointment.StudentUser").
+		Preload("Appointment.AdvisorUser").
+		Preload("ProgressReports").
+		Preload("Tags")
+
+	if f.AdvisorID != 0 {
+		q = q.Where("appointments.advisor_user_id = ?", f.AdvisorID)
+	}
+	if f.Status != "" {
+		q = q.Where("advisor_logs.status = ?", f.Status)
+	}
+	q = applyRange(q, "advisor_logs.created_at", f)
+
+	return &Job{
+		Name:    "advisor_logs",
+		headers: headersOf(cols),
+		run: func(ctx context.Context, w RowWriter) error {
+			return streamBatches(ctx, q, cols, w)
+		},
+	}, nil
+}
+
+// ------------------------------
+// ISSUE REPORTS (admin เท่านั้น)
+// ------------------------------
+
+var ReportColumns = []Column[entity.Report]{
+	{"id", "รหัสรายงานปัญหา", func(r entity.Report) string { return fmt.Sprint(r.ID) }},
+	{"reporter_name", "ผู้แจ้ง", func(r entity.Report) string {
+		if r.User == nil {
+			return ""
+		}
+		return fullName(r.User.FirstName, r.User.LastName)
+	}},
+	{"reporter_email", "อีเมลผู้แจ้ง", func(r entity.Report) string {
+		if r.User == nil {
+			return ""
+		}
+		return r.User.Email
+	}},
+	{"topic", "หัวข้อ", func(r entity.Report) string {
+		if r.Topic == nil {
+			return ""
+		}
+		return r.Topic.ReportTopicName
+	}},
+	{"description", "รายละเอียด", func(r entity.Report) string { return r.Description }},
+	{"status", "สถานะ", func(r entity.Report) string {
+		if r.Status == nil {
+			return ""
+		}
+		return r.Status.ReportStatusName
+	}},
+	{"created_at", "วันที่แจ้ง", func(r entity.Report) string { return formatDateTime(r.CreatedAt) }},
+}
+
+func (s *service) Reports(ctx context.Context, f dto.ExportFilter, requesterID uint, requesterRole string) (*Job, error) {
+	if strings.ToLower(strings.TrimSpace(requesterRole)) != "admin" {
+		return nil, ErrForbidden
+	}
+	if err := checkRange(f); err != nil {
+		return nil, err
+	}
+	cols, err := SelectColumns(ReportColumns, f.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	q := s.db.Model(&entity.Report{}).
+		Preload("User").
+		Preload("Status").
+		Preload("Topic")
+
+	if f.Status != "" {
+		q = q.Joins("JOIN report_statuses ON report_statuses.id = reports.report_status_id").
+			Where("report_statuses.report_status_name = ?", f.Status)
+	}
+	q = applyRange(q, "reports.created_at", f)
+
+	return &Job{
+		Name:    "reports",
+		headers: headersOf(cols),
+		run: func(ctx context.Context, w RowWriter) error {
+			return streamBatches(ctx, q, cols, w)
+		},
+	}, nil
+}
@@ -0,0 +1,189 @@
+package export
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+var ErrInvalidFormat = errors.New("invalid export format (csv|xlsx)")
+
+// utf8BOM ใส่หน้าไฟล์ CSV เพื่อให้ Excel เปิดภาษาไทยได้ถูกต้อง
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// RowWriter เขียนข้อมูลทีละแถว (ไม่ต้องโหลดทั้งหมดไว้ใน memory)
+type RowWriter interface {
+	WriteHeader(headers []string) error
+	WriteRow(values []string) error
+	// Flush ส่งข้อมูลที่ buffer ไว้ออกไปยัง client (เรียกหลังจบแต่ละ batch)
+	Flush() error
+	// Close ปิดไฟล์ (XLSX จะเขียนไฟล์จริงออกไปตอนนี้)
+	Close() error
+}
+
+// NormalizeFormat แปลงค่า ?format= ให้เป็นตัวพิมพ์เล็ก (ค่า default = csv)
+func NormalizeFormat(format string) (string, error) {
+	f := strings.ToLower(strings.TrimSpace(format))
+	if f == "" {
+		f = FormatCSV
+	}
+	if f != FormatCSV && f != FormatXLSX {
+		return "", ErrInvalidFormat
+	}
+	return f, nil
+}
+
+// ContentType คืน MIME type ของไฟล์ตาม format
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// NewRowWriter สร้าง writer ตาม format
+func NewRowWriter(format string, w io.Writer, sheetName string) (RowWriter, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatXLSX:
+		return newXLSXWriter(w, sheetName)
+	default:
+		return nil, ErrInvalidFormat
+	}
+}
+
+// escapeFormula กัน formula injection: ค่าที่ขึ้นต้นด้วย = + - @ tab หรือ CR
+// (เช่นรายละเอียดที่นักศึกษากรอก) Excel จะตีความเป็นสูตร จึงเติม ' นำหน้าให้เป็นข้อความ
+func escapeFormula(v string) string {
+	if v == "" {
+		return v
+	}
+	switch v[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + v
+	}
+	return v
+}
+
+// ------------------------------
+// CSV
+// ------------------------------
+
+type csvWriter struct {
+	out io.Writer
+	cw  *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	if _, err := w.Write(utf8BOM); err != nil {
+		return nil, err
+	}
+	return &csvWriter{out: w, cw: csv.NewWriter(w)}, nil
+}
+
+func (c *csvWriter) WriteHeader(headers []string) error {
+	return c.cw.Write(headers)
+}
+
+func (c *csvWriter) WriteRow(values []string) error {
+	row := make([]string, len(values))
+	for i, v := range values {
+		row[i] = escapeFormula(v)
+	}
+	return c.cw.Write(row)
+}
+
+func (c *csvWriter) Flush() error {
+	c.cw.Flush()
+	if err := c.cw.Error(); err != nil {
+		return err
+	}
+	if f, ok := c.out.(interface{ Flush() }); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+func (c *csvWriter) Close() error {
+	return c.Flush()
+}
+
+// ------------------------------
+// XLSX (ใช้ StreamWriter ของ excelize)
+// ------------------------------
+
+type xlsxWriter struct {
+	out   io.Writer
+	file  *excelize.File
+	sw    *excelize.StreamWriter
+	rowNo int
+}
+
+func newXLSXWriter(w io.Writer, sheetName string) (*xlsxWriter, error) {
+	if sheetName == "" {
+		sheetName = "Sheet1"
+	}
+
+	f := excelize.NewFile()
+	if err := f.SetSheetName("Sheet1", sheetName); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	sw, err := f.NewStreamWriter(sheetName)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	return &xlsxWriter{out: w, file: f, sw: sw}, nil
+}
+
+func (x *xlsxWriter) WriteHeader(headers []string) error {
+	return x.writeRow(headers)
+}
+
+func (x *xlsxWriter) WriteRow(values []string) error {
+	row := make([]string, len(values))
+	for i, v := range values {
+		row[i] = escapeFormula(v)
+	}
+	return x.writeRow(row)
+}
+
+func (x *xlsxWriter) writeRow(values []string) error {
+	x.rowNo++
+	cell, err := excelize.CoordinatesToCellName(1, x.rowNo)
+	if err != nil {
+		return err
+	}
+
+	row := make([]interface{}, len(values))
+	for i, v := range values {
+		row[i] = v
+	}
+	return x.sw.SetRow(cell, row)
+}
+
+// Flush: XLSX ต้องเขียนทั้งไฟล์ตอนจบ (StreamWriter จะพักแถวลง temp file เองเมื่อข้อมูลเยอะ)
+func (x *xlsxWriter) Flush() error {
+	return nil
+}
+
+func (x *xlsxWriter) Close() error {
+	defer x.file.Close()
+
+	if err := x.sw.Flush(); err != nil {
+		return err
+	}
+	return x.file.Write(x.out)
+}
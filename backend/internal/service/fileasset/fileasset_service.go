@@ -0,0 +1,295 @@
+package fileasset
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"backend/internal/app/dto"
+	"backend/internal/app/entity"
+	"backend/internal/service/storage"
+)
+
+var (
+	ErrSaveFileFailed = errors.New("save file failed")
+	ErrFileNotFound   = errors.New("file not found")
+)
+
+// Service จัดการไฟล์อัปโหลด: เขียนไฟล์ลง storage + เก็บ metadata 1 แถวต่อไฟล์ในตาราง file_assets
+// (file_assets.storage_path เก็บ key ของ storage ไม่ใช่ path บนดิสก์)
+//
+// ขั้นตอนปกติ:
+//  1. Store/StoreAll เขียนไฟล์และคำนวณ size/sha256 (ยังไม่ลง DB)
+//  2. Attach ใน transaction เดียวกับการสร้างเจ้าของ (log/report)
+//  3. ถ้า transaction ล้มเหลว เรียก Discard เพื่อลบไฟล์ที่เขียนไปแล้ว
+type Service interface {
+	// Validate ตรวจไฟล์ตาม Policy + โควต้าผู้ใช้ก่อน Store (คืน *UploadError ถ้าไม่ผ่าน)
+	Validate(ctx context.Context, p Policy, files []*multipart.FileHeader, uploaderID uint, replacing int64) error
+	UsedBytes(ctx context.Context, uploaderID uint) (int64, error)
+
+	Store(fh *multipart.FileHeader, uploaderID uint) (*entity.FileAssets, error)
+	StoreAll(files []*multipart.FileHeader, uploaderID uint) ([]entity.FileAssets, error)
+	Discard(assets []entity.FileAssets)
+
+	Attach(tx *gorm.DB, assets []entity.FileAssets, ownerType string, ownerID uint) error
+	// Detach ลบแถวของเจ้าของ (soft delete) แล้วคืนรายการไฟล์เดิม ให้เรียก Discard หลัง commit
+	Detach(tx *gorm.DB, ownerType string, ownerID uint) ([]entity.FileAssets, error)
+
+	ListByOwner(ctx context.Context, ownerType string, ownerID uint) ([]entity.FileAssets, error)
+	// Get ไฟล์ตาม id (ไม่ตรวจสิทธิ์ ใช้หลังตรวจลายเซ็นลิงก์แล้วเท่านั้น)
+	Get(ctx context.Context, id uint) (*entity.FileAssets, error)
+	// Open เปิดไฟล์จาก storage (ErrFileNotFound ถ้าไฟล์หาย) ผู้เรียกต้อง Close เอง
+	Open(ctx context.Context, asset entity.FileAssets) (storage.Object, *storage.ObjectInfo, error)
+}
+
+type service struct {
+	db    *gorm.DB
+	store storage.Storage
+}
+
+func New(db *gorm.DB, store storage.Storage) Service {
+	if store == nil {
+		store = storage.NewLocal("uploads")
+	}
+	return &service{db: db, store: store}
+}
+
+// ------------------------------
+// helpers
+// ------------------------------
+
+// DetectMimeType ตรวจจากเนื้อหาไฟล์ก่อน ถ้าได้แค่ชนิดกว้างๆ (octet-stream / text/plain)
+// ค่อยใช้ Content-Type ที่ browser ส่งมา แล้วจึงเดาจากนามสกุล
+// (ใช้เป็น metadata เท่านั้น การอนุญาตไฟล์ตัดสินจาก Policy.Check ที่ดูเนื้อหาอย่างเดียว)
+func DetectMimeType(fh *multipart.FileHeader) string {
+	sniffed := ""
+	if m, err := sniff(fh); err == nil {
+		if base, _, err := mime.ParseMediaType(m.String()); err == nil {
+			sniffed = base
+		}
+	}
+	if sniffed != "" && sniffed != "application/octet-stream" && sniffed != "text/plain" {
+		return sniffed
+	}
+	// ยอมใช้ชนิดที่ client บอกเฉพาะชนิดที่ระบบรู้จัก (กันการตั้งเป็น text/html แล้วถูกเสิร์ฟกลับ)
+	if mt := declaredMimeType(fh); mt != "" && knownType(mt) {
+		return mt
+	}
+	if sniffed != "" {
+		return sniffed
+	}
+	return "application/octet-stream"
+}
+
+// declaredMimeType ชนิดไฟล์ตามที่ client บอก (Content-Type หรือนามสกุล)
+func declaredMimeType(fh *multipart.FileHeader) string {
+	if ct := strings.TrimSpace(fh.Header.Get("Content-Type")); ct != "" {
+		if mt, _, err := mime.ParseMediaType(ct); err == nil {
+			return mt
+		}
+	}
+	if mt := mime.TypeByExtension(strings.ToLower(filepath.Ext(fh.Filename))); mt != "" {
+		if base, _, err := mime.ParseMediaType(mt); err == nil {
+			return base
+		}
+	}
+	return ""
+}
+
+// countingReader นับจำนวน byte ที่อ่านผ่าน (ใช้คู่กับ TeeReader เพื่อคำนวณ SHA-256 ระหว่างอัปโหลด)
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// extensionFor นามสกุลของ key ตามชนิดไฟล์จริง (ไม่เชื่อนามสกุลจาก client)
+func extensionFor(mimeType, fileName string) string {
+	if m := mimetype.Lookup(mimeType); m != nil && m.Extension() != "" {
+		return m.Extension()
+	}
+	if exts, _ := mime.ExtensionsByType(mimeType); len(exts) > 0 {
+		return exts[0]
+	}
+	// ไม่รู้จักชนิด: ใช้นามสกุลเดิมเฉพาะที่เป็นตัวอักษร/ตัวเลข
+	ext := strings.ToLower(filepath.Ext(fileName))
+	for _, r := range strings.TrimPrefix(ext, ".") {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9') {
+			return ".bin"
+		}
+	}
+	if ext == "" || ext == "." {
+		return ".bin"
+	}
+	return ext
+}
+
+// HashFile คำนวณขนาดและ SHA-256 ของไฟล์ที่มีอยู่แล้ว (ใช้ตอนย้ายข้อมูลเก่า)
+func HashFile(path string) (int64, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return 0, "", err
+	}
+	return n, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ------------------------------
+// STORE
+// ------------------------------
+
+func (s *service) Store(fh *multipart.FileHeader, uploaderID uint) (*entity.FileAssets, error) {
+	mimeType := DetectMimeType(fh)
+	key := uuid.New().String() + extensionFor(mimeType, fh.Filename)
+
+	src, err := fh.Open()
+	if err != nil {
+		return nil, ErrSaveFileFailed
+	}
+	defer src.Close()
+
+	h := sha256.New()
+	cr := &countingReader{r: io.TeeReader(src, h)}
+	if err := s.store.Put(context.Background(), key, cr, fh.Size, mimeType); err != nil {
+		_ = s.store.Delete(context.Background(), key)
+		return nil, ErrSaveFileFailed
+	}
+
+	asset := &entity.FileAssets{
+		OriginalName: filepath.Base(fh.Filename),
+		MimeType:     mimeType,
+		Size:         cr.n,
+		SHA256:       hex.EncodeToString(h.Sum(nil)),
+		StoragePath:  key,
+	}
+	if uploaderID != 0 {
+		asset.UploaderID = &uploaderID
+	}
+	return asset, nil
+}
+
+func (s *service) StoreAll(files []*multipart.FileHeader, uploaderID uint) ([]entity.FileAssets, error) {
+	out := make([]entity.FileAssets, 0, len(files))
+	for _, fh := range files {
+		asset, err := s.Store(fh, uploaderID)
+		if err != nil {
+			s.Discard(out)
+			return nil, err
+		}
+		out = append(out, *asset)
+	}
+	return out, nil
+}
+
+func (s *service) Discard(assets []entity.FileAssets) {
+	for _, a := range assets {
+		if a.StoragePath == "" {
+			continue
+		}
+		_ = s.store.Delete(context.Background(), a.StoragePath)
+	}
+}
+
+// ------------------------------
+// OWNER
+// ------------------------------
+
+func (s *service) Attach(tx *gorm.DB, assets []entity.FileAssets, ownerType string, ownerID uint) error {
+	if len(assets) == 0 {
+		return nil
+	}
+	for i := range assets {
+		assets[i].OwnerType = ownerType
+		assets[i].OwnerID = ownerID
+		assets[i].Position = i
+	}
+	return tx.Create(&assets).Error
+}
+
+func (s *service) Detach(tx *gorm.DB, ownerType string, ownerID uint) ([]entity.FileAssets, error) {
+	var old []entity.FileAssets
+	if err := tx.Where("owner_type = ? AND owner_id = ?", ownerType, ownerID).Find(&old).Error; err != nil {
+		return nil, err
+	}
+	if len(old) == 0 {
+		return nil, nil
+	}
+	if err := tx.Delete(&old).Error; err != nil {
+		return nil, err
+	}
+	return old, nil
+}
+
+func (s *service) ListByOwner(ctx context.Context, ownerType string, ownerID uint) ([]entity.FileAssets, error) {
+	var assets []entity.FileAssets
+	err := s.db.WithContext(ctx).
+		Where("owner_type = ? AND owner_id = ?", ownerType, ownerID).
+		Order("position asc, id asc").
+		Find(&assets).Error
+	return assets, err
+}
+
+func (s *service) Get(ctx context.Context, id uint) (*entity.FileAssets, error) {
+	var asset entity.FileAssets
+	if err := s.db.WithContext(ctx).First(&asset, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFileNotFound
+		}
+		return nil, err
+	}
+	return &asset, nil
+}
+
+func (s *service) Open(ctx context.Context, asset entity.FileAssets) (storage.Object, *storage.ObjectInfo, error) {
+	key := strings.TrimSpace(asset.StoragePath)
+	if key == "" {
+		return nil, nil, ErrFileNotFound
+	}
+	obj, info, err := s.store.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) || errors.Is(err, storage.ErrInvalidKey) {
+			return nil, nil, ErrFileNotFound
+		}
+		return nil, nil, err
+	}
+	return obj, info, nil
+}
+
+// ToResp แปลงเป็น DTO (index = ลำดับในรายการ ใช้กับ endpoint ดาวน์โหลดแบบ /files/:index)
+func ToResp(assets []entity.FileAssets) []dto.FileAssetResp {
+	out := make([]dto.FileAssetResp, 0, len(assets))
+	for i, a := range assets {
+		out = append(out, dto.FileAssetResp{
+			ID:         a.ID,
+			Index:      i,
+			Name:       a.OriginalName,
+			MimeType:   a.MimeType,
+			Size:       a.Size,
+			SHA256:     a.SHA256,
+			UploadedAt: a.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	return out
+}
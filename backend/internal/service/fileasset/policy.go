@@ -0,0 +1,312 @@
+package fileasset
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gabriel-vasile/mimetype"
+
+	"backend/internal/app/entity"
+)
+
+const mb = 1 << 20
+
+// รหัส error ของการอัปโหลด (ส่งกลับไปให้ frontend ใน field "code")
+const (
+	CodeInvalidMultipart = "invalid_multipart"
+	CodeRequestTooLarge  = "request_too_large"
+	CodeTooManyFiles     = "too_many_files"
+	CodeEmptyFile        = "empty_file"
+	CodeFileTooLarge     = "file_too_large"
+	CodeUnsupportedType  = "unsupported_type"
+	CodeQuotaExceeded    = "quota_exceeded"
+)
+
+// UploadError error แบบมีโครงสร้าง บอกได้ว่าไฟล์ไหนผิดกฎข้อไหน
+type UploadError struct {
+	Code    string   `json:"code"`
+	Message string   `json:"message"`
+	File    string   `json:"file,omitempty"`
+	Limit   int64    `json:"limit,omitempty"`
+	Allowed []string `json:"allowed,omitempty"`
+}
+
+func (e *UploadError) Error() string {
+	if e.File != "" {
+		return e.File + ": " + e.Message
+	}
+	return e.Message
+}
+
+// Policy กฎการอัปโหลดของแต่ละฟีเจอร์
+type Policy struct {
+	Feature        string
+	AllowedTypes   []string // ตรวจจาก magic bytes ไม่ใช่นามสกุลหรือ Content-Type ที่ client ส่งมา
+	MaxFileSize    int64
+	MaxFiles       int
+	MaxRequestSize int64
+}
+
+var documentTypes = []string{
+	"application/pdf",
+	"application/msword",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	"application/vnd.ms-excel",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	"application/vnd.ms-powerpoint",
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	"text/plain",
+	"text/csv",
+}
+
+var imageTypes = []string{
+	"image/png",
+	"image/jpeg",
+	"image/webp",
+	"image/gif",
+}
+
+// blockedTypes ชนิดที่ browser ตีความเป็นเอกสาร/สคริปต์ได้ ห้ามอัปโหลดแม้จะเป็นลูกของ text/plain
+var blockedTypes = []string{
+	"text/html",
+	"application/xhtml+xml",
+	"image/svg+xml",
+	"text/xml",
+	"application/xml",
+}
+
+// InlineSafe แสดงผลใน browser ได้โดยไม่เสี่ยงรันสคริปต์ (เฉพาะรูปภาพในรายการ)
+// ชนิดอื่นต้องเสิร์ฟเป็น attachment แบบ application/octet-stream
+func InlineSafe(mt string) bool {
+	base, _, err := mime.ParseMediaType(mt)
+	if err != nil {
+		return false
+	}
+	for _, t := range imageTypes {
+		if t == base {
+			return true
+		}
+	}
+	return false
+}
+
+// knownType ชนิดไฟล์ที่อยู่ในรายการเอกสาร/รูปภาพของระบบ
+func knownType(mt string) bool {
+	for _, t := range documentTypes {
+		if t == mt {
+			return true
+		}
+	}
+	for _, t := range imageTypes {
+		if t == mt {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	PolicyAdvisorLog = Policy{
+		Feature:        entity.FileOwnerAdvisorLog,
+		AllowedTypes:   append(append([]string{}, documentTypes...), imageTypes...),
+		MaxFileSize:    20 * mb,
+		MaxFiles:       10,
+		MaxRequestSize: 50 * mb,
+	}
+	PolicyProgressReport = Policy{
+		Feature:        entity.FileOwnerProgressReport,
+		AllowedTypes:   append(append([]string{}, documentTypes...), imageTypes...),
+		MaxFileSize:    20 * mb,
+		MaxFiles:       5,
+		MaxRequestSize: 50 * mb,
+	}
+	PolicyReportFeedback = Policy{
+		Feature:        entity.FileOwnerReportFeedback,
+		AllowedTypes:   append(append([]string{}, documentTypes...), imageTypes...),
+		MaxFileSize:    10 * mb,
+		MaxFiles:       3,
+		MaxRequestSize: 30 * mb,
+	}
+	PolicyAppointmentAttachment = Policy{
+		Feature:        entity.FileOwnerAppointment,
+		AllowedTypes:   append(append([]string{}, documentTypes...), imageTypes...),
+		MaxFileSize:    10 * mb,
+		MaxFiles:       5,
+		MaxRequestSize: 30 * mb,
+	}
+	PolicyReportImage = Policy{
+		Feature:        entity.FileOwnerReport,
+		AllowedTypes:   imageTypes,
+		MaxFileSize:    5 * mb,
+		MaxFiles:       5,
+		MaxRequestSize: 20 * mb,
+	}
+	PolicyProfileImage = Policy{
+		Feature:        entity.FileOwnerProfileImage,
+		AllowedTypes:   []string{"image/png", "image/jpeg", "image/webp"},
+		MaxFileSize:    2 * mb,
+		MaxFiles:       1,
+		MaxRequestSize: 3 * mb,
+	}
+)
+
+// UserQuota พื้นที่รวมต่อผู้ใช้ (UPLOAD_USER_QUOTA_MB, ค่าเริ่มต้น 500MB, 0 = ไม่จำกัด)
+func UserQuota() int64 {
+	if v := strings.TrimSpace(os.Getenv("UPLOAD_USER_QUOTA_MB")); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil && n >= 0 {
+			return n * mb
+		}
+	}
+	return 500 * mb
+}
+
+// ------------------------------
+// DETECT
+// ------------------------------
+
+// sniff ตรวจชนิดไฟล์จากเนื้อหา (magic bytes)
+func sniff(fh *multipart.FileHeader) (*mimetype.MIME, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return mimetype.DetectReader(f)
+}
+
+// blocked ชนิดที่ตรวจได้หรือ parent ของมันอยู่ใน blockedTypes
+func blocked(m *mimetype.MIME) bool {
+	for ; m != nil; m = m.Parent() {
+		for _, t := range blockedTypes {
+			if m.Is(t) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// allowed เทียบชนิดที่ตรวจได้ตรงตัวกับรายการที่อนุญาต
+// (ไม่ไล่ parent เพราะ text/html, image/svg+xml ต่างก็เป็นลูกของ text/plain)
+func allowed(m *mimetype.MIME, types []string) bool {
+	if blocked(m) {
+		return false
+	}
+	for _, t := range types {
+		if m.Is(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// ------------------------------
+// VALIDATE
+// ------------------------------
+
+// Check ตรวจไฟล์ตาม policy โดยไม่แตะ DB (จำนวน, ขนาด, ชนิดไฟล์)
+func (p Policy) Check(files []*multipart.FileHeader) error {
+	if p.MaxFiles > 0 && len(files) > p.MaxFiles {
+		return &UploadError{
+			Code:    CodeTooManyFiles,
+			Message: fmt.Sprintf("at most %d file(s) allowed", p.MaxFiles),
+			Limit:   int64(p.MaxFiles),
+		}
+	}
+
+	var total int64
+	for _, fh := range files {
+		name := fh.Filename
+		if fh.Size <= 0 {
+			return &UploadError{Code: CodeEmptyFile, Message: "file is empty", File: name}
+		}
+		if p.MaxFileSize > 0 && fh.Size > p.MaxFileSize {
+			return &UploadError{
+				Code:    CodeFileTooLarge,
+				Message: fmt.Sprintf("file exceeds %d MB", p.MaxFileSize/mb),
+				File:    name,
+				Limit:   p.MaxFileSize,
+			}
+		}
+		total += fh.Size
+
+		m, err := sniff(fh)
+		if err != nil {
+			return &UploadError{Code: CodeInvalidMultipart, Message: "cannot read file", File: name}
+		}
+		if !allowed(m, p.AllowedTypes) {
+			return &UploadError{
+				Code:    CodeUnsupportedType,
+				Message: "file type " + m.String() + " is not allowed",
+				File:    name,
+				Allowed: p.AllowedTypes,
+			}
+		}
+	}
+
+	if p.MaxRequestSize > 0 && total > p.MaxRequestSize {
+		return &UploadError{
+			Code:    CodeRequestTooLarge,
+			Message: fmt.Sprintf("total upload exceeds %d MB", p.MaxRequestSize/mb),
+			Limit:   p.MaxRequestSize,
+		}
+	}
+	return nil
+}
+
+// UsedBytes พื้นที่ที่ผู้ใช้ใช้อยู่ (ไม่นับไฟล์ที่ถูกลบแล้ว)
+func (s *service) UsedBytes(ctx context.Context, uploaderID uint) (int64, error) {
+	var used int64
+	err := s.db.WithContext(ctx).Model(&entity.FileAssets{}).
+		Where("uploader_id = ?", uploaderID).
+		Select("COALESCE(SUM(size), 0)").
+		Scan(&used).Error
+	return used, err
+}
+
+// Validate ตรวจตาม policy แล้วเช็คโควต้าผู้อัปโหลด
+// replacing = ขนาดไฟล์เดิมที่จะถูกแทนที่ (ไม่นับรวมในโควต้า)
+func (s *service) Validate(ctx context.Context, p Policy, files []*multipart.FileHeader, uploaderID uint, replacing int64) error {
+	if len(files) == 0 {
+		return nil
+	}
+	if err := p.Check(files); err != nil {
+		return err
+	}
+
+	quota := UserQuota()
+	if quota == 0 || uploaderID == 0 || s.db == nil {
+		return nil
+	}
+
+	used, err := s.UsedBytes(ctx, uploaderID)
+	if err != nil {
+		return err
+	}
+	var incoming int64
+	for _, fh := range files {
+		incoming += fh.Size
+	}
+	if used-replacing+incoming > quota {
+		return &UploadError{
+			Code:    CodeQuotaExceeded,
+			Message: fmt.Sprintf("storage quota of %d MB exceeded", quota/mb),
+			Limit:   quota,
+		}
+	}
+	return nil
+}
+
+// SizeOf ขนาดรวมของไฟล์ (ใช้คำนวณ replacing ตอนแทนที่ไฟล์เดิม)
+func SizeOf(assets []entity.FileAssets) int64 {
+	var n int64
+	for _, a := range assets {
+		n += a.Size
+	}
+	return n
+}
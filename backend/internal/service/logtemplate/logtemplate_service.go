@@ -0,0 +1,370 @@
+package logtemplate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/asaskevich/govalidator"
+	"gorm.io/gorm"
+
+	"backend/internal/app/dto"
+	"backend/internal/app/entity"
+	"backend/internal/app/queryspec"
+)
+
+// แบบฟอร์มบันทึกต่อหัวข้อนัด + คำศัพท์ tag ที่ admin ควบคุม
+type Service interface {
+	ListTemplates(ctx context.Context, topicID uint, includeInactive bool, spec queryspec.Spec) (*dto.LogTemplateListResp, error)
+	GetTemplate(ctx context.Context, id uint) (*entity.LogTemplate, error)
+	CreateTemplate(ctx context.Context, req dto.LogTemplateReq) (*entity.LogTemplate, error)
+	// UpdateTemplate แทนที่ช่องทั้งหมด (บันทึกเก่ายังเก็บค่าตาม key เดิมไว้)
+	UpdateTemplate(ctx context.Context, id uint, req dto.LogTemplateReq) (*entity.LogTemplate, error)
+	DeleteTemplate(ctx context.Context, id uint) error
+
+	ListTags(ctx context.Context, includeInactive bool, spec queryspec.Spec) (*dto.LogTagListResp, error)
+	CreateTag(ctx context.Context, req dto.LogTagReq) (*entity.LogTag, error)
+	UpdateTag(ctx context.Context, id uint, req dto.LogTagReq) (*entity.LogTag, error)
+	// DeactivateTag ปิดใช้งาน (ไม่ลบ) บันทึกที่ติด tag นี้อยู่แล้วยังนับสถิติได้
+	DeactivateTag(ctx context.Context, id uint) error
+}
+
+var (
+	ErrTemplateNotFound = errors.New("log template not found")
+	ErrTopicNotFound    = errors.New("appointment topic not found")
+	ErrTagNotFound      = errors.New("tag not found")
+	ErrDuplicateTag     = errors.New("tag name already exists")
+	ErrTemplateInactive = errors.New("log template is not active")
+	ErrTemplateTopic    = errors.New("log template does not belong to the appointment topic")
+)
+
+// UnknownTagsError tag ที่ไม่มีในคำศัพท์ (หรือถูกปิดใช้งาน)
+type UnknownTagsError struct {
+	Names []string
+}
+
+func (e *UnknownTagsError) Error() string {
+	return fmt.Sprintf("unknown tags: %s", strings.Join(e.Names, ", "))
+}
+
+type service struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) Service {
+	return &service{db: db}
+}
+
+// ------------------------------
+// helpers (ใช้ร่วมกับ advisorlog)
+// ------------------------------
+
+func preloadFields(db *gorm.DB) *gorm.DB {
+	return db.Order("position asc, id asc")
+}
+
+// LoadTemplate โหลดแบบฟอร์มที่ใช้งานอยู่พร้อมช่อง และตรวจว่าเป็นของหัวข้อนัดนี้
+func LoadTemplate(db *gorm.DB, id, topicID uint) (*entity.LogTemplate, error) {
+	var t entity.LogTemplate
+	if err := db.Preload("Fields", preloadFields).First(&t, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTemplateNotFound
+		}
+		return nil, err
+	}
+	if !t.IsActive {
+		return nil, ErrTemplateInactive
+	}
+	if t.TopicID != topicID {
+		return nil, ErrTemplateTopic
+	}
+	return &t, nil
+}
+
+// ResolveTags แปลงชื่อ tag เป็นแถวในคำศัพท์ (ต้องมีอยู่และเปิดใช้งานทุกตัว)
+func ResolveTags(db *gorm.DB, names []string) ([]entity.LogTag, error) {
+	names = NormalizeTags(names)
+	if len(names) == 0 {
+		return nil, nil
+	}
+	var tags []entity.LogTag
+	if err := db.Where("name IN ? AND is_active = ?", names, true).Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	found := map[string]bool{}
+	for _, t := range tags {
+		found[t.Name] = true
+	}
+	var missing []string
+	for _, n := range names {
+		if !found[n] {
+			missing = append(missing, n)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, &UnknownTagsError{Names: missing}
+	}
+	return tags, nil
+}
+
+// deactivateOthers หัวข้อหนึ่งใช้งานได้ทีละแบบฟอร์ม
+func deactivateOthers(tx *gorm.DB, t entity.LogTemplate) error {
+	if !t.IsActive {
+		return nil
+	}
+	return tx.Model(&entity.LogTemplate{}).
+		Where("topic_id = ? AND id <> ? AND is_active = ?", t.TopicID, t.ID, true).
+		Update("is_active", false).Error
+}
+
+func (s *service) checkTopic(ctx context.Context, topicID uint) error {
+	var topic entity.AppointmentTopic
+	if err := s.db.WithContext(ctx).First(&topic, topicID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTopicNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// ------------------------------
+// TEMPLATES
+// ------------------------------
+
+// TemplateListQuery sort/ตัวกรองที่รายการแบบฟอร์มรับได้
+var TemplateListQuery = queryspec.Options{
+	Sorts: map[string]string{
+		"id":       "id",
+		"topic_id": "topic_id",
+		"name":     "name",
+	},
+	DefaultSort: "topic_id",
+	Filters:     []string{"topic_id"},
+}
+
+// TagListQuery sort ที่รายการ tag รับได้
+var TagListQuery = queryspec.Options{
+	Sorts: map[string]string{
+		"id":    "id",
+		"name":  "name",
+		"label": "label",
+	},
+	DefaultSort: "label",
+}
+
+func (s *service) ListTemplates(ctx context.Context, topicID uint, includeInactive bool, spec queryspec.Spec) (*dto.LogTemplateListResp, error) {
+	q := s.db.WithContext(ctx).Model(&entity.LogTemplate{})
+	if topicID != 0 {
+		q = q.Where("topic_id = ?", topicID)
+	}
+	if !includeInactive {
+		q = q.Where("is_active = ?", true)
+	}
+	items, meta, err := queryspec.Find(q, spec, func(t entity.LogTemplate) uint { return t.ID }, func(db *gorm.DB) *gorm.DB {
+		return db.Preload("Topic").Preload("Fields", preloadFields)
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := dto.NewPageResp(items, meta)
+	return &out, nil
+}
+
+func (s *service) GetTemplate(ctx context.Context, id uint) (*entity.LogTemplate, error) {
+	var t entity.LogTemplate
+	if err := s.db.WithContext(ctx).Preload("Topic").Preload("Fields", preloadFields).First(&t, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTemplateNotFound
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (s *service) CreateTemplate(ctx context.Context, req dto.LogTemplateReq) (*entity.LogTemplate, error) {
+	fields, err := BuildFields(req.Fields)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTopic(ctx, req.TopicID); err != nil {
+		return nil, err
+	}
+
+	t := entity.LogTemplate{
+		TopicID:     req.TopicID,
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+		IsActive:    req.IsActive == nil || *req.IsActive,
+	}
+	if _, err := govalidator.ValidateStruct(t); err != nil {
+		return nil, err
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Fields").Create(&t).Error; err != nil {
+			return err
+		}
+		for i := range fields {
+			fields[i].TemplateID = t.ID
+		}
+		if err := tx.Create(&fields).Error; err != nil {
+			return err
+		}
+		return deactivateOthers(tx, t)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetTemplate(ctx, t.ID)
+}
+
+func (s *service) UpdateTemplate(ctx context.Context, id uint, req dto.LogTemplateReq) (*entity.LogTemplate, error) {
+	t, err := s.GetTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := BuildFields(req.Fields)
+	if err != nil {
+		return nil, err
+	}
+	if req.TopicID != t.TopicID {
+		if err := s.checkTopic(ctx, req.TopicID); err != nil {
+			return nil, err
+		}
+	}
+
+	t.TopicID = req.TopicID
+	t.Topic = nil
+	t.Name = strings.TrimSpace(req.Name)
+	t.Description = req.Description
+	if req.IsActive != nil {
+		t.IsActive = *req.IsActive
+	}
+	if _, err := govalidator.ValidateStruct(t); err != nil {
+		return nil, err
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Fields", "Topic").Save(t).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("template_id = ?", t.ID).Delete(&entity.LogTemplateField{}).Error; err != nil {
+			return err
+		}
+		for i := range fields {
+			fields[i].TemplateID = t.ID
+		}
+		if err := tx.Create(&fields).Error; err != nil {
+			return err
+		}
+		return deactivateOthers(tx, *t)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetTemplate(ctx, t.ID)
+}
+
+// DeleteTemplate soft delete (บันทึกที่อ้างถึงยังเก็บค่าไว้)
+func (s *service) DeleteTemplate(ctx context.Context, id uint) error {
+	res := s.db.WithContext(ctx).Delete(&entity.LogTemplate{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrTemplateNotFound
+	}
+	return nil
+}
+
+// ------------------------------
+// TAGS
+// ------------------------------
+
+func (s *service) ListTags(ctx context.Context, includeInactive bool, spec queryspec.Spec) (*dto.LogTagListResp, error) {
+	q := s.db.WithContext(ctx).Model(&entity.LogTag{})
+	if !includeInactive {
+		q = q.Where("is_active = ?", true)
+	}
+	items, meta, err := queryspec.Find(q, spec, func(t entity.LogTag) uint { return t.ID })
+	if err != nil {
+		return nil, err
+	}
+	out := dto.NewPageResp(items, meta)
+	return &out, nil
+}
+
+func (s *service) nameTaken(ctx context.Context, name string, exceptID uint) (bool, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Unscoped().Model(&entity.LogTag{}).
+		Where("name = ? AND id <> ?", name, exceptID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (s *service) CreateTag(ctx context.Context, req dto.LogTagReq) (*entity.LogTag, error) {
+	tag := entity.LogTag{
+		Name:        strings.ToLower(strings.TrimSpace(req.Name)),
+		Label:       strings.TrimSpace(req.Label),
+		Description: req.Description,
+		IsActive:    req.IsActive == nil || *req.IsActive,
+	}
+	if _, err := govalidator.ValidateStruct(tag); err != nil {
+		return nil, err
+	}
+	taken, err := s.nameTaken(ctx, tag.Name, 0)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, ErrDuplicateTag
+	}
+	if err := s.db.WithContext(ctx).Create(&tag).Error; err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// UpdateTag เปลี่ยน name ได้ถ้าไม่ซ้ำกับ tag อื่น (บันทึกผูกกับ id จึงไม่หลุด แต่ตัวกรองเดิมที่อ้าง name เก่าจะใช้ไม่ได้)
+func (s *service) UpdateTag(ctx context.Context, id uint, req dto.LogTagReq) (*entity.LogTag, error) {
+	var tag entity.LogTag
+	if err := s.db.WithContext(ctx).First(&tag, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTagNotFound
+		}
+		return nil, err
+	}
+	tag.Name = strings.ToLower(strings.TrimSpace(req.Name))
+	tag.Label = strings.TrimSpace(req.Label)
+	tag.Description = req.Description
+	if req.IsActive != nil {
+		tag.IsActive = *req.IsActive
+	}
+	if _, err := govalidator.ValidateStruct(tag); err != nil {
+		return nil, err
+	}
+	taken, err := s.nameTaken(ctx, tag.Name, tag.ID)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, ErrDuplicateTag
+	}
+	if err := s.db.WithContext(ctx).Save(&tag).Error; err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (s *service) DeactivateTag(ctx context.Context, id uint) error {
+	res := s.db.WithContext(ctx).Model(&entity.LogTag{}).Where("id = ?", id).Update("is_active", false)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrTagNotFound
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package logtemplate
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"backend/internal/app/dto"
+	"backend/internal/app/entity"
+)
+
+// ตรวจโครงแบบฟอร์ม / ค่าที่ผู้ใช้กรอก / ชื่อ tag (ไม่แตะ DB)
+
+var keyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// ความยาวสูงสุดของค่าข้อความต่อช่อง
+const maxValueLen = 5000
+
+var fieldTypes = map[string]bool{
+	entity.TemplateFieldText:        true,
+	entity.TemplateFieldTextarea:    true,
+	entity.TemplateFieldSelect:      true,
+	entity.TemplateFieldMultiSelect: true,
+	entity.TemplateFieldCheckbox:    true,
+}
+
+// FieldError ค่าของช่องใดช่องหนึ่งไม่ถูกต้อง
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e *FieldError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+func fieldErr(field, format string, args ...any) *FieldError {
+	return &FieldError{Field: field, Message: fmt.Sprintf(format, args...)}
+}
+
+// BuildFields ตรวจและแปลงช่องของแบบฟอร์มจาก request (ลำดับใน request = Position)
+func BuildFields(reqs []dto.LogTemplateFieldReq) ([]entity.LogTemplateField, error) {
+	if len(reqs) == 0 {
+		return nil, fieldErr("fields", "template needs at least one field")
+	}
+	seen := map[string]bool{}
+	out := make([]entity.LogTemplateField, 0, len(reqs))
+	for i, r := range reqs {
+		key := strings.TrimSpace(r.Key)
+		if !keyPattern.MatchString(key) {
+			return nil, fieldErr(fmt.Sprintf("fields[%d].key", i), "must be lowercase letters, digits or _ and start with a letter")
+		}
+		if seen[key] {
+			return nil, fieldErr(key, "duplicate key")
+		}
+		seen[key] = true
+
+		label := strings.TrimSpace(r.Label)
+		if label == "" {
+			return nil, fieldErr(key, "label is required")
+		}
+		typ := strings.ToLower(strings.TrimSpace(r.Type))
+		if typ == "" {
+			typ = entity.TemplateFieldText
+		}
+		if !fieldTypes[typ] {
+			return nil, fieldErr(key, "unknown type %q", r.Type)
+		}
+
+		var options []string
+		if typ == entity.TemplateFieldSelect || typ == entity.TemplateFieldMultiSelect {
+			optSeen := map[string]bool{}
+			for _, o := range r.Options {
+				o = strings.TrimSpace(o)
+				if o == "" || optSeen[o] {
+					continue
+				}
+				optSeen[o] = true
+				options = append(options, o)
+			}
+			if len(options) == 0 {
+				return nil, fieldErr(key, "%s needs options", typ)
+			}
+		}
+
+		out = append(out, entity.LogTemplateField{
+			Key:      key,
+			Label:    label,
+			Type:     typ,
+			Required: r.Required,
+			Options:  options,
+			Position: i,
+		})
+	}
+	return out, nil
+}
+
+func contains(list []string, v string) bool {
+	for _, x := range list {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateValues ตรวจค่าที่กรอกตามแบบฟอร์ม คืนค่าที่ normalize แล้ว (ตัดช่องว่าง, ตัดช่องที่ไม่ได้กรอก)
+// ช่องที่ไม่มีในแบบฟอร์มถือว่าผิด เพื่อไม่ให้มีข้อมูลนอกโครงสร้างที่นับสถิติไม่ได้
+func ValidateValues(t entity.LogTemplate, raw map[string]any) (map[string]any, error) {
+	fields := map[string]entity.LogTemplateField{}
+	for _, f := range t.Fields {
+		fields[f.Key] = f
+	}
+	for k := range raw {
+		if _, ok := fields[k]; !ok {
+			return nil, fieldErr(k, "not a field of this template")
+		}
+	}
+
+	out := map[string]any{}
+	for _, f := range t.Fields {
+		v, present := raw[f.Key]
+		if v == nil {
+			present = false
+		}
+
+		switch f.Type {
+		case entity.TemplateFieldCheckbox:
+			if !present {
+				if f.Required {
+					return nil, fieldErr(f.Key, "is required")
+				}
+				continue
+			}
+			b, ok := v.(bool)
+			if !ok {
+				return nil, fieldErr(f.Key, "must be true or false")
+			}
+			out[f.Key] = b
+
+		case entity.TemplateFieldMultiSelect:
+			var picked []string
+			if present {
+				list, ok := v.([]any)
+				if !ok {
+					return nil, fieldErr(f.Key, "must be a list")
+				}
+				for _, item := range list {
+					s, ok := item.(string)
+					if !ok || !contains(f.Options, strings.TrimSpace(s)) {
+						return nil, fieldErr(f.Key, "%v is not one of the options", item)
+					}
+					if !contains(picked, strings.TrimSpace(s)) {
+						picked = append(picked, strings.TrimSpace(s))
+					}
+				}
+			}
+			if len(picked) == 0 {
+				if f.Required {
+					return nil, fieldErr(f.Key, "is required")
+				}
+				continue
+			}
+			out[f.Key] = picked
+
+		default: // text, textarea, select
+			s := ""
+			if present {
+				str, ok := v.(string)
+				if !ok {
+					return nil, fieldErr(f.Key, "must be text")
+				}
+				s = strings.TrimSpace(str)
+			}
+			if s == "" {
+				if f.Required {
+					return nil, fieldErr(f.Key, "is required")
+				}
+				continue
+			}
+			if len([]rune(s)) > maxValueLen {
+				return nil, fieldErr(f.Key, "is longer than %d characters", maxValueLen)
+			}
+			if f.Type == entity.TemplateFieldSelect && !contains(f.Options, s) {
+				return nil, fieldErr(f.Key, "%q is not one of the options", s)
+			}
+			out[f.Key] = s
+		}
+	}
+	return out, nil
+}
+
+// RenderBody สร้างข้อความสรุปจากค่าที่กรอก (ใช้เป็น Body เมื่อผู้ใช้ไม่ได้เขียนเอง)
+func RenderBody(t entity.LogTemplate, values map[string]any) string {
+	var b strings.Builder
+	for _, f := range t.Fields {
+		v, ok := values[f.Key]
+		if !ok {
+			continue
+		}
+		var text string
+		switch x := v.(type) {
+		case bool:
+			text = "ไม่ใช่"
+			if x {
+				text = "ใช่"
+			}
+		case []string:
+			text = strings.Join(x, ", ")
+		case []any: // ค่าที่อ่านกลับมาจาก jsonb
+			parts := make([]string, 0, len(x))
+			for _, p := range x {
+				parts = append(parts, fmt.Sprint(p))
+			}
+			text = strings.Join(parts, ", ")
+		default:
+			text = fmt.Sprint(x)
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		if f.Type == entity.TemplateFieldTextarea {
+			b.WriteString(f.Label + ":\n" + text)
+		} else {
+			b.WriteString(f.Label + ": " + text)
+		}
+	}
+	return b.String()
+}
+
+// NormalizeTags แยก comma, ตัดช่องว่าง, ตัวพิมพ์เล็ก, ตัดค่าซ้ำ (คงลำดับเดิม)
+func NormalizeTags(raw []string) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, r := range raw {
+		for _, part := range strings.Split(r, ",") {
+			name := strings.ToLower(strings.TrimSpace(part))
+			if name == "" || seen[name] {
+				continue
+			}
+			seen[name] = true
+			out = append(out, name)
+		}
+	}
+	return out
+}
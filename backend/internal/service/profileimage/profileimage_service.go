@@ -0,0 +1,90 @@
+package profileimage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime/multipart"
+
+	"gorm.io/gorm"
+
+	"backend/internal/app/entity"
+	"backend/internal/service/fileasset"
+)
+
+// รูปโปรไฟล์ที่อัปโหลดเข้าระบบเอง (แทนการฝาก URL ภายนอก)
+// เก็บเป็น FileAssets owner = profile_image/user_id และตั้ง users.profile_image เป็น URL ของ API
+type Service interface {
+	Upload(ctx context.Context, userID uint, fh *multipart.FileHeader) (string, error)
+	Get(ctx context.Context, userID uint) (*entity.FileAssets, error)
+}
+
+var (
+	ErrNoFile        = errors.New("no image uploaded")
+	ErrImageNotFound = errors.New("profile image not found")
+)
+
+type service struct {
+	db    *gorm.DB
+	files fileasset.Service
+}
+
+func New(db *gorm.DB, files fileasset.Service) Service {
+	return &service{db: db, files: files}
+}
+
+// URL path สาธารณะของรูป (?v= เปลี่ยนทุกครั้งที่อัปโหลดใหม่ ให้ browser ไม่ใช้ cache เก่า)
+func URL(userID, assetID uint) string {
+	return fmt.Sprintf("/api/users/%d/profile-image?v=%d", userID, assetID)
+}
+
+func (s *service) Upload(ctx context.Context, userID uint, fh *multipart.FileHeader) (string, error) {
+	if fh == nil {
+		return "", ErrNoFile
+	}
+	files := []*multipart.FileHeader{fh}
+
+	current, err := s.files.ListByOwner(ctx, entity.FileOwnerProfileImage, userID)
+	if err != nil {
+		return "", err
+	}
+	if err := s.files.Validate(ctx, fileasset.PolicyProfileImage, files, userID, fileasset.SizeOf(current)); err != nil {
+		return "", err
+	}
+
+	assets, err := s.files.StoreAll(files, userID)
+	if err != nil {
+		return "", err
+	}
+
+	var old []entity.FileAssets
+	url := ""
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if old, err = s.files.Detach(tx, entity.FileOwnerProfileImage, userID); err != nil {
+			return err
+		}
+		if err := s.files.Attach(tx, assets, entity.FileOwnerProfileImage, userID); err != nil {
+			return err
+		}
+		url = URL(userID, assets[0].ID)
+		return tx.Model(&entity.User{}).Where("id = ?", userID).Update("profile_image", url).Error
+	})
+	if err != nil {
+		s.files.Discard(assets)
+		return "", err
+	}
+	s.files.Discard(old)
+	return url, nil
+}
+
+func (s *service) Get(ctx context.Context, userID uint) (*entity.FileAssets, error) {
+	assets, err := s.files.ListByOwner(ctx, entity.FileOwnerProfileImage, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(assets) == 0 {
+		return nil, ErrImageNotFound
+	}
+	return &assets[len(assets)-1], nil
+}
@@ -0,0 +1,318 @@
+package progressreport
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"backend/internal/app/dto"
+	"backend/internal/app/entity"
+	"backend/internal/service/fileasset"
+)
+
+// feedback ของรายงานเป็น thread: ตอบกลับด้วย ParentID (ต้องเป็นรายงานเดียวกัน)
+// โพสต์/ตอบได้เฉพาะนักศึกษาและอาจารย์ของนัด, admin อ่านได้อย่างเดียว
+// ผู้เขียนแก้ข้อความได้ภายใน EditWindow หลังโพสต์
+
+const DefaultEditWindow = 15 * time.Minute
+
+var (
+	ErrFeedbackNotFound = errors.New("feedback not found")
+	ErrInvalidParent    = errors.New("parent feedback does not belong to this report")
+	ErrEditWindowClosed = errors.New("feedback can no longer be edited")
+	ErrNotAuthor        = errors.New("only the author can edit this feedback")
+	ErrFileNotFound     = errors.New("file not found")
+	ErrBodyRequired     = errors.New("body is required")
+)
+
+// EditWindow ช่วงเวลาที่แก้ feedback ได้ (FEEDBACK_EDIT_WINDOW_MINUTES, ค่าเริ่มต้น 15 นาที, 0 = แก้ไม่ได้)
+func EditWindow() time.Duration {
+	if v := strings.TrimSpace(os.Getenv("FEEDBACK_EDIT_WINDOW_MINUTES")); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			return time.Duration(n) * time.Minute
+		}
+	}
+	return DefaultEditWindow
+}
+
+// CanEdit ผู้เรียกแก้ feedback นี้ได้ตอนนี้หรือไม่
+func CanEdit(fb entity.ReportFeedback, requesterID uint, now time.Time, window time.Duration) bool {
+	if fb.AuthorUserID == nil || *fb.AuthorUserID != requesterID {
+		return false
+	}
+	return now.Before(fb.CreatedAt.Add(window))
+}
+
+// isParticipant นักศึกษาหรืออาจารย์ของนัด (ตรงทั้ง id และบทบาท)
+func isParticipant(appt *entity.Appointment, requesterID uint, requesterRole string) bool {
+	if appt == nil || requesterID == 0 {
+		return false
+	}
+	switch normRole(requesterRole) {
+	case "student":
+		return appt.StudentUserID == requesterID
+	case "advisor":
+		return appt.AdvisorUserID == requesterID
+	}
+	return false
+}
+
+func canView(appt *entity.Appointment, requesterID uint, requesterRole string) bool {
+	return normRole(requesterRole) == "admin" || isParticipant(appt, requesterID, requesterRole)
+}
+
+// viewer ข้อมูลผู้ดูที่ใช้ประกอบ response ของ feedback (canEdit / บทบาทผู้เขียน)
+type viewer struct {
+	appt   *entity.Appointment
+	userID uint
+	now    time.Time
+	window time.Duration
+}
+
+func (s *service) viewer(appt *entity.Appointment, requesterID uint, requesterRole string) viewer {
+	v := viewer{appt: appt, now: s.now(), window: EditWindow()}
+	// admin อ่านอย่างเดียว จึงไม่ต้องคำนวณ canEdit ให้
+	if isParticipant(appt, requesterID, requesterRole) {
+		v.userID = requesterID
+	}
+	return v
+}
+
+func (v viewer) authorRole(authorID *uint) string {
+	if authorID == nil || v.appt == nil {
+		return ""
+	}
+	switch *authorID {
+	case v.appt.AdvisorUserID:
+		return "advisor"
+	case v.appt.StudentUserID:
+		return "student"
+	}
+	return ""
+}
+
+func (v viewer) feedback(f entity.ReportFeedback) dto.ReportFeedbackResp {
+	out := dto.ReportFeedbackResp{
+		ID:           f.ID,
+		ParentID:     f.ParentID,
+		AuthorUserID: f.AuthorUserID,
+		AuthorRole:   v.authorRole(f.AuthorUserID),
+		Body:         f.Body,
+		Outcome:      f.Outcome,
+		Files:        fileasset.ToResp(f.Files),
+		CanEdit:      v.userID != 0 && CanEdit(f, v.userID, v.now, v.window),
+		CreatedAt:    f.CreatedAt.Format("2006-01-02 15:04:05"),
+		Replies:      []dto.ReportFeedbackResp{},
+	}
+	if f.Author != nil {
+		out.AuthorName = strings.TrimSpace(f.Author.FirstName + " " + f.Author.LastName)
+	}
+	if f.EditedAt != nil {
+		e := f.EditedAt.Format("2006-01-02 15:04:05")
+		out.EditedAt = &e
+	}
+	return out
+}
+
+func (v viewer) feedbacks(fbs []entity.ReportFeedback) []dto.ReportFeedbackResp {
+	out := make([]dto.ReportFeedbackResp, 0, len(fbs))
+	for _, f := range fbs {
+		out = append(out, v.feedback(f))
+	}
+	return out
+}
+
+// BuildThreads จัด feedback แบบเรียงตามเวลาให้เป็น thread (ตอบกลับที่หา parent ไม่เจอจะขึ้นเป็นหัวข้อ)
+func BuildThreads(flat []dto.ReportFeedbackResp) []dto.ReportFeedbackResp {
+	known := make(map[uint]bool, len(flat))
+	for _, f := range flat {
+		known[f.ID] = true
+	}
+	children := map[uint][]dto.ReportFeedbackResp{}
+	var roots []dto.ReportFeedbackResp
+	for _, f := range flat {
+		if f.ParentID != nil && known[*f.ParentID] && *f.ParentID != f.ID {
+			children[*f.ParentID] = append(children[*f.ParentID], f)
+			continue
+		}
+		roots = append(roots, f)
+	}
+
+	seen := map[uint]bool{}
+	var attach func(f dto.ReportFeedbackResp) dto.ReportFeedbackResp
+	attach = func(f dto.ReportFeedbackResp) dto.ReportFeedbackResp {
+		seen[f.ID] = true
+		f.Replies = make([]dto.ReportFeedbackResp, 0, len(children[f.ID]))
+		for _, c := range children[f.ID] {
+			if !seen[c.ID] {
+				f.Replies = append(f.Replies, attach(c))
+			}
+		}
+		return f
+	}
+
+	out := make([]dto.ReportFeedbackResp, 0, len(roots))
+	for _, r := range roots {
+		out = append(out, attach(r))
+	}
+	return out
+}
+
+// loadReportContext รายงาน + บันทึก + นัด ของรายงานนี้
+func (s *service) loadReportContext(reportID uint) (*entity.ProgressReport, *entity.AdvisorLog, error) {
+	report, err := s.repo.GetReport(reportID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrReportNotFound
+		}
+		return nil, nil, err
+	}
+	log, err := s.getLog(report.AdvisorLogsID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return report, log, nil
+}
+
+func (s *service) getFeedback(id uint) (*entity.ReportFeedback, error) {
+	fb, err := s.repo.GetFeedback(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFeedbackNotFound
+		}
+		return nil, err
+	}
+	return fb, nil
+}
+
+// ------------------------------
+// ADD (🔒 นักศึกษา/อาจารย์ของนัด)
+// ------------------------------
+func (s *service) AddFeedback(ctx context.Context, req dto.ReportFeedbackCreateReq, requesterID uint, requesterRole string) (*dto.ReportFeedbackResp, error) {
+	report, log, err := s.loadReportContext(req.ProgressReportID)
+	if err != nil {
+		return nil, err
+	}
+	if !isParticipant(log.Appointment, requesterID, requesterRole) {
+		return nil, ErrForbidden
+	}
+
+	if req.ParentID != nil {
+		parent, err := s.getFeedback(*req.ParentID)
+		if err != nil {
+			if errors.Is(err, ErrFeedbackNotFound) {
+				return nil, ErrInvalidParent
+			}
+			return nil, err
+		}
+		if parent.ProgressReportsID != report.ID {
+			return nil, ErrInvalidParent
+		}
+	}
+
+	fb := entity.ReportFeedback{
+		ProgressReportsID: report.ID,
+		Body:              strings.TrimSpace(req.Body),
+		Outcome:           entity.FeedbackComment,
+		AuthorUserID:      &requesterID,
+		ParentID:          req.ParentID,
+	}
+	if fb.Body == "" {
+		return nil, ErrBodyRequired
+	}
+
+	if err := s.files.Validate(ctx, fileasset.PolicyReportFeedback, req.Files, requesterID, 0); err != nil {
+		return nil, err
+	}
+	assets, err := s.files.StoreAll(req.Files, requesterID)
+	if err != nil {
+		return nil, ErrSaveFileFailed
+	}
+
+	err = s.repo.CreateFeedback(&fb, func(tx *gorm.DB) error {
+		return s.files.Attach(tx.WithContext(ctx), assets, entity.FileOwnerReportFeedback, fb.ID)
+	})
+	if err != nil {
+		s.files.Discard(assets)
+		return nil, err
+	}
+
+	saved, err := s.getFeedback(fb.ID)
+	if err != nil {
+		return nil, err
+	}
+	saved.Files = assets
+	out := s.viewer(log.Appointment, requesterID, requesterRole).feedback(*saved)
+	return &out, nil
+}
+
+// ------------------------------
+// EDIT (🔒 ผู้เขียน ภายในช่วงเวลาที่กำหนด)
+// ------------------------------
+func (s *service) EditFeedback(ctx context.Context, feedbackID uint, req dto.ReportFeedbackUpdateReq, requesterID uint, requesterRole string) (*dto.ReportFeedbackResp, error) {
+	fb, err := s.getFeedback(feedbackID)
+	if err != nil {
+		return nil, err
+	}
+	_, log, err := s.loadReportContext(fb.ProgressReportsID)
+	if err != nil {
+		return nil, err
+	}
+	if !isParticipant(log.Appointment, requesterID, requesterRole) {
+		return nil, ErrForbidden
+	}
+	if fb.AuthorUserID == nil || *fb.AuthorUserID != requesterID {
+		return nil, ErrNotAuthor
+	}
+	now := s.now()
+	if !CanEdit(*fb, requesterID, now, EditWindow()) {
+		return nil, ErrEditWindowClosed
+	}
+
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return nil, ErrBodyRequired
+	}
+	if err := s.repo.UpdateFeedbackBody(fb.ID, body, now); err != nil {
+		return nil, err
+	}
+	fb.Body = body
+	fb.EditedAt = &now
+
+	if fb.Files, err = s.files.ListByOwner(ctx, entity.FileOwnerReportFeedback, fb.ID); err != nil {
+		return nil, err
+	}
+	out := s.viewer(log.Appointment, requesterID, requesterRole).feedback(*fb)
+	return &out, nil
+}
+
+// ------------------------------
+// FILE (🔒 คู่นัด หรือ admin)
+// ------------------------------
+func (s *service) FeedbackFile(ctx context.Context, feedbackID uint, index int, requesterID uint, requesterRole string) (*entity.FileAssets, error) {
+	fb, err := s.getFeedback(feedbackID)
+	if err != nil {
+		return nil, err
+	}
+	_, log, err := s.loadReportContext(fb.ProgressReportsID)
+	if err != nil {
+		return nil, err
+	}
+	if !canView(log.Appointment, requesterID, requesterRole) {
+		return nil, ErrForbidden
+	}
+
+	files, err := s.files.ListByOwner(ctx, entity.FileOwnerReportFeedback, fb.ID)
+	if err != nil {
+		return nil, err
+	}
+	if index < 0 || index >= len(files) {
+		return nil, ErrFileNotFound
+	}
+	return &files[index], nil
+}
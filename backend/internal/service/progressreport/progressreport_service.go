@@ -0,0 +1,377 @@
+package progressreport
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	"gorm.io/gorm"
+
+	"backend/internal/app/dto"
+	"backend/internal/app/entity"
+	"backend/internal/app/queryspec"
+	"backend/internal/app/repository"
+	"backend/internal/service/fileasset"
+	"backend/internal/service/reportescalation"
+)
+
+// รายงานความคืบหน้าที่นักศึกษาส่งตามบันทึกการปรึกษา (RequiresReport)
+// กำหนดส่งตั้งโดยอาจารย์ที่ปรึกษาของนัด เก็บไว้ที่ AdvisorLog.ReportDueAt
+type Service interface {
+	// Submit เฉพาะนักศึกษาเจ้าของนัด และบันทึกต้องอยู่ในสถานะ PendingReport
+	Submit(ctx context.Context, req dto.ProgressReportCreateReq, requesterID uint, requesterRole string) (*dto.ProgressReportResp, error)
+	// ListByLog นักศึกษาเจ้าของนัด อาจารย์ของนัด หรือ admin
+	ListByLog(ctx context.Context, logID uint, requesterID uint, requesterRole string) ([]dto.ProgressReportResp, error)
+	// Review อาจารย์ของนัดตรวจฉบับล่าสุด: approve = บันทึก Completed, request_revision = กลับไป PendingReport ให้ส่งใหม่
+	Review(ctx context.Context, reportID uint, req dto.ReportReviewReq, requesterID uint, requesterRole string) (*dto.ProgressReportResp, error)
+
+	// feedback แบบ thread (ดู feedback.go) เฉพาะนักศึกษาและอาจารย์ของนัด
+	AddFeedback(ctx context.Context, req dto.ReportFeedbackCreateReq, requesterID uint, requesterRole string) (*dto.ReportFeedbackResp, error)
+	EditFeedback(ctx context.Context, feedbackID uint, req dto.ReportFeedbackUpdateReq, requesterID uint, requesterRole string) (*dto.ReportFeedbackResp, error)
+	// FeedbackFile ไฟล์แนบลำดับที่ index ของ feedback (คู่นัด หรือ admin)
+	FeedbackFile(ctx context.Context, feedbackID uint, index int, requesterID uint, requesterRole string) (*entity.FileAssets, error)
+
+	// SetDueDate อาจารย์ที่ปรึกษาของนัดเท่านั้น (dueAt = nil ยกเลิกกำหนดส่ง)
+	SetDueDate(ctx context.Context, logID uint, dueAt *time.Time, requesterID uint, requesterRole string) (*dto.ReportDueResp, error)
+	// ListOverdue รายงานที่เลยกำหนดของนัดที่ผู้เรียกเป็นอาจารย์ ทีละหน้า (ค่าเริ่มต้นค้างนานสุดก่อน)
+	ListOverdue(ctx context.Context, spec queryspec.Spec, requesterID uint, requesterRole string) (*dto.OverdueReportListResp, error)
+}
+
+var (
+	ErrLogNotFound     = errors.New("advisor log not found")
+	ErrForbidden       = errors.New("forbidden")
+	ErrNotPending      = repository.ErrLogNotPending
+	ErrReportNotFound  = errors.New("progress report not found")
+	ErrAlreadyReviewed = repository.ErrReportReviewed
+	ErrInvalidDecision = errors.New("decision must be approve or request_revision")
+	ErrCommentRequired = errors.New("comment is required when requesting a revision")
+	ErrReportNotNeeded = errors.New("this advisor log does not require a report")
+	ErrDueInPast       = errors.New("due date must be in the future")
+	ErrInvalidDueDate  = errors.New("invalid due date (YYYY-MM-DD or RFC3339)")
+	ErrSaveFileFailed  = errors.New("save file failed")
+)
+
+type service struct {
+	repo  repository.ProgressReportRepository
+	files fileasset.Service
+	now   func() time.Time
+}
+
+func New(repo repository.ProgressReportRepository, files fileasset.Service) Service {
+	return &service{repo: repo, files: files, now: time.Now}
+}
+
+func bangkok() *time.Location {
+	loc, err := time.LoadLocation("Asia/Bangkok")
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+func normRole(role string) string {
+	return strings.ToLower(strings.TrimSpace(role))
+}
+
+// ParseDue อ่านกำหนดส่ง: YYYY-MM-DD = สิ้นวันนั้นตามเวลาไทย, หรือเวลาเต็มแบบ RFC3339
+func ParseDue(v string) (time.Time, error) {
+	v = strings.TrimSpace(v)
+	if day, err := time.ParseInLocation("2006-01-02", v, bangkok()); err == nil {
+		return day.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, ErrInvalidDueDate
+	}
+	return t, nil
+}
+
+// OverdueDays จำนวนวันเต็มที่เลยกำหนด (ยังไม่ครบวันนับเป็น 0)
+func OverdueDays(due, now time.Time) int {
+	if !now.After(due) {
+		return 0
+	}
+	return int(now.Sub(due) / (24 * time.Hour))
+}
+
+// Decide สถานะรายงานและสถานะบันทึกหลังตรวจ
+func Decide(decision, comment string) (reportStatus, logStatus string, err error) {
+	switch decision {
+	case entity.FeedbackApprove:
+		return entity.ProgressReportApproved, "Completed", nil
+	case entity.FeedbackRequestRevision:
+		if strings.TrimSpace(comment) == "" {
+			return "", "", ErrCommentRequired
+		}
+		return entity.ProgressReportRevisionRequested, "PendingReport", nil
+	}
+	return "", "", ErrInvalidDecision
+}
+
+func (s *service) getLog(id uint) (*entity.AdvisorLog, error) {
+	log, err := s.repo.GetLog(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLogNotFound
+		}
+		return nil, err
+	}
+	if log.Appointment == nil {
+		return nil, ErrLogNotFound
+	}
+	return log, nil
+}
+
+func toResp(r entity.ProgressReport, v viewer) dto.ProgressReportResp {
+	out := dto.ProgressReportResp{
+		ID:           r.ID,
+		AdvisorLogID: r.AdvisorLogsID,
+		Body:         r.Body,
+		Status:       r.Status,
+		Version:      r.Version,
+		PreviousID:   r.PreviousID,
+		SubmittedAt:  r.SubmittedAt.Format("2006-01-02 15:04:05"),
+		ReviewedByID: r.ReviewedByID,
+		Files:        fileasset.ToResp(r.Files),
+		Feedbacks:    BuildThreads(v.feedbacks(r.Feedbacks)),
+	}
+	if r.ReviewedAt != nil {
+		v := r.ReviewedAt.Format("2006-01-02 15:04:05")
+		out.ReviewedAt = &v
+	}
+	return out
+}
+
+// ------------------------------
+// SUBMIT (🔒 นักศึกษาเจ้าของนัด)
+// ------------------------------
+func (s *service) Submit(ctx context.Context, req dto.ProgressReportCreateReq, requesterID uint, requesterRole string) (*dto.ProgressReportResp, error) {
+	log, err := s.getLog(req.AdvisorLogID)
+	if err != nil {
+		return nil, err
+	}
+	if normRole(requesterRole) != "student" || log.Appointment.StudentUserID != requesterID {
+		return nil, ErrForbidden
+	}
+	if log.Status != "PendingReport" {
+		return nil, ErrNotPending
+	}
+
+	report := entity.ProgressReport{
+		AdvisorLogsID: log.ID,
+		Body:          strings.TrimSpace(req.Body),
+		Status:        entity.ProgressReportSubmitted,
+		SubmittedAt:   s.now(),
+	}
+	if _, err := govalidator.ValidateStruct(report); err != nil {
+		return nil, err
+	}
+
+	if err := s.files.Validate(ctx, fileasset.PolicyProgressReport, req.Files, requesterID, 0); err != nil {
+		return nil, err
+	}
+	assets, err := s.files.StoreAll(req.Files, requesterID)
+	if err != nil {
+		return nil, ErrSaveFileFailed
+	}
+
+	err = s.repo.Submit(&report, func(tx *gorm.DB) error {
+		return s.files.Attach(tx.WithContext(ctx), assets, entity.FileOwnerProgressReport, report.ID)
+	})
+	if err != nil {
+		s.files.Discard(assets)
+		return nil, err
+	}
+	report.Files = assets
+
+	out := toResp(report, s.viewer(log.Appointment, requesterID, requesterRole))
+	return &out, nil
+}
+
+// ------------------------------
+// LIST BY LOG (🔒 คู่นัด หรือ admin)
+// ------------------------------
+func (s *service) ListByLog(ctx context.Context, logID uint, requesterID uint, requesterRole string) ([]dto.ProgressReportResp, error) {
+	log, err := s.getLog(logID)
+	if err != nil {
+		return nil, err
+	}
+	switch normRole(requesterRole) {
+	case "student":
+		// นักศึกษาไม่เห็นบันทึกที่ยังเป็น Draft
+		if log.Appointment.StudentUserID != requesterID || log.Status == "Draft" {
+			return nil, ErrForbidden
+		}
+	case "advisor":
+		if log.Appointment.AdvisorUserID != requesterID {
+			return nil, ErrForbidden
+		}
+	case "admin":
+	default:
+		return nil, ErrForbidden
+	}
+
+	reports, err := s.repo.ListByLog(log.ID)
+	if err != nil {
+		return nil, err
+	}
+	v := s.viewer(log.Appointment, requesterID, requesterRole)
+	out := make([]dto.ProgressReportResp, 0, len(reports))
+	for _, r := range reports {
+		out = append(out, toResp(r, v))
+	}
+	return out, nil
+}
+
+// ------------------------------
+// REVIEW (🔒 อาจารย์ของนัด)
+// ------------------------------
+func (s *service) Review(ctx context.Context, reportID uint, req dto.ReportReviewReq, requesterID uint, requesterRole string) (*dto.ProgressReportResp, error) {
+	report, err := s.repo.GetReport(reportID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReportNotFound
+		}
+		return nil, err
+	}
+	log, err := s.getLog(report.AdvisorLogsID)
+	if err != nil {
+		return nil, err
+	}
+	if normRole(requesterRole) != "advisor" || log.Appointment.AdvisorUserID != requesterID {
+		return nil, ErrForbidden
+	}
+	if report.Status != entity.ProgressReportSubmitted {
+		return nil, ErrAlreadyReviewed
+	}
+
+	reportStatus, logStatus, err := Decide(req.Decision, req.Comment)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	report.Status = reportStatus
+	report.ReviewedAt = &now
+	report.ReviewedByID = &requesterID
+
+	// อนุมัติไม่จำเป็นต้องมีความเห็น ส่วนขอแก้ต้องบอกว่าให้แก้อะไร
+	var feedback *entity.ReportFeedback
+	if comment := strings.TrimSpace(req.Comment); comment != "" {
+		feedback = &entity.ReportFeedback{
+			ProgressReportsID: report.ID,
+			Body:              comment,
+			Outcome:           req.Decision,
+			AuthorUserID:      &requesterID,
+		}
+	}
+
+	if err := s.repo.Review(report, feedback, logStatus); err != nil {
+		return nil, err
+	}
+
+	reports, err := s.repo.ListByLog(log.ID)
+	if err != nil {
+		return nil, err
+	}
+	v := s.viewer(log.Appointment, requesterID, requesterRole)
+	for _, r := range reports {
+		if r.ID == report.ID {
+			out := toResp(r, v)
+			return &out, nil
+		}
+	}
+	out := toResp(*report, v)
+	return &out, nil
+}
+
+// ------------------------------
+// DUE DATE (🔒 อาจารย์ของนัด)
+// ------------------------------
+func (s *service) SetDueDate(ctx context.Context, logID uint, dueAt *time.Time, requesterID uint, requesterRole string) (*dto.ReportDueResp, error) {
+	log, err := s.getLog(logID)
+	if err != nil {
+		return nil, err
+	}
+	if normRole(requesterRole) != "advisor" || log.Appointment.AdvisorUserID != requesterID {
+		return nil, ErrForbidden
+	}
+	if !log.RequiresReport {
+		return nil, ErrReportNotNeeded
+	}
+	if dueAt != nil && !dueAt.After(s.now()) {
+		return nil, ErrDueInPast
+	}
+
+	if err := s.repo.SetDueDate(log.ID, dueAt); err != nil {
+		return nil, err
+	}
+
+	out := &dto.ReportDueResp{AdvisorLogID: log.ID}
+	if dueAt != nil {
+		v := dueAt.In(bangkok()).Format(time.RFC3339)
+		out.DueAt = &v
+	}
+	return out, nil
+}
+
+func latestStep(rows []entity.ReportEscalation, logID uint, due time.Time) string {
+	step := ""
+	for _, r := range rows {
+		if r.AdvisorLogID != logID || !r.DueAt.Equal(due) {
+			continue
+		}
+		if reportescalation.StepRank(r.Step) > reportescalation.StepRank(step) {
+			step = r.Step
+		}
+	}
+	return step
+}
+
+// ------------------------------
+// OVERDUE (🔒 อาจารย์ เห็นเฉพาะนัดของตัวเอง)
+// ------------------------------
+func (s *service) ListOverdue(ctx context.Context, spec queryspec.Spec, requesterID uint, requesterRole string) (*dto.OverdueReportListResp, error) {
+	if normRole(requesterRole) != "advisor" {
+		return nil, ErrForbidden
+	}
+	now := s.now()
+	logs, meta, err := s.repo.ListOverdueByAdvisor(requesterID, now, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, 0, len(logs))
+	for _, l := range logs {
+		ids = append(ids, l.ID)
+	}
+	escalations, err := s.repo.ListEscalations(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := bangkok()
+	out := make([]dto.OverdueReportResp, 0, len(logs))
+	for _, l := range logs {
+		if l.ReportDueAt == nil || l.Appointment == nil {
+			continue
+		}
+		student := l.Appointment.StudentUser
+		out = append(out, dto.OverdueReportResp{
+			AdvisorLogID:  l.ID,
+			AppointmentID: l.AppointmentID,
+			Title:         l.Title,
+			StudentUserID: l.Appointment.StudentUserID,
+			StudentSutID:  student.SutId,
+			StudentName:   strings.TrimSpace(student.FirstName + " " + student.LastName),
+			DueAt:         l.ReportDueAt.In(loc).Format(time.RFC3339),
+			OverdueDays:   OverdueDays(*l.ReportDueAt, now),
+			// นับเฉพาะขั้นของกำหนดส่งปัจจุบัน (เลื่อนกำหนดแล้วเริ่มใหม่)
+			EscalationStep: latestStep(escalations, l.ID, *l.ReportDueAt),
+		})
+	}
+	page := dto.NewPageResp(out, meta)
+	return &page, nil
+}
@@ -0,0 +1,297 @@
+package reportescalation
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"backend/internal/app/entity"
+)
+
+// แจ้งเตือนรายงานความคืบหน้าที่เลยกำหนด (บันทึกสถานะ PendingReport ที่ ReportDueAt ผ่านไปแล้ว)
+//
+//	ขั้น student: เลยกำหนดทันที           -> แจ้งนักศึกษา
+//	ขั้น advisor: เลยกำหนด AdvisorAfter    -> แจ้งอาจารย์ที่ปรึกษาของนัด
+//	ขั้น admin:   เลยกำหนด AdminAfter      -> แจ้ง admin ของหน่วยงานนักศึกษา (ไม่มี = admin ส่วนกลาง)
+//
+// แต่ละขั้นบันทึกใน report_escalations จึงไม่แจ้งซ้ำ
+
+const (
+	DefaultAdvisorAfter = 3 * 24 * time.Hour
+	DefaultAdminAfter   = 7 * 24 * time.Hour
+	DefaultInterval     = time.Hour
+)
+
+type Thresholds struct {
+	AdvisorAfter time.Duration
+	AdminAfter   time.Duration
+}
+
+// ThresholdsFromEnv REPORT_ESCALATE_ADVISOR_DAYS / REPORT_ESCALATE_ADMIN_DAYS (ค่าเริ่มต้น 3 และ 7 วัน)
+func ThresholdsFromEnv() Thresholds {
+	return Thresholds{
+		AdvisorAfter: daysFromEnv("REPORT_ESCALATE_ADVISOR_DAYS", DefaultAdvisorAfter),
+		AdminAfter:   daysFromEnv("REPORT_ESCALATE_ADMIN_DAYS", DefaultAdminAfter),
+	}
+}
+
+func daysFromEnv(key string, def time.Duration) time.Duration {
+	if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			return time.Duration(n) * 24 * time.Hour
+		}
+	}
+	return def
+}
+
+// IntervalFromEnv รอบการรันใน server (REPORT_ESCALATION_INTERVAL เช่น 30m, 0 = ปิด)
+func IntervalFromEnv() time.Duration {
+	if v := strings.TrimSpace(os.Getenv("REPORT_ESCALATION_INTERVAL")); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			return d
+		}
+	}
+	return DefaultInterval
+}
+
+// DueSteps ขั้นที่ถึงเวลาแจ้งแล้ว ณ now (เรียงตามลำดับ)
+func DueSteps(due, now time.Time, th Thresholds) []string {
+	if !now.After(due) {
+		return nil
+	}
+	steps := []string{entity.EscalationStudent}
+	if !now.Before(due.Add(th.AdvisorAfter)) {
+		steps = append(steps, entity.EscalationAdvisor)
+	}
+	if !now.Before(due.Add(th.AdminAfter)) {
+		steps = append(steps, entity.EscalationAdmin)
+	}
+	return steps
+}
+
+// StepRank ลำดับของขั้น (ไม่รู้จัก = 0)
+func StepRank(step string) int {
+	switch step {
+	case entity.EscalationStudent:
+		return 1
+	case entity.EscalationAdvisor:
+		return 2
+	case entity.EscalationAdmin:
+		return 3
+	}
+	return 0
+}
+
+type Options struct {
+	Thresholds Thresholds
+	DryRun     bool      // true = รายงานว่าจะแจ้งอะไร ไม่เขียนฐานข้อมูล
+	Now        time.Time // ค่าว่าง = time.Now()
+}
+
+type Escalation struct {
+	AdvisorLogID uint      `json:"advisor_log_id"`
+	DueAt        time.Time `json:"due_at"`
+	Step         string    `json:"step"`
+	Recipients   []uint    `json:"recipients"`
+}
+
+type Result struct {
+	DryRun      bool         `json:"dry_run"`
+	StartedAt   time.Time    `json:"started_at"`
+	Checked     int          `json:"checked"`
+	Escalations []Escalation `json:"escalations"`
+	Errors      []string     `json:"errors"`
+}
+
+type Service interface {
+	Run(ctx context.Context, opt Options) (*Result, error)
+}
+
+type service struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) Service {
+	return &service{db: db}
+}
+
+// Schedule รัน Run ทุก interval จนกว่า ctx จะถูกยกเลิก (interval <= 0 = ไม่รัน)
+func Schedule(ctx context.Context, svc Service, interval time.Duration, th Thresholds) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			res, err := svc.Run(ctx, Options{Thresholds: th})
+			if err != nil {
+				log.Printf("report escalation: %v", err)
+			} else if len(res.Escalations) > 0 || len(res.Errors) > 0 {
+				log.Printf("report escalation: checked=%d escalated=%d errors=%d", res.Checked, len(res.Escalations), len(res.Errors))
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func fullName(u entity.User) string {
+	return strings.TrimSpace(u.FirstName + " " + u.LastName)
+}
+
+func (s *service) Run(ctx context.Context, opt Options) (*Result, error) {
+	now := opt.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	res := &Result{DryRun: opt.DryRun, StartedAt: now, Escalations: []Escalation{}, Errors: []string{}}
+
+	var logs []entity.AdvisorLog
+	err := s.db.WithContext(ctx).
+		Preload("Appointment.StudentUser").
+		Preload("Appointment.AdvisorUser").
+		Where("status = ? AND report_due_at IS NOT NULL AND report_due_at < ?", "PendingReport", now).
+		Order("report_due_at asc, id asc").
+		Find(&logs).Error
+	if err != nil {
+		return nil, err
+	}
+	res.Checked = len(logs)
+
+	loc, err := time.LoadLocation("Asia/Bangkok")
+	if err != nil {
+		loc = time.Local
+	}
+
+	for _, l := range logs {
+		if l.Appointment == nil || l.ReportDueAt == nil {
+			continue
+		}
+		due := *l.ReportDueAt
+		for _, step := range DueSteps(due, now, opt.Thresholds) {
+			recipients, err := s.recipients(ctx, *l.Appointment, step)
+			if err != nil {
+				res.Errors = append(res.Errors, fmt.Sprintf("log %d %s: %v", l.ID, step, err))
+				continue
+			}
+
+			sent, err := s.escalate(ctx, l, due, step, recipients, now, loc, opt.DryRun)
+			if err != nil {
+				res.Errors = append(res.Errors, fmt.Sprintf("log %d %s: %v", l.ID, step, err))
+				continue
+			}
+			if sent {
+				res.Escalations = append(res.Escalations, Escalation{
+					AdvisorLogID: l.ID,
+					DueAt:        due,
+					Step:         step,
+					Recipients:   recipients,
+				})
+			}
+		}
+	}
+	return res, nil
+}
+
+// recipients ผู้รับของแต่ละขั้น
+func (s *service) recipients(ctx context.Context, appt entity.Appointment, step string) ([]uint, error) {
+	switch step {
+	case entity.EscalationStudent:
+		return []uint{appt.StudentUserID}, nil
+	case entity.EscalationAdvisor:
+		return []uint{appt.AdvisorUserID}, nil
+	}
+
+	// admin ของหน่วยงานนักศึกษา ถ้าไม่มีให้ admin ส่วนกลาง (ไม่สังกัดหน่วยงาน)
+	admins := func(q *gorm.DB) ([]uint, error) {
+		var ids []uint
+		err := q.Model(&entity.User{}).
+			Joins("JOIN roles ON roles.id = users.role_id").
+			Where("LOWER(roles.role) = ? AND users.active = ?", "admin", true).
+			Order("users.id asc").
+			Pluck("users.id", &ids).Error
+		return ids, err
+	}
+	if dept := appt.StudentUser.DepartmentID; dept != nil {
+		ids, err := admins(s.db.WithContext(ctx).Where("users.department_id = ?", *dept))
+		if err != nil || len(ids) > 0 {
+			return ids, err
+		}
+	}
+	return admins(s.db.WithContext(ctx).Where("users.department_id IS NULL"))
+}
+
+func message(l entity.AdvisorLog, step string, due, now time.Time, loc *time.Location) string {
+	appt := l.Appointment
+	dueText := due.In(loc).Format("02/01/2006 15:04")
+	days := int(now.Sub(due) / (24 * time.Hour))
+	switch step {
+	case entity.EscalationStudent:
+		return fmt.Sprintf("รายงานความคืบหน้า \"%s\" เลยกำหนดส่ง (%s) กรุณาส่งรายงานโดยเร็ว", l.Title, dueText)
+	case entity.EscalationAdvisor:
+		return fmt.Sprintf("%s ยังไม่ส่งรายงานความคืบหน้า \"%s\" เลยกำหนด (%s) มาแล้ว %d วัน",
+			fullName(appt.StudentUser), l.Title, dueText, days)
+	}
+	return fmt.Sprintf("%s ยังไม่ส่งรายงานความคืบหน้า \"%s\" ให้ %s เลยกำหนด (%s) มาแล้ว %d วัน",
+		fullName(appt.StudentUser), l.Title, fullName(appt.AdvisorUser), dueText, days)
+}
+
+// escalate บันทึกขั้นและสร้าง notification ใน transaction เดียว (คืน false ถ้าขั้นนี้เคยแจ้งแล้ว)
+func (s *service) escalate(ctx context.Context, l entity.AdvisorLog, due time.Time, step string, recipients []uint, now time.Time, loc *time.Location, dryRun bool) (bool, error) {
+	if dryRun {
+		var count int64
+		err := s.db.WithContext(ctx).Model(&entity.ReportEscalation{}).
+			Where("advisor_log_id = ? AND due_at = ? AND step = ?", l.ID, due, step).
+			Count(&count).Error
+		return count == 0, err
+	}
+
+	sent := false
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		row := entity.ReportEscalation{
+			AdvisorLogID: l.ID,
+			DueAt:        due,
+			Step:         step,
+			Recipients:   len(recipients),
+		}
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&row)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil // job อื่นแจ้งขั้นนี้ไปแล้ว
+		}
+		sent = true
+
+		if len(recipients) == 0 {
+			return nil
+		}
+		msg := message(l, step, due, now, loc)
+		notes := make([]entity.Notification, 0, len(recipients))
+		for _, uid := range recipients {
+			notes = append(notes, entity.Notification{
+				AppointmentID:   l.AppointmentID,
+				RecipientUserID: uid,
+				// ผู้ส่งคืออาจารย์ที่ขอรายงาน (notification ต้องมีผู้ส่งเสมอ)
+				SenderUserID: l.Appointment.AdvisorUserID,
+				EventType:    entity.EventReportOverdue,
+				Topic:        "รายงานความคืบหน้าเลยกำหนดส่ง",
+				Message:      msg,
+				SentAt:       &now,
+			})
+		}
+		return tx.Omit(clause.Associations).Create(&notes).Error
+	})
+	return sent, err
+}
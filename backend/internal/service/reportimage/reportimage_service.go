@@ -0,0 +1,153 @@
+package reportimage
+
+import (
+	"context"
+	"errors"
+	"mime/multipart"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"backend/internal/app/dto"
+	"backend/internal/app/entity"
+	"backend/internal/app/queryspec"
+	"backend/internal/service/fileasset"
+)
+
+// รูปประกอบการแจ้งปัญหา (Report) เก็บเป็น FileAssets + ReportImage 1 แถวต่อรูป
+type Service interface {
+	Upload(ctx context.Context, reportID uint, files []*multipart.FileHeader, requesterID uint, requesterRole string) ([]entity.ReportImage, error)
+	// List รูปของรายงานทีละหน้า (ดู ListQuery)
+	List(ctx context.Context, reportID uint, spec queryspec.Spec, requesterID uint, requesterRole string) ([]entity.ReportImage, dto.PageMeta, error)
+	Get(ctx context.Context, reportID, imageID uint, requesterID uint, requesterRole string) (*entity.FileAssets, error)
+}
+
+var (
+	ErrReportNotFound = errors.New("report not found")
+	ErrImageNotFound  = errors.New("image not found")
+	ErrForbidden      = errors.New("forbidden")
+	ErrNoFiles        = errors.New("no files uploaded")
+)
+
+// ListQuery sort ที่รายการรูปรับได้ (ค่าเริ่มต้นตามลำดับที่อัปโหลด)
+var ListQuery = queryspec.Options{
+	Sorts:       map[string]string{"id": "id"},
+	DefaultSort: "id",
+}
+
+type service struct {
+	db    *gorm.DB
+	files fileasset.Service
+}
+
+func New(db *gorm.DB, files fileasset.Service) Service {
+	return &service{db: db, files: files}
+}
+
+// ------------------------------
+// helpers
+// ------------------------------
+
+// authorize ผู้แจ้งปัญหาหรือแอดมินเท่านั้น
+func (s *service) authorize(ctx context.Context, reportID, requesterID uint, requesterRole string) error {
+	var report entity.Report
+	if err := s.db.WithContext(ctx).First(&report, reportID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrReportNotFound
+		}
+		return err
+	}
+	if strings.EqualFold(requesterRole, "admin") || report.ReportByID == requesterID {
+		return nil
+	}
+	return ErrForbidden
+}
+
+// ------------------------------
+// UPLOAD
+// ------------------------------
+
+func (s *service) Upload(ctx context.Context, reportID uint, files []*multipart.FileHeader, requesterID uint, requesterRole string) ([]entity.ReportImage, error) {
+	if err := s.authorize(ctx, reportID, requesterID, requesterRole); err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, ErrNoFiles
+	}
+
+	// นับรูปเดิมด้วย ไม่ให้อัปโหลดทีละรูปจนเกินจำนวนที่กำหนด
+	var existing int64
+	if err := s.db.WithContext(ctx).Model(&entity.ReportImage{}).Where("report_id = ?", reportID).Count(&existing).Error; err != nil {
+		return nil, err
+	}
+	policy := fileasset.PolicyReportImage
+	if max := policy.MaxFiles - int(existing); len(files) > max {
+		return nil, &fileasset.UploadError{
+			Code:    fileasset.CodeTooManyFiles,
+			Message: "report already has the maximum number of images",
+			Limit:   int64(policy.MaxFiles),
+		}
+	}
+	if err := s.files.Validate(ctx, policy, files, requesterID, 0); err != nil {
+		return nil, err
+	}
+
+	assets, err := s.files.StoreAll(files, requesterID)
+	if err != nil {
+		return nil, err
+	}
+
+	images := make([]entity.ReportImage, 0, len(assets))
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.files.Attach(tx, assets, entity.FileOwnerReport, reportID); err != nil {
+			return err
+		}
+		for i := range assets {
+			images = append(images, entity.ReportImage{
+				FileAssetsID: assets[i].ID,
+				FileAssets:   &assets[i],
+				ReportID:     reportID,
+			})
+		}
+		return tx.Omit("FileAssets", "Report").Create(&images).Error
+	})
+	if err != nil {
+		s.files.Discard(assets)
+		return nil, err
+	}
+	return images, nil
+}
+
+// ------------------------------
+// READ
+// ------------------------------
+
+func (s *service) List(ctx context.Context, reportID uint, spec queryspec.Spec, requesterID uint, requesterRole string) ([]entity.ReportImage, dto.PageMeta, error) {
+	if err := s.authorize(ctx, reportID, requesterID, requesterRole); err != nil {
+		return nil, dto.PageMeta{}, err
+	}
+	q := s.db.WithContext(ctx).Model(&entity.ReportImage{}).Where("report_id = ?", reportID)
+	return queryspec.Find(q, spec, func(img entity.ReportImage) uint { return img.ID }, func(db *gorm.DB) *gorm.DB {
+		return db.Preload("FileAssets")
+	})
+}
+
+func (s *service) Get(ctx context.Context, reportID, imageID uint, requesterID uint, requesterRole string) (*entity.FileAssets, error) {
+	if err := s.authorize(ctx, reportID, requesterID, requesterRole); err != nil {
+		return nil, err
+	}
+	var image entity.ReportImage
+	if err := s.db.WithContext(ctx).
+		Preload("FileAssets").
+		Where("id = ? AND report_id = ?", imageID, reportID).
+		First(&image).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrImageNotFound
+		}
+		return nil, err
+	}
+	if image.FileAssets == nil {
+		return nil, ErrImageNotFound
+	}
+	return image.FileAssets, nil
+}
@@ -0,0 +1,224 @@
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// รองรับ RRULE (RFC 5545) เท่าที่ปฏิทินการศึกษาใช้จริง:
+// FREQ=DAILY|WEEKLY|MONTHLY|YEARLY, INTERVAL, COUNT, UNTIL และ BYDAY (เฉพาะ WEEKLY)
+
+const (
+	FreqDaily   = "DAILY"
+	FreqWeekly  = "WEEKLY"
+	FreqMonthly = "MONTHLY"
+	FreqYearly  = "YEARLY"
+)
+
+// กันลูปไม่รู้จบกรณี rule ไม่มี COUNT/UNTIL
+const maxIterations = 10000
+
+var (
+	ErrInvalidRule     = errors.New("invalid RRULE")
+	ErrUnsupportedRule = errors.New("unsupported RRULE (FREQ=DAILY|WEEKLY|MONTHLY|YEARLY, INTERVAL, COUNT, UNTIL, BYDAY)")
+)
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+type Rule struct {
+	Freq     string
+	Interval int
+	Count    int
+	Until    *time.Time
+	ByDay    []time.Weekday
+}
+
+func bangkok() *time.Location {
+	loc, err := time.LoadLocation("Asia/Bangkok")
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+func parseUntil(v string) (time.Time, error) {
+	loc := bangkok()
+	switch {
+	case len(v) == 8:
+		// UNTIL แบบวันที่ = รวมทั้งวันนั้น
+		d, err := time.ParseInLocation("20060102", v, loc)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return d.Add(24*time.Hour - time.Second), nil
+	case strings.HasSuffix(v, "Z"):
+		return time.Parse("20060102T150405Z", v)
+	default:
+		return time.ParseInLocation("20060102T150405", v, loc)
+	}
+}
+
+// Parse อ่าน RRULE (รับได้ทั้งแบบมีและไม่มี "RRULE:" นำหน้า)
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "RRULE:"), "rrule:")
+	if s == "" {
+		return nil, ErrInvalidRule
+	}
+
+	r := &Rule{Interval: 1}
+	for _, part := range strings.Split(s, ";") {
+		if part == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidRule, part)
+		}
+		k, v = strings.ToUpper(strings.TrimSpace(k)), strings.ToUpper(strings.TrimSpace(v))
+
+		switch k {
+		case "FREQ":
+			switch v {
+			case FreqDaily, FreqWeekly, FreqMonthly, FreqYearly:
+				r.Freq = v
+			default:
+				return nil, ErrUnsupportedRule
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("%w: INTERVAL", ErrInvalidRule)
+			}
+			r.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("%w: COUNT", ErrInvalidRule)
+			}
+			r.Count = n
+		case "UNTIL":
+			t, err := parseUntil(v)
+			if err != nil {
+				return nil, fmt.Errorf("%w: UNTIL", ErrInvalidRule)
+			}
+			r.Until = &t
+		case "BYDAY":
+			for _, d := range strings.Split(v, ",") {
+				wd, ok := weekdays[d]
+				if !ok {
+					// ไม่รองรับแบบมีลำดับ เช่น 1MO, -1FR
+					return nil, ErrUnsupportedRule
+				}
+				r.ByDay = append(r.ByDay, wd)
+			}
+		case "WKST":
+			// ใช้สัปดาห์เริ่มวันจันทร์เสมอ
+		default:
+			return nil, ErrUnsupportedRule
+		}
+	}
+
+	if r.Freq == "" {
+		return nil, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	}
+	if r.Count > 0 && r.Until != nil {
+		return nil, fmt.Errorf("%w: COUNT and UNTIL must not be used together", ErrInvalidRule)
+	}
+	if len(r.ByDay) > 0 && r.Freq != FreqWeekly {
+		return nil, ErrUnsupportedRule
+	}
+	return r, nil
+}
+
+// Normalize ตรวจ RRULE แล้วคืนค่าแบบตัดช่องว่าง/ตัวพิมพ์ใหญ่ (ค่าว่าง = ไม่ใช่ event ซ้ำ)
+func Normalize(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", nil
+	}
+	if _, err := Parse(s); err != nil {
+		return "", err
+	}
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "RRULE:"), "rrule:")
+	return strings.ToUpper(strings.ReplaceAll(s, " ", "")), nil
+}
+
+// candidates คืนวันเริ่มของรอบที่ i (อาจมีหลายวันใน 1 รอบสำหรับ WEEKLY+BYDAY)
+func (r *Rule) candidates(dtstart time.Time, i int) []time.Time {
+	step := i * r.Interval
+	switch r.Freq {
+	case FreqDaily:
+		return []time.Time{dtstart.AddDate(0, 0, step)}
+
+	case FreqWeekly:
+		if len(r.ByDay) == 0 {
+			return []time.Time{dtstart.AddDate(0, 0, 7*step)}
+		}
+		// สัปดาห์เริ่มวันจันทร์
+		offset := (int(dtstart.Weekday()) + 6) % 7
+		monday := dtstart.AddDate(0, 0, -offset+7*step)
+
+		out := make([]time.Time, 0, len(r.ByDay))
+		for _, wd := range r.ByDay {
+			out = append(out, monday.AddDate(0, 0, (int(wd)+6)%7))
+		}
+		sort.Slice(out, func(a, b int) bool { return out[a].Before(out[b]) })
+		return out
+
+	case FreqMonthly, FreqYearly:
+		months := step
+		if r.Freq == FreqYearly {
+			months = 12 * step
+		}
+		y, m, d := dtstart.Date()
+		first := time.Date(y, m, 1, dtstart.Hour(), dtstart.Minute(), dtstart.Second(), 0, dtstart.Location()).AddDate(0, months, 0)
+		// เดือนที่ไม่มีวันนั้น (เช่น 31, 29 ก.พ.) ให้ข้ามตาม RFC 5545
+		t := first.AddDate(0, 0, d-1)
+		if t.Month() != first.Month() {
+			return nil
+		}
+		return []time.Time{t}
+	}
+	return nil
+}
+
+// Expand คืนเวลาเริ่มของทุก occurrence ที่ช่วง [start, start+duration] ทับกับ [from, to)
+// dtstart คือ occurrence แรก (นับรวมใน COUNT)
+func (r *Rule) Expand(dtstart time.Time, duration time.Duration, from, to time.Time) []time.Time {
+	var (
+		out   []time.Time
+		count int
+	)
+
+	for i := 0; i < maxIterations; i++ {
+		cands := r.candidates(dtstart, i)
+		for _, c := range cands {
+			if c.Before(dtstart) {
+				continue
+			}
+			if r.Until != nil && c.After(*r.Until) {
+				return out
+			}
+			if !c.Before(to) {
+				return out
+			}
+
+			count++
+			if r.Count > 0 && count > r.Count {
+				return out
+			}
+			if !c.Add(duration).Before(from) {
+				out = append(out, c)
+			}
+		}
+	}
+	return out
+}
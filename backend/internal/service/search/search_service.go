@@ -0,0 +1,383 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+
+	"backend/internal/app/dto"
+	"backend/internal/app/entity"
+	"backend/internal/service/advisorlog"
+)
+
+// ค้นหารวม ผู้ใช้ / บันทึกการปรึกษา / FAQ / รายงานปัญหา
+// ภาษาไทยไม่มีช่องว่างคั่นคำ to_tsvector จึงตัดคำไม่ได้ ใช้ pg_trgm (ILIKE + word_similarity) กับ GIN index แทน
+// ดัชนีสร้างใน config/migration/search.go จาก Sources ชุดเดียวกับที่ใช้ค้น (นิพจน์ต้องตรงกันจึงใช้ index ได้)
+
+const (
+	TypeUser       = "user"
+	TypeAdvisorLog = "advisor_log"
+	TypeFAQ        = "faq"
+	TypeReport     = "report"
+
+	MinQueryLength = 2
+	DefaultLimit   = 10
+	MaxLimit       = 50
+	snippetRunes   = 120
+)
+
+var (
+	ErrQueryTooShort = errors.New("q must be at least 2 characters")
+	ErrInvalidType   = errors.New("invalid type (user, advisor_log, faq, report)")
+	ErrForbidden     = errors.New("forbidden")
+)
+
+// Source ตาราง + นิพจน์ข้อความที่ค้น
+type Source struct {
+	Type  string
+	Table string
+	// คอลัมน์ที่นำมาต่อกันเป็นข้อความค้นหา
+	Columns []string
+}
+
+var Sources = []Source{
+	{Type: TypeUser, Table: "users", Columns: []string{"sut_id", "first_name", "last_name", "email"}},
+	{Type: TypeAdvisorLog, Table: "advisor_logs", Columns: []string{"title", "body"}},
+	{Type: TypeFAQ, Table: "faqs", Columns: []string{"faq_question", "description"}},
+	{Type: TypeReport, Table: "reports", Columns: []string{"description"}},
+}
+
+// Expr นิพจน์ข้อความ (qualified = ใส่ชื่อตารางนำหน้า ใช้ตอน query ที่มี JOIN, index ใช้แบบไม่ใส่)
+func (src Source) Expr(qualified bool) string {
+	parts := make([]string, 0, len(src.Columns))
+	for _, c := range src.Columns {
+		if qualified {
+			c = src.Table + "." + c
+		}
+		parts = append(parts, "coalesce("+c+", '')")
+	}
+	return "(" + strings.Join(parts, " || ' ' || ") + ")"
+}
+
+func sourceOf(t string) Source {
+	for _, s := range Sources {
+		if s.Type == t {
+			return s
+		}
+	}
+	return Source{}
+}
+
+type Service interface {
+	// Search ค้นทุกประเภท (หรือเฉพาะ types) ได้ไม่เกิน limit ต่อประเภท เรียงตามความใกล้เคียง
+	Search(ctx context.Context, q string, types []string, limit int, requesterID uint, requesterRole string) (*dto.SearchResp, error)
+}
+
+type service struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) Service {
+	return &service{db: db}
+}
+
+// ------------------------------
+// helpers
+// ------------------------------
+
+func normRole(role string) string {
+	return strings.ToLower(strings.TrimSpace(role))
+}
+
+// NormalizeQuery ตัดช่องว่างซ้ำ และตรวจความยาวขั้นต่ำ (นับเป็นตัวอักษร ไม่ใช่ byte)
+func NormalizeQuery(q string) (string, error) {
+	q = strings.Join(strings.Fields(q), " ")
+	if utf8.RuneCountInString(q) < MinQueryLength {
+		return "", ErrQueryTooShort
+	}
+	return q, nil
+}
+
+// LikePattern ครอบ %...% โดย escape อักขระพิเศษของ LIKE
+func LikePattern(q string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + r.Replace(q) + "%"
+}
+
+// ParseTypes ?type=user,faq (ว่าง = ทุกประเภท)
+func ParseTypes(raw []string) ([]string, error) {
+	var out []string
+	seen := map[string]bool{}
+	for _, r := range raw {
+		for _, t := range strings.Split(r, ",") {
+			t = strings.TrimSpace(t)
+			if t == "" || seen[t] {
+				continue
+			}
+			if sourceOf(t).Type == "" {
+				return nil, ErrInvalidType
+			}
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	if len(out) == 0 {
+		for _, s := range Sources {
+			out = append(out, s.Type)
+		}
+	}
+	return out, nil
+}
+
+// Snippet ตัดข้อความรอบคำที่พบ (นับเป็น rune เพื่อไม่ให้ตัดกลางตัวอักษรไทย)
+func Snippet(text, q string, size int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if len(runes) <= size {
+		return text
+	}
+	start := 0
+	if i := strings.Index(strings.ToLower(text), strings.ToLower(q)); i >= 0 {
+		start = utf8.RuneCountInString(text[:i]) - size/3
+		if start < 0 {
+			start = 0
+		}
+	}
+	end := start + size
+	if end > len(runes) {
+		end = len(runes)
+		start = end - size
+	}
+	out := string(runes[start:end])
+	if start > 0 {
+		out = "…" + out
+	}
+	if end < len(runes) {
+		out += "…"
+	}
+	return out
+}
+
+// match เงื่อนไข ILIKE + คะแนนความใกล้เคียง (cols = คอลัมน์ที่ดึง ไม่รวม score)
+func match(q *gorm.DB, src Source, text, cols string, limit int) *gorm.DB {
+	expr := src.Expr(true)
+	return q.
+		Where(expr+" ILIKE ?", LikePattern(text)).
+		Select(cols+", word_similarity(?, "+expr+") AS score", text).
+		Order("score desc, " + src.Table + ".id desc").
+		Limit(limit)
+}
+
+// ------------------------------
+// SEARCH
+// ------------------------------
+
+func (s *service) Search(ctx context.Context, q string, types []string, limit int, requesterID uint, requesterRole string) (*dto.SearchResp, error) {
+	text, err := NormalizeQuery(q)
+	if err != nil {
+		return nil, err
+	}
+	switch normRole(requesterRole) {
+	case "student", "advisor", "admin":
+	default:
+		return nil, ErrForbidden
+	}
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	out := &dto.SearchResp{Query: text, Results: []dto.SearchResult{}, Counts: map[string]int{}}
+	for _, t := range types {
+		var rows []dto.SearchResult
+		switch t {
+		case TypeUser:
+			rows, err = s.users(ctx, text, limit, requesterID, requesterRole)
+		case TypeAdvisorLog:
+			rows, err = s.advisorLogs(ctx, text, limit, requesterID, requesterRole)
+		case TypeFAQ:
+			rows, err = s.faqs(ctx, text, limit, requesterRole)
+		case TypeReport:
+			rows, err = s.reports(ctx, text, limit, requesterID, requesterRole)
+		default:
+			return nil, ErrInvalidType
+		}
+		if err != nil {
+			return nil, err
+		}
+		out.Counts[t] = len(rows)
+		out.Results = append(out.Results, rows...)
+	}
+	sort.SliceStable(out.Results, func(i, j int) bool { return out.Results[i].Score > out.Results[j].Score })
+	return out, nil
+}
+
+// UserScope ผู้ใช้ที่ผู้เรียกค้นเจอได้: admin เห็นทุกคน, อาจารย์เห็นนักศึกษาในที่ปรึกษา + บุคลากร,
+// นักศึกษาเห็นเฉพาะอาจารย์/แอดมินและตัวเอง (ไม่เห็นนักศึกษาคนอื่น)
+func UserScope(db *gorm.DB, requesterID uint, requesterRole string) *gorm.DB {
+	q := db.Model(&entity.User{})
+
+	staff := "users.role_id IN (SELECT id FROM roles WHERE lower(role) IN ('advisor', 'admin'))"
+	switch normRole(requesterRole) {
+	case "advisor":
+		q = q.Where(staff+` OR users.id IN (
+			SELECT sp.user_id FROM student_profiles sp
+			JOIN advisor_profiles ap ON ap.id = sp.advisor_profile_id
+			WHERE ap.user_id = ? AND sp.deleted_at IS NULL)`, requesterID)
+	case "student":
+		q = q.Where(staff+" OR users.id = ?", requesterID)
+	}
+	return q
+}
+
+// UserQuery query ที่ users ใช้ค้น (แยกออกมาให้ทดสอบ SQL ได้โดยไม่ต้องมี DB)
+func UserQuery(db *gorm.DB, text string, limit int, requesterID uint, requesterRole string) *gorm.DB {
+	cols := "users.id, users.sut_id, users.first_name, users.last_name, users.email, " +
+		"(SELECT role FROM roles WHERE roles.id = users.role_id) AS role_name"
+	return match(UserScope(db, requesterID, requesterRole), sourceOf(TypeUser), text, cols, limit)
+}
+
+func (s *service) users(ctx context.Context, text string, limit int, requesterID uint, requesterRole string) ([]dto.SearchResult, error) {
+	type row struct {
+		ID        uint
+		SutID     string
+		FirstName string
+		LastName  string
+		Email     string
+		RoleName  string
+		Score     float64
+	}
+	var rows []row
+	if err := UserQuery(s.db.WithContext(ctx), text, limit, requesterID, requesterRole).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]dto.SearchResult, 0, len(rows))
+	for _, r := range rows {
+		res := dto.SearchResult{
+			Type:    TypeUser,
+			ID:      r.ID,
+			Title:   strings.TrimSpace(r.FirstName + " " + r.LastName),
+			Snippet: r.SutID,
+			Status:  r.RoleName,
+			Score:   r.Score,
+		}
+		// อีเมลไม่แสดงให้นักศึกษา
+		if normRole(requesterRole) != "student" {
+			res.Snippet = strings.TrimSpace(res.Snippet + " " + r.Email)
+		}
+		out = append(out, res)
+	}
+	return out, nil
+}
+
+// AdvisorLogQuery ใช้ขอบเขตเดียวกับรายการบันทึก (นักศึกษาเห็นเฉพาะของตัวเองที่ไม่ใช่ Draft) ไม่ค้นบันทึกส่วนตัว
+func AdvisorLogQuery(db *gorm.DB, text string, limit int, requesterID uint, requesterRole string) (*gorm.DB, error) {
+	q, err := advisorlog.Scope(db, requesterID, requesterRole)
+	if err != nil {
+		return nil, err
+	}
+	cols := "advisor_logs.id, advisor_logs.title, advisor_logs.body, advisor_logs.status"
+	return match(q, sourceOf(TypeAdvisorLog), text, cols, limit), nil
+}
+
+func (s *service) advisorLogs(ctx context.Context, text string, limit int, requesterID uint, requesterRole string) ([]dto.SearchResult, error) {
+	q, err := AdvisorLogQuery(s.db.WithContext(ctx), text, limit, requesterID, requesterRole)
+	if err != nil {
+		return nil, err
+	}
+	type row struct {
+		ID     uint
+		Title  string
+		Body   string
+		Status string
+		Score  float64
+	}
+	var rows []row
+	if err := q.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]dto.SearchResult, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, dto.SearchResult{
+			Type:    TypeAdvisorLog,
+			ID:      r.ID,
+			Title:   r.Title,
+			Snippet: Snippet(r.Body, text, snippetRunes),
+			Status:  r.Status,
+			Score:   r.Score,
+		})
+	}
+	return out, nil
+}
+
+// faqs ผู้ที่ไม่ใช่ admin เห็นเฉพาะที่ไม่ได้ซ่อน
+func (s *service) faqs(ctx context.Context, text string, limit int, requesterRole string) ([]dto.SearchResult, error) {
+	q := s.db.WithContext(ctx).Model(&entity.FAQ{})
+	if normRole(requesterRole) != "admin" {
+		q = q.Where("faqs.faq_status = ?", entity.StatusUnhide)
+	}
+	type row struct {
+		ID          uint
+		FAQQuestion string
+		Description string
+		FAQStatus   string
+		Score       float64
+	}
+	var rows []row
+	cols := "faqs.id, faqs.faq_question, faqs.description, faqs.faq_status"
+	if err := match(q, sourceOf(TypeFAQ), text, cols, limit).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]dto.SearchResult, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, dto.SearchResult{
+			Type:    TypeFAQ,
+			ID:      r.ID,
+			Title:   r.FAQQuestion,
+			Snippet: Snippet(r.Description, text, snippetRunes),
+			Status:  r.FAQStatus,
+			Score:   r.Score,
+		})
+	}
+	return out, nil
+}
+
+// reports admin เห็นทั้งหมด คนอื่นเห็นเฉพาะที่ตัวเองแจ้ง
+func (s *service) reports(ctx context.Context, text string, limit int, requesterID uint, requesterRole string) ([]dto.SearchResult, error) {
+	q := s.db.WithContext(ctx).Model(&entity.Report{})
+	if normRole(requesterRole) != "admin" {
+		q = q.Where("reports.report_by_id = ?", requesterID)
+	}
+	type row struct {
+		ID          uint
+		Description string
+		TopicName   string
+		StatusName  string
+		Score       float64
+	}
+	var rows []row
+	cols := "reports.id, reports.description, " +
+		"(SELECT report_topic_name FROM report_topics WHERE report_topics.id = reports.report_topic_id) AS topic_name, " +
+		"(SELECT report_status_name FROM report_statuses WHERE report_statuses.id = reports.report_status_id) AS status_name"
+	if err := match(q, sourceOf(TypeReport), text, cols, limit).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]dto.SearchResult, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, dto.SearchResult{
+			Type:    TypeReport,
+			ID:      r.ID,
+			Title:   r.TopicName,
+			Snippet: Snippet(r.Description, text, snippetRunes),
+			Status:  r.StatusName,
+			Score:   r.Score,
+		})
+	}
+	return out, nil
+}
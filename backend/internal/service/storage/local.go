@@ -0,0 +1,157 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Local เก็บไฟล์บนดิสก์ใต้ root (key = path ย่อยภายใต้ root)
+type Local struct {
+	root string
+}
+
+func NewLocal(root string) *Local {
+	if root == "" {
+		root = "uploads"
+	}
+	return &Local{root: root}
+}
+
+func (l *Local) Root() string {
+	return l.root
+}
+
+func (l *Local) path(key string) (string, error) {
+	key, err := CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(l.root, filepath.FromSlash(key)), nil
+}
+
+func (l *Local) info(key string, fi os.FileInfo) *ObjectInfo {
+	ct := mime.TypeByExtension(strings.ToLower(filepath.Ext(key)))
+	if ct == "" {
+		ct = "application/octet-stream"
+	}
+	return &ObjectInfo{Key: key, Size: fi.Size(), ContentType: ct, ModTime: fi.ModTime()}
+}
+
+// Put เขียนลงไฟล์ชั่วคราวก่อนแล้วค่อย rename (ไม่มีไฟล์ครึ่งๆ ถ้าเขียนไม่สำเร็จ)
+func (l *Local) Put(_ context.Context, key string, r io.Reader, _ int64, _ string) error {
+	dest, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dest), tempPrefix+"*")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+func (l *Local) Get(_ context.Context, key string) (Object, *ObjectInfo, error) {
+	p, err := l.path(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, ErrNotFound
+		}
+		return nil, nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil || fi.IsDir() {
+		f.Close()
+		return nil, nil, ErrNotFound
+	}
+	return f, l.info(key, fi), nil
+}
+
+func (l *Local) Delete(_ context.Context, key string) error {
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (l *Local) Stat(_ context.Context, key string) (*ObjectInfo, error) {
+	p, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := os.Stat(p)
+	if err != nil || fi.IsDir() {
+		return nil, ErrNotFound
+	}
+	return l.info(key, fi), nil
+}
+
+// SignedURL ดิสก์ในเครื่องไม่มี URL ของตัวเอง ต้องดาวน์โหลดผ่าน API
+func (l *Local) SignedURL(context.Context, string, time.Duration, string) (string, error) {
+	return "", ErrNotSupported
+}
+
+// tempPrefix ไฟล์ชั่วคราวระหว่าง Put (ไม่นับเป็น object)
+const tempPrefix = ".upload-"
+
+func (l *Local) List(ctx context.Context, prefix string, fn func(ObjectInfo) error) error {
+	err := filepath.WalkDir(l.root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), tempPrefix) {
+			return nil
+		}
+		rel, err := filepath.Rel(l.root, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return fn(*l.info(key, fi))
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3 เก็บไฟล์ใน bucket ของ S3-compatible storage (AWS S3, MinIO, ...)
+type S3 struct {
+	client *minio.Client
+	bucket string
+}
+
+func NewS3(ctx context.Context, cfg Config) (*S3, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, errors.New("s3 storage requires S3_ENDPOINT and S3_BUCKET")
+	}
+
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s := &S3{client: client, bucket: cfg.Bucket}
+	if cfg.CreateBucket {
+		if err := s.ensureBucket(ctx, cfg.Region); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func (s *S3) ensureBucket(ctx context.Context, region string) error {
+	ok, err := s.client.BucketExists(ctx, s.bucket)
+	if err != nil {
+		return fmt.Errorf("check bucket %q: %w", s.bucket, err)
+	}
+	if ok {
+		return nil
+	}
+	return s.client.MakeBucket(ctx, s.bucket, minio.MakeBucketOptions{Region: region})
+}
+
+func isNotFound(err error) bool {
+	switch minio.ToErrorResponse(err).Code {
+	case "NoSuchKey", "NotFound":
+		return true
+	}
+	return false
+}
+
+func (s *S3) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	key, err := CleanKey(key)
+	if err != nil {
+		return err
+	}
+	if size <= 0 {
+		size = -1
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	_, err = s.client.PutObject(ctx, s.bucket, key, r, size, minio.PutObjectOptions{ContentType: contentType})
+	return err
+}
+
+// Get minio.Object โหลดแบบ lazy: Stat ก่อนเพื่อรู้ว่ามี object จริงไหม
+func (s *S3) Get(ctx context.Context, key string) (Object, *ObjectInfo, error) {
+	key, err := CleanKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	obj, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	st, err := obj.Stat()
+	if err != nil {
+		obj.Close()
+		if isNotFound(err) {
+			return nil, nil, ErrNotFound
+		}
+		return nil, nil, err
+	}
+	return obj, toInfo(st), nil
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+	key, err := CleanKey(key)
+	if err != nil {
+		return err
+	}
+	// S3 ลบ key ที่ไม่มีอยู่ได้โดยไม่ error อยู่แล้ว
+	return s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{})
+}
+
+func (s *S3) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	key, err := CleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+	st, err := s.client.StatObject(ctx, s.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return toInfo(st), nil
+}
+
+// SignedURL presigned GET พร้อมตั้งชื่อไฟล์ตอนดาวน์โหลด
+func (s *S3) SignedURL(ctx context.Context, key string, expiry time.Duration, downloadName string) (string, error) {
+	key, err := CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	params := url.Values{}
+	if downloadName != "" {
+		params.Set("response-content-disposition",
+			mime.FormatMediaType("attachment", map[string]string{"filename": downloadName}))
+	}
+	u, err := s.client.PresignedGetObject(ctx, s.bucket, key, expiry, params)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+func (s *S3) List(ctx context.Context, prefix string, fn func(ObjectInfo) error) error {
+	// ยกเลิก context เพื่อหยุด goroutine ของ ListObjects เมื่อออกจากลูปก่อนจบ
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for obj := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if obj.Err != nil {
+			return obj.Err
+		}
+		if err := fn(*toInfo(obj)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func toInfo(st minio.ObjectInfo) *ObjectInfo {
+	return &ObjectInfo{
+		Key:         st.Key,
+		Size:        st.Size,
+		ContentType: st.ContentType,
+		ModTime:     st.LastModified,
+	}
+}
@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Storage ที่เก็บไฟล์อัปโหลด (ดิสก์ในเครื่องหรือ S3-compatible เช่น MinIO)
+// key เป็น path แบบใช้ "/" คั่น และไม่ขึ้นกับ driver (เก็บใน file_assets.storage_path)
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get คืน object ที่ seek ได้ (ใช้กับ http.ServeContent เพื่อรองรับ Range)
+	Get(ctx context.Context, key string) (Object, *ObjectInfo, error)
+	// Delete ไม่ถือเป็น error ถ้าไม่มี key นั้นอยู่แล้ว
+	Delete(ctx context.Context, key string) error
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	// SignedURL ลิงก์ดาวน์โหลดตรงจาก storage (ErrNotSupported สำหรับ driver ที่ทำไม่ได้)
+	SignedURL(ctx context.Context, key string, expiry time.Duration, downloadName string) (string, error)
+	// List ไล่ทุก object ที่ขึ้นต้นด้วย prefix ("" = ทั้งหมด) ใช้กับงานตรวจ/เก็บกวาดไฟล์
+	List(ctx context.Context, prefix string, fn func(ObjectInfo) error) error
+}
+
+type Object interface {
+	io.ReadSeekCloser
+}
+
+type ObjectInfo struct {
+	Key         string
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+}
+
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+var (
+	ErrNotFound     = errors.New("object not found")
+	ErrInvalidKey   = errors.New("invalid storage key")
+	ErrNotSupported = errors.New("operation not supported by storage driver")
+)
+
+// Config ค่าตั้งต้นของ storage (อ่านจาก env ด้วย ConfigFromEnv)
+type Config struct {
+	Driver string
+
+	// local
+	LocalRoot string
+
+	// s3
+	Endpoint     string
+	Region       string
+	Bucket       string
+	AccessKey    string
+	SecretKey    string
+	UseSSL       bool
+	CreateBucket bool
+}
+
+func envOr(key, def string) string {
+	if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+		return v
+	}
+	return def
+}
+
+func envBool(key string, def bool) bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(key))) {
+	case "1", "true", "yes":
+		return true
+	case "0", "false", "no":
+		return false
+	}
+	return def
+}
+
+// ConfigFromEnv
+//
+//	STORAGE_DRIVER=local|s3 (ค่าเริ่มต้น local)
+//	UPLOAD_DIR (local, ค่าเริ่มต้น uploads)
+//	S3_ENDPOINT, S3_REGION, S3_BUCKET, S3_ACCESS_KEY, S3_SECRET_KEY, S3_USE_SSL, S3_CREATE_BUCKET
+func ConfigFromEnv() Config {
+	return Config{
+		Driver:       strings.ToLower(envOr("STORAGE_DRIVER", DriverLocal)),
+		LocalRoot:    envOr("UPLOAD_DIR", "uploads"),
+		Endpoint:     envOr("S3_ENDPOINT", ""),
+		Region:       envOr("S3_REGION", ""),
+		Bucket:       envOr("S3_BUCKET", ""),
+		AccessKey:    envOr("S3_ACCESS_KEY", ""),
+		SecretKey:    envOr("S3_SECRET_KEY", ""),
+		UseSSL:       envBool("S3_USE_SSL", false),
+		CreateBucket: envBool("S3_CREATE_BUCKET", true),
+	}
+}
+
+// New สร้าง storage ตาม driver ใน config
+func New(ctx context.Context, cfg Config) (Storage, error) {
+	switch cfg.Driver {
+	case "", DriverLocal:
+		return NewLocal(cfg.LocalRoot), nil
+	case DriverS3:
+		return NewS3(ctx, cfg)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
+
+// CleanKey ตรวจ key: ห้ามว่าง ห้าม absolute และห้ามมี ".." (กันหลุดออกนอก root/bucket)
+func CleanKey(key string) (string, error) {
+	key = strings.TrimSpace(strings.ReplaceAll(key, "\\", "/"))
+	key = strings.TrimLeft(key, "/")
+	if key == "" {
+		return "", ErrInvalidKey
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return "", ErrInvalidKey
+		}
+	}
+	return key, nil
+}
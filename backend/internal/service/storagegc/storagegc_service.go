@@ -0,0 +1,286 @@
+package storagegc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"backend/internal/app/entity"
+	"backend/internal/service/storage"
+)
+
+// งานบำรุงรักษาที่เก็บไฟล์: เทียบไฟล์ใน storage กับแถว file_assets
+//   - missing:  แถวที่ยังใช้งานอยู่แต่ไม่มีไฟล์จริง (รายงานอย่างเดียว)
+//   - orphan:   ไฟล์ที่ไม่มีแถวไหนอ้างถึง และเก่ากว่า grace period (เช่น crash ระหว่างเขียนไฟล์กับ commit)
+//   - purge:    ไฟล์ของเจ้าของที่ถูกลบ (soft delete) นานเกิน retention และแถว file_assets ที่ถูกลบนานเกิน retention
+//
+// ค่าเริ่มต้นเป็น dry-run: รายงานอย่างเดียว ไม่ลบอะไร
+
+const (
+	DefaultGrace     = 24 * time.Hour
+	DefaultRetention = 365 * 24 * time.Hour
+)
+
+// ownerTables ตารางของเจ้าของไฟล์แต่ละชนิด (ใช้ดูว่าเจ้าของถูกลบไปนานแค่ไหน)
+var ownerTables = map[string]string{
+	entity.FileOwnerAdvisorLog:     "advisor_logs",
+	entity.FileOwnerProgressReport: "progress_reports",
+	entity.FileOwnerReport:         "reports",
+	entity.FileOwnerAppointment:    "appointments",
+	entity.FileOwnerProfileImage:   "users",
+	entity.FileOwnerReportFeedback: "report_feedbacks",
+}
+
+type Options struct {
+	Grace     time.Duration
+	Retention time.Duration
+	Apply     bool      // false = dry-run
+	Now       time.Time // ค่าว่าง = time.Now()
+}
+
+type MissingFile struct {
+	AssetID   uint   `json:"asset_id"`
+	OwnerType string `json:"owner_type"`
+	OwnerID   uint   `json:"owner_id"`
+	Key       string `json:"key"`
+}
+
+type OrphanFile struct {
+	Key     string    `json:"key"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	Deleted bool      `json:"deleted"`
+}
+
+type PurgedFile struct {
+	AssetID   uint   `json:"asset_id"`
+	OwnerType string `json:"owner_type"`
+	OwnerID   uint   `json:"owner_id"`
+	Key       string `json:"key"`
+	Reason    string `json:"reason"` // owner_deleted | owner_missing | asset_deleted
+	Deleted   bool   `json:"deleted"`
+}
+
+type Report struct {
+	DryRun       bool          `json:"dry_run"`
+	StartedAt    time.Time     `json:"started_at"`
+	Objects      int           `json:"objects"`
+	Referenced   int           `json:"referenced"`
+	Missing      []MissingFile `json:"missing"`
+	Orphans      []OrphanFile  `json:"orphans"`
+	Purged       []PurgedFile  `json:"purged"`
+	ReclaimBytes int64         `json:"reclaim_bytes"`
+	Errors       []string      `json:"errors"`
+}
+
+type Service interface {
+	Run(ctx context.Context, opts Options) (*Report, error)
+}
+
+type service struct {
+	db    *gorm.DB
+	store storage.Storage
+}
+
+func New(db *gorm.DB, store storage.Storage) Service {
+	return &service{db: db, store: store}
+}
+
+func (o Options) normalize() Options {
+	if o.Grace <= 0 {
+		o.Grace = DefaultGrace
+	}
+	if o.Retention <= 0 {
+		o.Retention = DefaultRetention
+	}
+	if o.Now.IsZero() {
+		o.Now = time.Now()
+	}
+	return o
+}
+
+func (r *Report) errorf(format string, args ...any) {
+	r.Errors = append(r.Errors, fmt.Sprintf(format, args...))
+}
+
+// ------------------------------
+// RUN
+// ------------------------------
+
+func (s *service) Run(ctx context.Context, opts Options) (*Report, error) {
+	opts = opts.normalize()
+	rep := &Report{DryRun: !opts.Apply, StartedAt: opts.Now}
+
+	// 1) purge ก่อน เพื่อให้ไฟล์ที่ถูก purge ไม่ถูกนับเป็น orphan ซ้ำ
+	if err := s.purge(ctx, opts, rep); err != nil {
+		return nil, err
+	}
+
+	// 2) แถวที่ยังอ้างถึงไฟล์อยู่ (ถ้า dry-run ให้นับแถวที่จะ purge ว่ายังอ้างอยู่)
+	//    รวมแถวที่ถูก soft delete แต่ยังไม่ถึง retention (เช่น ไฟล์ของ revision เก่าใน advisor log)
+	var rows []entity.FileAssets
+	if err := s.db.WithContext(ctx).Unscoped().
+		Select("id", "owner_type", "owner_id", "storage_path", "deleted_at").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	referenced := make(map[string]bool, len(rows))
+	var live []entity.FileAssets
+	for _, a := range rows {
+		referenced[a.StoragePath] = true
+		if !a.DeletedAt.Valid {
+			live = append(live, a)
+		}
+	}
+	if !opts.Apply {
+		for _, p := range rep.Purged {
+			referenced[p.Key] = true
+		}
+	}
+	rep.Referenced = len(referenced)
+
+	// 3) ไล่ไฟล์ใน storage หา orphan
+	seen := make(map[string]bool, len(referenced))
+	cutoff := opts.Now.Add(-opts.Grace)
+	err := s.store.List(ctx, "", func(obj storage.ObjectInfo) error {
+		rep.Objects++
+		seen[obj.Key] = true
+		if referenced[obj.Key] || obj.ModTime.After(cutoff) {
+			return nil
+		}
+
+		orphan := OrphanFile{Key: obj.Key, Size: obj.Size, ModTime: obj.ModTime}
+		if opts.Apply {
+			if err := s.store.Delete(ctx, obj.Key); err != nil {
+				rep.errorf("delete orphan %s: %v", obj.Key, err)
+			} else {
+				orphan.Deleted = true
+			}
+		}
+		rep.ReclaimBytes += obj.Size
+		rep.Orphans = append(rep.Orphans, orphan)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list storage: %w", err)
+	}
+
+	// 4) แถวที่อ้างถึงไฟล์ที่ไม่มีอยู่จริง
+	for _, a := range live {
+		if seen[a.StoragePath] {
+			continue
+		}
+		// key ที่ List ไม่เห็น (เช่น key เก่านอก root) ตรวจซ้ำด้วย Stat ก่อนสรุปว่าหาย
+		if _, err := s.store.Stat(ctx, a.StoragePath); err == nil {
+			continue
+		} else if !errors.Is(err, storage.ErrNotFound) && !errors.Is(err, storage.ErrInvalidKey) {
+			rep.errorf("stat %s: %v", a.StoragePath, err)
+			continue
+		}
+		rep.Missing = append(rep.Missing, MissingFile{
+			AssetID:   a.ID,
+			OwnerType: a.OwnerType,
+			OwnerID:   a.OwnerID,
+			Key:       a.StoragePath,
+		})
+	}
+
+	return rep, nil
+}
+
+// ------------------------------
+// PURGE (retention)
+// ------------------------------
+
+type purgeRow struct {
+	ID          uint
+	OwnerType   string
+	OwnerID     uint
+	StoragePath string
+	Size        int64
+	Reason      string
+}
+
+func (s *service) purgeCandidates(ctx context.Context, cutoff time.Time) ([]purgeRow, error) {
+	var out []purgeRow
+
+	// ไฟล์ของเจ้าของที่ถูกลบนานเกิน retention หรือเจ้าของหายไปแล้ว
+	for ownerType, table := range ownerTables {
+		var rows []purgeRow
+		err := s.db.WithContext(ctx).Raw(fmt.Sprintf(`
+			SELECT fa.id, fa.owner_type, fa.owner_id, fa.storage_path, fa.size,
+				CASE WHEN o.id IS NULL THEN 'owner_missing' ELSE 'owner_deleted' END AS reason
+			FROM file_assets fa
+			LEFT JOIN %s o ON o.id = fa.owner_id
+			WHERE fa.deleted_at IS NULL AND fa.owner_type = ?
+				AND (o.id IS NULL OR (o.deleted_at IS NOT NULL AND o.deleted_at < ?))`, table),
+			ownerType, cutoff).Scan(&rows).Error
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, rows...)
+	}
+
+	// แถว file_assets ที่ถูกลบ (ถูกแทนที่) นานเกิน retention
+	// ยกเว้นไฟล์ที่ revision ของ advisor log ที่ยังอยู่อ้างถึง (ต้องเก็บไว้ให้ดู/กู้คืนได้)
+	var rows []purgeRow
+	if err := s.db.WithContext(ctx).Raw(`
+		SELECT fa.id, fa.owner_type, fa.owner_id, fa.storage_path, fa.size, 'asset_deleted' AS reason
+		FROM file_assets fa
+		WHERE fa.deleted_at IS NOT NULL AND fa.deleted_at < ?
+			AND NOT EXISTS (
+				SELECT 1 FROM advisor_log_revision_files rf
+				JOIN advisor_log_revisions r ON r.id = rf.revision_id
+				JOIN advisor_logs l ON l.id = r.advisor_log_id
+				WHERE rf.file_assets_id = fa.id AND l.deleted_at IS NULL)`, cutoff).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	out = append(out, rows...)
+
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+func (s *service) purge(ctx context.Context, opts Options, rep *Report) error {
+	rows, err := s.purgeCandidates(ctx, opts.Now.Add(-opts.Retention))
+	if err != nil {
+		return fmt.Errorf("find purge candidates: %w", err)
+	}
+
+	for _, r := range rows {
+		p := PurgedFile{AssetID: r.ID, OwnerType: r.OwnerType, OwnerID: r.OwnerID, Key: r.StoragePath, Reason: r.Reason}
+		if opts.Apply {
+			if err := s.purgeOne(ctx, r); err != nil {
+				rep.errorf("purge asset %d: %v", r.ID, err)
+			} else {
+				p.Deleted = true
+			}
+		}
+		rep.ReclaimBytes += r.Size
+		rep.Purged = append(rep.Purged, p)
+	}
+	return nil
+}
+
+// purgeOne ลบไฟล์ก่อนแล้วค่อยลบแถว (ถ้าลบแถวไม่สำเร็จ รอบหน้าจะเจอเป็น missing แทน orphan ที่หาไม่เจอ)
+func (s *service) purgeOne(ctx context.Context, r purgeRow) error {
+	if err := s.store.Delete(ctx, r.StoragePath); err != nil && !errors.Is(err, storage.ErrInvalidKey) {
+		return err
+	}
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("file_assets_id = ?", r.ID).Delete(&entity.ReportImage{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("file_assets_id = ?", r.ID).Delete(&entity.AppointmentAttachment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("file_assets_id = ?", r.ID).Delete(&entity.AdvisorLogRevisionFile{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&entity.FileAssets{}, r.ID).Error
+	})
+}
@@ -1,11 +1,14 @@
 package main
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
 
 	"backend/config"
 	"backend/internal/routes"
 	"backend/internal/middlewares"
+	"backend/internal/service/reportescalation"
 )
 
 const PORT = "8080"
@@ -14,6 +17,12 @@ func main() {
 	// ต่อ DB + migrate + seed
 	config.ConnectDB()
 	config.SetupDatabase()
+	config.SetupStorage()
+
+	// แจ้งเตือนรายงานความคืบหน้าที่เลยกำหนด (ทุก REPORT_ESCALATION_INTERVAL, 0 = ปิด)
+	reportescalation.Schedule(context.Background(), reportescalation.New(config.DB()),
+		reportescalation.IntervalFromEnv(), reportescalation.ThresholdsFromEnv())
+
 	gin.SetMode(gin.ReleaseMode)
 	// สร้าง router
 	r := gin.New()
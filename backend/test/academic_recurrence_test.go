@@ -0,0 +1,117 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"backend/internal/app/entity"
+	academic "backend/internal/service/academiccalendar"
+	"backend/internal/service/rrule"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestAcademicRecurrence(t *testing.T) {
+	RegisterTestingT(t)
+
+	loc, err := time.LoadLocation("Asia/Bangkok")
+	Expect(err).To(BeNil())
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, loc) }
+	dates := func(ts []time.Time) []string {
+		out := make([]string, 0, len(ts))
+		for _, t := range ts {
+			out = append(out, t.Format("2006-01-02"))
+		}
+		return out
+	}
+
+	t.Run("unsupported or invalid rules are rejected", func(t *testing.T) {
+		_, err := rrule.Parse("FREQ=HOURLY")
+		Expect(err).To(MatchError(rrule.ErrUnsupportedRule))
+
+		_, err = rrule.Parse("FREQ=MONTHLY;BYDAY=1MO")
+		Expect(err).To(MatchError(rrule.ErrUnsupportedRule))
+
+		_, err = rrule.Parse("INTERVAL=2")
+		Expect(err).To(MatchError(ContainSubstring("FREQ is required")))
+
+		_, err = rrule.Parse("FREQ=DAILY;COUNT=3;UNTIL=20250101")
+		Expect(err).NotTo(BeNil())
+
+		rule, err := rrule.Normalize(" rrule:freq=weekly;byday=mo,we ")
+		Expect(err).To(BeNil())
+		Expect(rule).To(Equal("FREQ=WEEKLY;BYDAY=MO,WE"))
+	})
+
+	t.Run("weekly BYDAY with UNTIL", func(t *testing.T) {
+		r, err := rrule.Parse("FREQ=WEEKLY;BYDAY=MO,WE;UNTIL=20250616")
+		Expect(err).To(BeNil())
+
+		// 2025-06-04 เป็นวันพุธ
+		start := time.Date(2025, 6, 4, 9, 0, 0, 0, loc)
+		got := r.Expand(start, 2*time.Hour, day(2025, 1, 1), day(2026, 1, 1))
+		Expect(dates(got)).To(Equal([]string{"2025-06-04", "2025-06-09", "2025-06-11", "2025-06-16"}))
+		Expect(got[1].Hour()).To(Equal(9))
+	})
+
+	t.Run("COUNT includes occurrences before the window", func(t *testing.T) {
+		r, err := rrule.Parse("FREQ=DAILY;INTERVAL=2;COUNT=4")
+		Expect(err).To(BeNil())
+
+		got := r.Expand(day(2025, 3, 1), time.Hour, day(2025, 3, 4), day(2025, 4, 1))
+		Expect(dates(got)).To(Equal([]string{"2025-03-05", "2025-03-07"}))
+	})
+
+	t.Run("monthly on the 31st skips short months", func(t *testing.T) {
+		r, err := rrule.Parse("FREQ=MONTHLY;COUNT=3")
+		Expect(err).To(BeNil())
+
+		got := r.Expand(day(2025, 1, 31), time.Hour, day(2025, 1, 1), day(2026, 1, 1))
+		Expect(dates(got)).To(Equal([]string{"2025-01-31", "2025-03-31", "2025-05-31"}))
+	})
+
+	t.Run("ExpandEvents merges single and recurring events in time order", func(t *testing.T) {
+		events := []entity.AcademicCalendar{
+			{EventName: "ประชุมภาควิชา", EventType: "activity",
+				StartDateTime: time.Date(2025, 6, 2, 13, 0, 0, 0, loc),
+				EndDateTime:   time.Date(2025, 6, 2, 15, 0, 0, 0, loc),
+				RRule:         "FREQ=WEEKLY;COUNT=3"},
+			{EventName: "วันเฉลิมฯ", EventType: "holiday", IsHoliday: true,
+				StartDateTime: day(2025, 6, 3),
+				EndDateTime:   time.Date(2025, 6, 3, 23, 59, 59, 0, loc)},
+		}
+
+		occ := academic.ExpandEvents(events, day(2025, 6, 1), day(2025, 7, 1))
+		Expect(occ).To(HaveLen(4))
+		Expect(occ[0].Event.EventName).To(Equal("ประชุมภาควิชา"))
+		Expect(occ[1].Event.EventName).To(Equal("วันเฉลิมฯ"))
+		Expect(occ[3].Start.Format("2006-01-02 15:04")).To(Equal("2025-06-16 13:00"))
+		Expect(occ[3].End.Format("15:04")).To(Equal("15:00"))
+	})
+
+	t.Run("semester validation and exam windows", func(t *testing.T) {
+		midStart, midEnd := day(2025, 9, 1), day(2025, 9, 7)
+		sem := entity.Semester{
+			Name: "ภาคการศึกษาที่ 1/2568", AcademicYear: 2568, TermNumber: 1,
+			StartDate: day(2025, 7, 14), EndDate: day(2025, 11, 7),
+			MidtermExamStart: &midStart, MidtermExamEnd: &midEnd,
+		}
+		Expect(sem.Validate()).To(Succeed())
+		Expect(sem.Contains(day(2025, 11, 7))).To(BeTrue())
+		Expect(sem.Contains(day(2025, 11, 8))).To(BeFalse())
+		Expect(sem.InExamPeriod(day(2025, 9, 3))).To(BeTrue())
+		Expect(sem.InExamPeriod(day(2025, 9, 8))).To(BeFalse())
+
+		bad := sem
+		bad.TermNumber = 4
+		Expect(bad.Validate()).To(MatchError(ContainSubstring("ภาคการศึกษาต้องเป็น")))
+
+		bad = sem
+		bad.MidtermExamEnd = nil
+		Expect(bad.Validate()).To(MatchError(ContainSubstring("สอบกลางภาค")))
+
+		bad = sem
+		bad.EndDate = day(2025, 7, 1)
+		Expect(bad.Validate()).NotTo(BeNil())
+	})
+}
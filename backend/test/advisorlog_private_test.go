@@ -0,0 +1,83 @@
+package test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"backend/internal/app/dto"
+	"backend/internal/app/entity"
+	"backend/internal/service/advisorlog"
+	"backend/internal/service/export"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestAdvisorLogPrivateNotes(t *testing.T) {
+	RegisterTestingT(t)
+
+	appt := &entity.Appointment{StudentUserID: 10, AdvisorUserID: 20}
+	log := entity.AdvisorLog{
+		Title:        "ติดตามผลการเรียน",
+		Body:         "สรุปที่นักศึกษาเห็น",
+		Status:       "Completed",
+		PrivateNotes: "ความเห็นส่วนตัวของอาจารย์",
+		Appointment:  appt,
+	}
+
+	t.Run("only the appointment's advisor sees private notes", func(t *testing.T) {
+		out := advisorlog.ToResp(log, 20, "Advisor")
+		Expect(out.PrivateNotes).NotTo(BeNil())
+		Expect(*out.PrivateNotes).To(Equal("ความเห็นส่วนตัวของอาจารย์"))
+
+		Expect(advisorlog.ToResp(log, 10, "Student").PrivateNotes).To(BeNil())
+		Expect(advisorlog.ToResp(log, 21, "Advisor").PrivateNotes).To(BeNil())
+		Expect(advisorlog.ToResp(log, 1, "Admin").PrivateNotes).To(BeNil())
+		// นักศึกษาที่ id ตรงกับอาจารย์ก็ไม่เห็น (ต้องเป็น role advisor)
+		Expect(advisorlog.ToResp(log, 20, "Student").PrivateNotes).To(BeNil())
+	})
+
+	t.Run("serialized response never carries notes for students", func(t *testing.T) {
+		raw, err := json.Marshal(advisorlog.ToResp(log, 10, "student"))
+		Expect(err).To(BeNil())
+		Expect(string(raw)).NotTo(ContainSubstring("privateNotes"))
+		Expect(string(raw)).NotTo(ContainSubstring("ความเห็นส่วนตัว"))
+	})
+
+	t.Run("entity json (appointment.advisor_log) omits notes", func(t *testing.T) {
+		raw, err := json.Marshal(entity.Appointment{AdvisorLog: &log})
+		Expect(err).To(BeNil())
+		Expect(string(raw)).NotTo(ContainSubstring("ความเห็นส่วนตัว"))
+	})
+
+	t.Run("revisions are redacted for non-owners", func(t *testing.T) {
+		notes := "ลับ"
+		revs := []dto.AdvisorLogRevisionResp{
+			{Revision: 3, Action: entity.RevisionActionUpdate, ChangedFields: []string{advisorlog.FieldPrivateNotes}, PrivateNotes: &notes},
+			{Revision: 2, Action: entity.RevisionActionUpdate, ChangedFields: []string{advisorlog.FieldBody, advisorlog.FieldPrivateNotes}, PrivateNotes: &notes},
+			{Revision: 1, Action: entity.RevisionActionCreate, ChangedFields: []string{advisorlog.FieldTitle}, PrivateNotes: &notes},
+		}
+
+		Expect(advisorlog.RedactRevisions(revs, true)).To(HaveLen(3))
+
+		out := advisorlog.RedactRevisions(revs, false)
+		Expect(out).To(HaveLen(2))
+		Expect(out[0].Revision).To(Equal(2))
+		Expect(out[0].ChangedFields).To(Equal([]string{advisorlog.FieldBody}))
+		for _, r := range out {
+			Expect(r.PrivateNotes).To(BeNil())
+		}
+
+		from := entity.AdvisorLogRevision{Revision: 1, Body: "a", PrivateNotes: "x"}
+		to := entity.AdvisorLogRevision{Revision: 2, Body: "b", PrivateNotes: "y"}
+		d := advisorlog.RedactDiff(advisorlog.Diff(from, to), false)
+		Expect(d.Changes).To(HaveLen(1))
+		Expect(d.Changes[0].Field).To(Equal(advisorlog.FieldBody))
+	})
+
+	t.Run("exports have no private notes column", func(t *testing.T) {
+		for _, c := range export.AdvisorLogColumns {
+			Expect(c.Key).NotTo(ContainSubstring("private"))
+			Expect(c.Value(log)).NotTo(ContainSubstring("ความเห็นส่วนตัว"))
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package test
+
+import (
+	"testing"
+
+	"backend/internal/app/dto"
+	"backend/internal/app/entity"
+	"backend/internal/service/advisorlog"
+
+	. "github.com/onsi/gomega"
+)
+
+func revisionWithFiles(n int, title, body string, files ...entity.FileAssets) entity.AdvisorLogRevision {
+	rev := entity.AdvisorLogRevision{Revision: n, AdvisorLogID: 9, Title: title, Body: body}
+	for i := range files {
+		f := files[i]
+		rev.Files = append(rev.Files, entity.AdvisorLogRevisionFile{FileAssetsID: f.ID, Position: i, FileAssets: &f})
+	}
+	return rev
+}
+
+func TestAdvisorLogRevisions(t *testing.T) {
+	RegisterTestingT(t)
+
+	a := entity.FileAssets{OriginalName: "a.pdf", SHA256: "aaa"}
+	a.ID = 1
+	b := entity.FileAssets{OriginalName: "b.pdf", SHA256: "bbb"}
+	b.ID = 2
+
+	t.Run("changed fields", func(t *testing.T) {
+		r1 := revisionWithFiles(1, "นัดครั้งที่ 1", "สรุป", a)
+		r2 := revisionWithFiles(2, "นัดครั้งที่ 1", "สรุป", a)
+		Expect(advisorlog.ChangedFields(r1, r2)).To(BeEmpty())
+
+		r2.Body = "สรุปใหม่"
+		r2.RequiresReport = true
+		Expect(advisorlog.ChangedFields(r1, r2)).To(Equal([]string{advisorlog.FieldBody, advisorlog.FieldRequiresReport}))
+
+		r3 := revisionWithFiles(3, "นัดครั้งที่ 1", "สรุป", b)
+		Expect(advisorlog.ChangedFields(r1, r3)).To(Equal([]string{advisorlog.FieldFiles}))
+	})
+
+	t.Run("line diff", func(t *testing.T) {
+		out := advisorlog.LineDiff("หัวข้อ\nเดิม\nท้าย", "หัวข้อ\nใหม่\nท้าย")
+		Expect(out).To(Equal([]dto.AdvisorLogDiffLine{
+			{Op: "equal", Text: "หัวข้อ"},
+			{Op: "remove", Text: "เดิม"},
+			{Op: "add", Text: "ใหม่"},
+			{Op: "equal", Text: "ท้าย"},
+		}))
+
+		Expect(advisorlog.LineDiff("", "x")).To(Equal([]dto.AdvisorLogDiffLine{{Op: "add", Text: "x"}}))
+		Expect(advisorlog.LineDiff("", "")).To(BeEmpty())
+	})
+
+	t.Run("diff reports field changes and file set", func(t *testing.T) {
+		from := revisionWithFiles(1, "เดิม", "body", a)
+		to := revisionWithFiles(3, "ใหม่", "body", a, b)
+
+		d := advisorlog.Diff(from, to)
+		Expect(d.From).To(Equal(1))
+		Expect(d.To).To(Equal(3))
+		Expect(d.Changes).To(ContainElement(dto.AdvisorLogFieldChange{Field: advisorlog.FieldTitle, From: "เดิม", To: "ใหม่"}))
+		Expect(d.FilesAdded).To(HaveLen(1))
+		Expect(d.FilesAdded[0].Name).To(Equal("b.pdf"))
+		Expect(d.FilesRemoved).To(BeEmpty())
+
+		// ย้อนทิศ: b ถูกเอาออก
+		back := advisorlog.Diff(to, from)
+		Expect(back.FilesRemoved).To(HaveLen(1))
+		Expect(back.FilesRemoved[0].Name).To(Equal("b.pdf"))
+	})
+}
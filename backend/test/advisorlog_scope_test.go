@@ -0,0 +1,78 @@
+package test
+
+import (
+	"net/url"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"backend/internal/app/entity"
+	"backend/internal/app/queryspec"
+	"backend/internal/service/advisorlog"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestAdvisorLogListQuery(t *testing.T) {
+	RegisterTestingT(t)
+
+	spec, err := queryspec.Parse(url.Values{}, advisorlog.ListQuery)
+	Expect(err).To(BeNil())
+	Expect(spec.Page).To(Equal(1))
+	Expect(spec.PageSize).To(Equal(queryspec.DefaultPageSize))
+	Expect(spec.ToOrder()).To(Equal("advisor_logs.created_at desc, advisor_logs.id desc"))
+
+	spec, err = queryspec.Parse(url.Values{"page": {"3"}, "page_size": {"500"}, "status": {"Completed"}, "body": {"x"}}, advisorlog.ListQuery)
+	Expect(err).To(BeNil())
+	Expect(spec.Page).To(Equal(3))
+	Expect(spec.PageSize).To(Equal(queryspec.MaxPageSize))
+	Expect(spec.Filters).To(Equal(map[string]string{"status": "Completed"}))
+
+	_, err = queryspec.Parse(url.Values{"page": {"-2"}}, advisorlog.ListQuery)
+	Expect(err).To(Equal(queryspec.ErrInvalidPage))
+
+	// private_notes ไม่อยู่ใน whitelist
+	_, err = queryspec.Parse(url.Values{"sort": {"private_notes"}}, advisorlog.ListQuery)
+	Expect(err).To(MatchError(ContainSubstring(`cannot sort by "private_notes"`)))
+}
+
+func TestAdvisorLogEditor(t *testing.T) {
+	RegisterTestingT(t)
+
+	appt := &entity.Appointment{StudentUserID: 10, AdvisorUserID: 20}
+	log := entity.AdvisorLog{Status: "Completed", Appointment: appt}
+	draft := entity.AdvisorLog{Status: "Draft", Appointment: appt}
+
+	t.Run("only the appointment's advisor edits content", func(t *testing.T) {
+		Expect(advisorlog.CheckEditor(log, 20, "advisor")).To(Succeed())
+		Expect(advisorlog.CheckEditor(draft, 20, "advisor")).To(Succeed())
+		Expect(advisorlog.CheckEditor(log, 21, "advisor")).To(Equal(advisorlog.ErrForbidden))
+		// admin ผ่านที่นี่ ขอบเขตหน่วยงานตรวจใน Update
+		Expect(advisorlog.CheckEditor(log, 1, "admin")).To(Succeed())
+		Expect(advisorlog.CheckEditor(log, 1, "")).To(Equal(advisorlog.ErrForbidden))
+	})
+
+	t.Run("the appointment's student cannot edit", func(t *testing.T) {
+		Expect(advisorlog.CheckEditor(log, 10, "student")).To(Equal(advisorlog.ErrForbidden))
+		Expect(advisorlog.CheckEditor(log, 11, "student")).To(Equal(advisorlog.ErrForbidden))
+	})
+
+	t.Run("a hidden draft stays not found for the student", func(t *testing.T) {
+		Expect(advisorlog.CheckEditor(draft, 10, "student")).To(Equal(advisorlog.ErrAdvisorLogNotFound))
+	})
+}
+
+func TestAdminDepartmentScope(t *testing.T) {
+	RegisterTestingT(t)
+
+	dept := uint(5)
+	appointments := func(departmentID *uint) string {
+		return searchSQL(t, func(tx *gorm.DB) (*gorm.DB, error) {
+			return advisorlog.InDepartment(tx.Table("appointments"), departmentID), nil
+		})
+	}
+
+	Expect(appointments(&dept)).To(ContainSubstring("appointments.student_user_id IN (SELECT id FROM users WHERE department_id = 5)"))
+	// admin ที่ไม่สังกัดหน่วยงานเห็นทั้งหมด
+	Expect(appointments(nil)).NotTo(ContainSubstring("department_id"))
+}
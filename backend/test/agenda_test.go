@@ -0,0 +1,91 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"backend/internal/app/dto"
+	"backend/internal/app/entity"
+	"backend/internal/service/agenda"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestAgendaHelpers(t *testing.T) {
+	RegisterTestingT(t)
+	bkk, _ := time.LoadLocation("Asia/Bangkok")
+
+	t.Run("range defaults to 30 days from today in Bangkok", func(t *testing.T) {
+		// 2026-03-01 20:00 UTC = 2026-03-02 03:00 เวลาไทย
+		now := time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC)
+		from, to, err := agenda.ParseRange("", "", now)
+		Expect(err).To(BeNil())
+		Expect(from).To(Equal(time.Date(2026, 3, 2, 0, 0, 0, 0, bkk)))
+		Expect(to).To(Equal(time.Date(2026, 4, 1, 0, 0, 0, 0, bkk)))
+	})
+
+	t.Run("range is inclusive and validated", func(t *testing.T) {
+		from, to, err := agenda.ParseRange("2026-03-01", "2026-03-01", time.Now())
+		Expect(err).To(BeNil())
+		Expect(to.Sub(from)).To(Equal(24 * time.Hour))
+
+		_, _, err = agenda.ParseRange("2026-03-02", "2026-03-01", time.Now())
+		Expect(err).To(Equal(agenda.ErrInvalidRange))
+		_, _, err = agenda.ParseRange("2026-01-01", "2027-06-01", time.Now())
+		Expect(err).To(Equal(agenda.ErrInvalidRange))
+		_, _, err = agenda.ParseRange("01/03/2026", "", time.Now())
+		Expect(err).To(Equal(agenda.ErrInvalidRange))
+	})
+
+	t.Run("non-availability expands weekly inside the range", func(t *testing.T) {
+		clock := func(h int) time.Time { return time.Date(2000, 1, 1, h, 0, 0, 0, bkk) }
+		blocks := []entity.AdvisorNonAvailabillity{
+			{
+				Description: "สอน",
+				Day:         time.Date(2026, 2, 2, 0, 0, 0, 0, bkk), // จันทร์
+				IsRecurring: true,
+				TimeNonAvailabillity: entity.TimeNonAvailabillity{
+					Subjects: "SE101", StartTime: clock(9), EndTime: clock(12),
+				},
+			},
+			{
+				Day:                  time.Date(2026, 3, 4, 0, 0, 0, 0, bkk),
+				TimeNonAvailabillity: entity.TimeNonAvailabillity{StartTime: clock(13), EndTime: clock(15)},
+			},
+			{
+				// นอกช่วง
+				Day:                  time.Date(2026, 4, 1, 0, 0, 0, 0, bkk),
+				TimeNonAvailabillity: entity.TimeNonAvailabillity{StartTime: clock(13), EndTime: clock(15)},
+			},
+		}
+		from, to, _ := agenda.ParseRange("2026-03-01", "2026-03-16", time.Now())
+		items := agenda.ExpandUnavailable(blocks, from, to)
+		agenda.Sort(items)
+
+		starts := []string{}
+		for _, it := range items {
+			Expect(it.Type).To(Equal(agenda.TypeUnavailable))
+			starts = append(starts, it.Start)
+		}
+		Expect(starts).To(Equal([]string{
+			"2026-03-02T09:00:00+07:00",
+			"2026-03-04T13:00:00+07:00",
+			"2026-03-09T09:00:00+07:00",
+			"2026-03-16T09:00:00+07:00",
+		}))
+		Expect(items[0].Title).To(Equal("สอน"))
+		Expect(items[0].Detail).To(Equal("SE101"))
+		Expect(*items[0].End).To(Equal("2026-03-02T12:00:00+07:00"))
+		Expect(items[1].Title).To(Equal("ไม่ว่าง"))
+	})
+
+	t.Run("items sort by start then type", func(t *testing.T) {
+		items := []dto.AgendaItem{
+			{Type: agenda.TypeReportDue, ID: 1, Start: "2026-03-02T09:00:00+07:00"},
+			{Type: agenda.TypeAppointment, ID: 2, Start: "2026-03-02T09:00:00+07:00"},
+			{Type: agenda.TypeAcademicEvent, ID: 3, Start: "2026-03-01T00:00:00+07:00"},
+		}
+		agenda.Sort(items)
+		Expect([]uint{items[0].ID, items[1].ID, items[2].ID}).To(Equal([]uint{3, 2, 1}))
+	})
+}
@@ -5,7 +5,9 @@ import (
 	"testing"
 	"time"
 
+	"backend/internal/app/dto"
 	"backend/internal/app/entity"
+	"backend/internal/app/queryspec"
 	"backend/internal/app/repository"
 	approval "backend/internal/service/approval"
 
@@ -120,13 +122,16 @@ func (f *fakeAppointmentRepo) CreateStatusHistory(h *entity.StatusHistory) error
 	return nil
 }
 
-func (f *fakeAppointmentRepo) ListPendingByAdvisor(advisorID uint) ([]entity.Appointment, error) {
-	return nil, nil
+func (f *fakeAppointmentRepo) ListPendingByAdvisor(advisorID uint, spec queryspec.Spec) ([]entity.Appointment, dto.PageMeta, error) {
+	return nil, dto.PageMeta{}, nil
 }
-func (f *fakeAppointmentRepo) ListDoneByAdvisor(advisorID uint) ([]entity.Appointment, error) {
-	return nil, nil
+func (f *fakeAppointmentRepo) ListDoneByAdvisor(advisorID uint, spec queryspec.Spec) ([]entity.Appointment, dto.PageMeta, error) {
+	return nil, dto.PageMeta{}, nil
+}
+func (f *fakeAppointmentRepo) ListAllByAdvisor(advisorID uint, spec queryspec.Spec) ([]entity.Appointment, dto.PageMeta, error) {
+	return nil, dto.PageMeta{}, nil
 }
-func (f *fakeAppointmentRepo) ListAllByAdvisor(advisorID uint) ([]entity.Appointment, error) {
+func (f *fakeAppointmentRepo) GetFollowUpChain(id uint) ([]repository.ChainLink, error) {
 	return nil, nil
 }
 
@@ -0,0 +1,100 @@
+package test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"backend/internal/app/dto"
+	"backend/internal/app/entity"
+	"backend/internal/app/queryspec"
+	"backend/internal/service/booking"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestAppointmentAccess(t *testing.T) {
+	RegisterTestingT(t)
+
+	appt := &entity.Appointment{AdvisorUserID: 10, StudentUserID: 20}
+
+	t.Run("parties of the appointment", func(t *testing.T) {
+		Expect(booking.CanAccess(appt, 10, "Advisor")).To(BeTrue())
+		Expect(booking.CanAccess(appt, 20, "Student")).To(BeTrue())
+		// admin ต้องผ่านการตรวจหน่วยงานใน Authorize
+		Expect(booking.CanAccess(appt, 99, "Admin")).To(BeFalse())
+
+		svc := booking.New(nil, nil)
+		Expect(svc.Authorize(context.Background(), appt, 10, "Advisor")).To(Succeed())
+		Expect(svc.Authorize(context.Background(), appt, 11, "Advisor")).To(Equal(booking.ErrForbidden))
+	})
+
+	t.Run("other advisors and students are denied", func(t *testing.T) {
+		Expect(booking.CanAccess(appt, 11, "Advisor")).To(BeFalse())
+		Expect(booking.CanAccess(appt, 21, "Student")).To(BeFalse())
+		// id ตรงแต่ role ไม่ตรงฝั่ง
+		Expect(booking.CanAccess(appt, 10, "Student")).To(BeFalse())
+		Expect(booking.CanAccess(appt, 20, "")).To(BeFalse())
+	})
+
+	t.Run("only students can request", func(t *testing.T) {
+		svc := booking.New(nil, nil)
+		_, err := svc.Request(context.Background(), dto.AppointmentRequestReq{TopicID: 1, CategoryID: 1}, 10, "Advisor")
+		Expect(err).To(MatchError(booking.ErrStudentOnly))
+	})
+}
+
+func TestStudentAppointmentView(t *testing.T) {
+	RegisterTestingT(t)
+
+	appt := entity.Appointment{
+		Description:       "ขอปรึกษาแผนการเรียน",
+		AdvisorUserID:     10,
+		AdvisorUser:       entity.User{FirstName: "สมชาย", LastName: "ใจดี"},
+		Topic:             entity.AppointmentTopic{Topic: "วางแผนการเรียน"},
+		AppointmentStatus: entity.AppointmentStatus{StatusCode: "RESCHEDULE", StatusName: "เสนอเวลาใหม่"},
+	}
+	appt.ID = 5
+
+	item := booking.ToStudentItem(appt)
+	Expect(item.AdvisorName).To(Equal("สมชาย ใจดี"))
+	Expect(item.StatusCode).To(Equal("RESCHEDULE"))
+	Expect(item.HasAdvisorLog).To(BeFalse())
+
+	// Draft ยังไม่นับเป็นบันทึกที่นักศึกษาเห็น
+	appt.AdvisorLog = &entity.AdvisorLog{Status: "Draft"}
+	Expect(booking.ToStudentItem(appt).HasAdvisorLog).To(BeFalse())
+	appt.AdvisorLog.Status = "Completed"
+	Expect(booking.ToStudentItem(appt).HasAdvisorLog).To(BeTrue())
+
+	h := entity.StatusHistory{
+		ChangedByUserID: 10,
+		ChangedByUser:   entity.User{FirstName: "สมชาย", LastName: "ใจดี", Role: &entity.Role{Role: "Advisor"}},
+		FromStatus:      entity.AppointmentStatus{StatusCode: "PENDING"},
+		ToStatus:        entity.AppointmentStatus{StatusCode: "RESCHEDULE"},
+		Action:          entity.ApprovalAction{ActionCode: "RESCHEDULE"},
+		Reason:          "ติดประชุมคณะ",
+		ChangedAt:       time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC),
+	}
+	timeline := booking.ToTimeline([]entity.StatusHistory{h})
+	Expect(timeline).To(HaveLen(1))
+	Expect(timeline[0].FromStatusCode).To(Equal("PENDING"))
+	Expect(timeline[0].ToStatusCode).To(Equal("RESCHEDULE"))
+	Expect(timeline[0].Reason).To(Equal("ติดประชุมคณะ"))
+	Expect(timeline[0].ChangedByName).To(Equal("สมชาย ใจดี"))
+	Expect(timeline[0].ChangedByRole).To(Equal("Advisor"))
+	Expect(timeline[0].ChangedAt).To(Equal("2025-03-01 09:30:00"))
+
+	// รายการนัดของนักศึกษาแบ่งหน้าเหมือน list อื่น
+	spec, err := queryspec.Parse(url.Values{"status": {"pending"}, "sort": {"start_at"}}, booking.MineListQuery)
+	Expect(err).To(BeNil())
+	Expect(spec.PageSize).To(Equal(queryspec.DefaultPageSize))
+	Expect(spec.Filter("status")).To(Equal("pending"))
+	Expect(spec.ToOrder()).To(Equal("start_at asc, id desc"))
+	_, err = queryspec.Parse(url.Values{"sort": {"advisor_user_id"}}, booking.MineListQuery)
+	Expect(err).To(BeAssignableToTypeOf(&queryspec.SortError{}))
+
+	_, err = booking.New(nil, nil).ListMine(context.Background(), 10, "Advisor", spec)
+	Expect(err).To(MatchError(booking.ErrStudentOnly))
+}
@@ -0,0 +1,115 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"backend/internal/service/calendarimport"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestCalendarImportParse(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("format is detected from file extension", func(t *testing.T) {
+		f, err := calendarimport.DetectFormat("holidays-2027.ICS")
+		Expect(err).To(BeNil())
+		Expect(f).To(Equal(calendarimport.FormatICS))
+
+		f, err = calendarimport.DetectFormat("calendar.csv")
+		Expect(err).To(BeNil())
+		Expect(f).To(Equal(calendarimport.FormatCSV))
+
+		_, err = calendarimport.DetectFormat("calendar.xlsx")
+		Expect(err).To(MatchError(calendarimport.ErrUnsupportedFormat))
+	})
+
+	t.Run("event types accept Thai and English names", func(t *testing.T) {
+		for raw, want := range map[string]string{
+			"Public Holiday": "holiday",
+			"วันหยุด":        "holiday",
+			"สอบ":            "exam",
+			"Activity":       "activity",
+		} {
+			got, ok := calendarimport.NormalizeEventType(raw)
+			Expect(ok).To(BeTrue(), raw)
+			Expect(got).To(Equal(want), raw)
+		}
+
+		_, ok := calendarimport.NormalizeEventType("meeting")
+		Expect(ok).To(BeFalse())
+	})
+
+	t.Run("CSV with BOM, Buddhist year and timed rows", func(t *testing.T) {
+		csv := "\xEF\xBB\xBFtitle,type,start_date,end_date,start_time,end_time\n" +
+			"วันสงกรานต์,holiday,13/04/2570,15/04/2570,,\n" +
+			"สอบกลางภาค,exam,2027-08-02,2027-08-02,09:00,12:00\n" +
+			",activity,2027-08-03,,,\n"
+
+		events, err := calendarimport.ParseCSV(strings.NewReader(csv))
+		Expect(err).To(BeNil())
+		Expect(events).To(HaveLen(3))
+
+		Expect(events[0].Err).To(BeNil())
+		Expect(events[0].AllDay).To(BeTrue())
+		Expect(events[0].Start.Format("2006-01-02 15:04")).To(Equal("2027-04-13 00:00"))
+		Expect(events[0].End.Format("2006-01-02 15:04:05")).To(Equal("2027-04-15 23:59:59"))
+
+		Expect(events[1].Err).To(BeNil())
+		Expect(events[1].RawType).To(Equal("exam"))
+		Expect(events[1].Start.Format("15:04")).To(Equal("09:00"))
+		Expect(events[1].End.Format("15:04")).To(Equal("12:00"))
+
+		// แถวที่ไม่มีชื่อ ต้องไม่ทำให้ทั้งไฟล์พัง
+		Expect(events[2].Err).NotTo(BeNil())
+		Expect(events[2].Line).To(Equal(4))
+	})
+
+	t.Run("CSV without required columns is rejected", func(t *testing.T) {
+		_, err := calendarimport.ParseCSV(strings.NewReader("foo,bar\n1,2\n"))
+		Expect(err).To(MatchError(calendarimport.ErrMissingColumn))
+	})
+
+	t.Run("ICS all-day, timed, folded and cancelled events", func(t *testing.T) {
+		ics := "BEGIN:VCALENDAR\r\n" +
+			"BEGIN:VEVENT\r\n" +
+			"SUMMARY:วันหยุด\\, ชดเชย\r\n" +
+			"CATEGORIES:Public Holiday\r\n" +
+			"DTSTART;VALUE=DATE:20270413\r\n" +
+			"DTEND;VALUE=DATE:20270416\r\n" +
+			"END:VEVENT\r\n" +
+			"BEGIN:VEVENT\r\n" +
+			"SUMMARY:สอบปลาย\r\n" +
+			" ภาค\r\n" +
+			"DTSTART:20271101T020000Z\r\n" +
+			"DTEND;TZID=Asia/Bangkok:20271101T120000\r\n" +
+			"END:VEVENT\r\n" +
+			"BEGIN:VEVENT\r\n" +
+			"SUMMARY:ยกเลิก\r\n" +
+			"STATUS:CANCELLED\r\n" +
+			"DTSTART;VALUE=DATE:20270501\r\n" +
+			"END:VEVENT\r\n" +
+			"END:VCALENDAR\r\n"
+
+		events, err := calendarimport.ParseICS(strings.NewReader(ics))
+		Expect(err).To(BeNil())
+		Expect(events).To(HaveLen(2))
+
+		Expect(events[0].Title).To(Equal("วันหยุด, ชดเชย"))
+		Expect(events[0].RawType).To(Equal("Public Holiday"))
+		Expect(events[0].AllDay).To(BeTrue())
+		// DTEND แบบ DATE เป็น exclusive -> วันสุดท้ายคือ 15
+		Expect(events[0].End.Format("2006-01-02")).To(Equal("2027-04-15"))
+
+		Expect(events[1].Err).To(BeNil())
+		Expect(events[1].Title).To(Equal("สอบปลายภาค"))
+		Expect(events[1].Start.Format("2006-01-02 15:04")).To(Equal("2027-11-01 09:00"))
+		Expect(events[1].End.Format("15:04")).To(Equal("12:00"))
+	})
+
+	t.Run("ICS without events is rejected", func(t *testing.T) {
+		_, err := calendarimport.ParseICS(strings.NewReader("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"))
+		Expect(err).To(MatchError(calendarimport.ErrEmptyFile))
+	})
+}
@@ -0,0 +1,112 @@
+package test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"backend/internal/app/entity"
+	"backend/internal/service/export"
+
+	. "github.com/onsi/gomega"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestExportWriter(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("CSV starts with UTF-8 BOM and keeps Thai text", func(t *testing.T) {
+		var buf bytes.Buffer
+		w, err := export.NewRowWriter(export.FormatCSV, &buf, "test")
+		Expect(err).To(BeNil())
+
+		Expect(w.WriteHeader([]string{"ชื่อ", "สถานะ"})).To(Succeed())
+		Expect(w.WriteRow([]string{"สมชาย, ใจดี", "อนุมัติแล้ว"})).To(Succeed())
+		Expect(w.Close()).To(Succeed())
+
+		out := buf.Bytes()
+		Expect(out[:3]).To(Equal([]byte{0xEF, 0xBB, 0xBF}))
+		Expect(string(out[3:])).To(Equal("ชื่อ,สถานะ\n\"สมชาย, ใจดี\",อนุมัติแล้ว\n"))
+	})
+
+	t.Run("XLSX is readable by excelize", func(t *testing.T) {
+		var buf bytes.Buffer
+		w, err := export.NewRowWriter(export.FormatXLSX, &buf, "appointments")
+		Expect(err).To(BeNil())
+
+		Expect(w.WriteHeader([]string{"รหัส", "หัวข้อ"})).To(Succeed())
+		Expect(w.WriteRow([]string{"1", "ลงทะเบียนเรียน"})).To(Succeed())
+		Expect(w.Close()).To(Succeed())
+
+		f, err := excelize.OpenReader(&buf)
+		Expect(err).To(BeNil())
+		defer f.Close()
+
+		rows, err := f.GetRows("appointments")
+		Expect(err).To(BeNil())
+		Expect(rows).To(Equal([][]string{{"รหัส", "หัวข้อ"}, {"1", "ลงทะเบียนเรียน"}}))
+	})
+
+	t.Run("values that Excel would run as formulas are escaped", func(t *testing.T) {
+		row := []string{"=HYPERLINK(\"http://x\")", "+1", "-2", "@SUM(A1)", "\tx", "\rx", "ปกติ", ""}
+		want := []string{"'=HYPERLINK(\"http://x\")", "'+1", "'-2", "'@SUM(A1)", "'\tx", "'\rx", "ปกติ", ""}
+
+		var csvBuf bytes.Buffer
+		w, err := export.NewRowWriter(export.FormatCSV, &csvBuf, "test")
+		Expect(err).To(BeNil())
+		Expect(w.WriteRow(row)).To(Succeed())
+		Expect(w.Close()).To(Succeed())
+
+		records, err := csv.NewReader(bytes.NewReader(csvBuf.Bytes()[3:])).ReadAll()
+		Expect(err).To(BeNil())
+		Expect(records).To(Equal([][]string{want}))
+
+		var xlsxBuf bytes.Buffer
+		w, err = export.NewRowWriter(export.FormatXLSX, &xlsxBuf, "test")
+		Expect(err).To(BeNil())
+		Expect(w.WriteRow(row)).To(Succeed())
+		Expect(w.Close()).To(Succeed())
+
+		f, err := excelize.OpenReader(&xlsxBuf)
+		Expect(err).To(BeNil())
+		defer f.Close()
+		rows, err := f.GetRows("test")
+		Expect(err).To(BeNil())
+		Expect(rows[0]).To(Equal(want[:len(rows[0])]))
+	})
+
+	t.Run("invalid format is rejected", func(t *testing.T) {
+		_, err := export.NormalizeFormat("pdf")
+		Expect(err).To(Equal(export.ErrInvalidFormat))
+
+		f, err := export.NormalizeFormat("")
+		Expect(err).To(BeNil())
+		Expect(f).To(Equal(export.FormatCSV))
+	})
+}
+
+func TestExportColumnSelection(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("empty selection returns all columns", func(t *testing.T) {
+		cols, err := export.SelectColumns(export.AppointmentColumns, "")
+		Expect(err).To(BeNil())
+		Expect(cols).To(HaveLen(len(export.AppointmentColumns)))
+	})
+
+	t.Run("selection keeps requested order", func(t *testing.T) {
+		cols, err := export.SelectColumns(export.AppointmentColumns, "status, id")
+		Expect(err).To(BeNil())
+		Expect(export.ColumnKeys(cols)).To(Equal([]string{"status", "id"}))
+
+		appt := entity.Appointment{AppointmentStatus: entity.AppointmentStatus{StatusName: "รอพิจารณา"}}
+		appt.ID = 7
+		Expect(cols[0].Value(appt)).To(Equal("รอพิจารณา"))
+		Expect(cols[1].Value(appt)).To(Equal("7"))
+	})
+
+	t.Run("unknown column is rejected", func(t *testing.T) {
+		_, err := export.SelectColumns(export.AppointmentColumns, "id,password_hash")
+		Expect(err).To(MatchError(ContainSubstring("unknown column: password_hash")))
+	})
+}
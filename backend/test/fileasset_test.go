@@ -0,0 +1,185 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"testing"
+
+	"backend/internal/app/entity"
+	"backend/internal/service/fileasset"
+	"backend/internal/service/storage"
+
+	. "github.com/onsi/gomega"
+)
+
+// newFileHeader สร้าง multipart.FileHeader จำลองเหมือนที่ gin ได้จาก request
+func newFileHeader(t *testing.T, name, contentType string, content []byte) *multipart.FileHeader {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Disposition", `form-data; name="files"; filename="`+name+`"`)
+	if contentType != "" {
+		h.Set("Content-Type", contentType)
+	}
+	part, err := w.CreatePart(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = part.Write(content)
+	_ = w.Close()
+
+	form, err := multipart.NewReader(&body, w.Boundary()).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return form.File["files"][0]
+}
+
+func TestFileAssetStore(t *testing.T) {
+	RegisterTestingT(t)
+
+	dir := t.TempDir()
+	svc := fileasset.New(nil, storage.NewLocal(dir))
+
+	t.Run("one asset per file with size, sha256 and uploader", func(t *testing.T) {
+		content := []byte("%PDF-1.4\n% รายงานความก้าวหน้า\n%%EOF\n")
+		sum := sha256.Sum256(content)
+
+		// ชื่อไฟล์มี comma ต้องเก็บได้ครบ (แบบเดิมจะพัง)
+		fh := newFileHeader(t, "report, final.pdf", "application/pdf", content)
+
+		assets, err := svc.StoreAll([]*multipart.FileHeader{fh}, 7)
+		Expect(err).To(BeNil())
+		Expect(assets).To(HaveLen(1))
+
+		a := assets[0]
+		Expect(a.OriginalName).To(Equal("report, final.pdf"))
+		Expect(a.MimeType).To(Equal("application/pdf"))
+		Expect(a.Size).To(Equal(int64(len(content))))
+		Expect(a.SHA256).To(Equal(hex.EncodeToString(sum[:])))
+		Expect(a.UploaderID).NotTo(BeNil())
+		Expect(*a.UploaderID).To(Equal(uint(7)))
+
+		obj, _, err := svc.Open(context.Background(), a)
+		Expect(err).To(BeNil())
+		Expect(io.ReadAll(obj)).To(Equal(content))
+		obj.Close()
+
+		svc.Discard(assets)
+		_, _, err = svc.Open(context.Background(), a)
+		Expect(err).To(MatchError(fileasset.ErrFileNotFound))
+	})
+
+	t.Run("mime type falls back to extension", func(t *testing.T) {
+		fh := newFileHeader(t, "photo.PNG", "", []byte{0x89, 'P', 'N', 'G'})
+		Expect(fileasset.DetectMimeType(fh)).To(Equal("image/png"))
+	})
+
+	t.Run("response keeps upload order as download index", func(t *testing.T) {
+		out := fileasset.ToResp([]entity.FileAssets{
+			{OriginalName: "a.pdf", Position: 0},
+			{OriginalName: "b,c.pdf", Position: 1},
+		})
+		Expect(out).To(HaveLen(2))
+		Expect(out[1].Index).To(Equal(1))
+		Expect(out[1].Name).To(Equal("b,c.pdf"))
+	})
+}
+
+func TestUploadPolicy(t *testing.T) {
+	RegisterTestingT(t)
+
+	pdf := []byte("%PDF-1.4\n1 0 obj\n<<>>\nendobj\n%%EOF\n")
+	png := []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n', 0, 0, 0, 0x0d, 'I', 'H', 'D', 'R'}
+	exe := append([]byte("MZ\x90\x00\x03\x00\x00\x00\x04\x00\x00\x00\xff\xff\x00\x00"), make([]byte, 64)...)
+
+	uploadErr := func(err error) *fileasset.UploadError {
+		ue, ok := err.(*fileasset.UploadError)
+		Expect(ok).To(BeTrue(), "expected *UploadError, got %v", err)
+		return ue
+	}
+
+	t.Run("type is detected from content not from extension", func(t *testing.T) {
+		// ไฟล์ .exe ที่เปลี่ยนชื่อเป็น .pdf และแอบอ้าง Content-Type
+		fh := newFileHeader(t, "report.pdf", "application/pdf", exe)
+		ue := uploadErr(fileasset.PolicyAdvisorLog.Check([]*multipart.FileHeader{fh}))
+		Expect(ue.Code).To(Equal(fileasset.CodeUnsupportedType))
+		Expect(ue.File).To(Equal("report.pdf"))
+
+		ok := newFileHeader(t, "scan.bin", "", pdf)
+		Expect(fileasset.PolicyAdvisorLog.Check([]*multipart.FileHeader{ok})).To(Succeed())
+		Expect(fileasset.DetectMimeType(ok)).To(Equal("application/pdf"))
+	})
+
+	t.Run("html, svg and xml are rejected even though they are text", func(t *testing.T) {
+		for name, body := range map[string]string{
+			"x.txt": "<!DOCTYPE html><html><body><script>alert(1)</script></body></html>",
+			"x.svg": `<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`,
+			"x.xml": `<?xml version="1.0"?><note><to>a</to></note>`,
+		} {
+			ue := uploadErr(fileasset.PolicyAdvisorLog.Check([]*multipart.FileHeader{newFileHeader(t, name, "text/plain", []byte(body))}))
+			Expect(ue.Code).To(Equal(fileasset.CodeUnsupportedType), name)
+		}
+
+		plain := newFileHeader(t, "note.txt", "", []byte("บันทึกการปรึกษา\nหัวข้อ: โครงงาน\n"))
+		Expect(fileasset.PolicyAdvisorLog.Check([]*multipart.FileHeader{plain})).To(Succeed())
+		csv := newFileHeader(t, "grades.csv", "", []byte("id,name,grade\n1,a,A\n2,b,B\n"))
+		Expect(fileasset.PolicyAdvisorLog.Check([]*multipart.FileHeader{csv})).To(Succeed())
+	})
+
+	t.Run("only allowlisted images are served inline", func(t *testing.T) {
+		Expect(fileasset.InlineSafe("image/png")).To(BeTrue())
+		Expect(fileasset.InlineSafe("image/jpeg")).To(BeTrue())
+		Expect(fileasset.InlineSafe("image/svg+xml")).To(BeFalse())
+		Expect(fileasset.InlineSafe("text/html; charset=utf-8")).To(BeFalse())
+		Expect(fileasset.InlineSafe("application/pdf")).To(BeFalse())
+		Expect(fileasset.InlineSafe("")).To(BeFalse())
+	})
+
+	t.Run("profile image accepts images only", func(t *testing.T) {
+		Expect(fileasset.PolicyProfileImage.Check([]*multipart.FileHeader{newFileHeader(t, "me.png", "", png)})).To(Succeed())
+
+		ue := uploadErr(fileasset.PolicyProfileImage.Check([]*multipart.FileHeader{newFileHeader(t, "me.png", "image/png", pdf)}))
+		Expect(ue.Code).To(Equal(fileasset.CodeUnsupportedType))
+		Expect(ue.Allowed).To(ContainElement("image/png"))
+	})
+
+	t.Run("size and count limits", func(t *testing.T) {
+		p := fileasset.Policy{AllowedTypes: []string{"application/pdf"}, MaxFileSize: 64, MaxFiles: 2, MaxRequestSize: 60}
+
+		big := newFileHeader(t, "big.pdf", "", append(append([]byte{}, pdf...), make([]byte, 64)...))
+		ue := uploadErr(p.Check([]*multipart.FileHeader{big}))
+		Expect(ue.Code).To(Equal(fileasset.CodeFileTooLarge))
+		Expect(ue.Limit).To(Equal(int64(64)))
+
+		a := newFileHeader(t, "a.pdf", "", pdf)
+		ue = uploadErr(p.Check([]*multipart.FileHeader{a, a, a}))
+		Expect(ue.Code).To(Equal(fileasset.CodeTooManyFiles))
+
+		ue = uploadErr(p.Check([]*multipart.FileHeader{a, a, a}[:2]))
+		Expect(ue.Code).To(Equal(fileasset.CodeRequestTooLarge))
+
+		ue = uploadErr(p.Check([]*multipart.FileHeader{newFileHeader(t, "empty.pdf", "", nil)}))
+		Expect(ue.Code).To(Equal(fileasset.CodeEmptyFile))
+	})
+
+	t.Run("stored key uses detected extension", func(t *testing.T) {
+		svc := fileasset.New(nil, storage.NewLocal(t.TempDir()))
+		asset, err := svc.Store(newFileHeader(t, "photo.exe", "", png), 1)
+		Expect(err).To(BeNil())
+		Expect(asset.MimeType).To(Equal("image/png"))
+		Expect(asset.StoragePath).To(HaveSuffix(".png"))
+		svc.Discard([]entity.FileAssets{*asset})
+	})
+
+	t.Run("quota from env", func(t *testing.T) {
+		t.Setenv("UPLOAD_USER_QUOTA_MB", "3")
+		Expect(fileasset.UserQuota()).To(Equal(int64(3 << 20)))
+	})
+}
@@ -0,0 +1,58 @@
+package test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"backend/internal/app/dto"
+	"backend/internal/app/entity"
+	"backend/internal/service/advisorlog"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestFollowUpTime(t *testing.T) {
+	RegisterTestingT(t)
+
+	now := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	start, end, err := advisorlog.ParseFollowUpTime("2025-03-15T10:00:00+07:00", "2025-03-15T10:30:00+07:00", now)
+	Expect(err).To(BeNil())
+	Expect(end.Sub(start)).To(Equal(30 * time.Minute))
+
+	_, _, err = advisorlog.ParseFollowUpTime("2025-03-15T10:30:00+07:00", "2025-03-15T10:00:00+07:00", now)
+	Expect(err).To(Equal(advisorlog.ErrInvalidFollowUpTime))
+
+	// ย้อนหลังไม่ได้
+	_, _, err = advisorlog.ParseFollowUpTime("2025-02-28T10:00:00+07:00", "2025-02-28T11:00:00+07:00", now)
+	Expect(err).To(Equal(advisorlog.ErrInvalidFollowUpTime))
+
+	_, _, err = advisorlog.ParseFollowUpTime("15/03/2025 10:00", "", now)
+	Expect(err).To(Equal(advisorlog.ErrInvalidFollowUpTime))
+}
+
+func TestAppointmentDetailJSON(t *testing.T) {
+	RegisterTestingT(t)
+
+	parent := uint(10)
+	appt := &entity.Appointment{Description: "นัดติดตามผล", ParentAppointmentID: &parent}
+	appt.ID = 11
+
+	raw, err := json.Marshal(dto.AppointmentDetailResp{
+		Appointment: appt,
+		FollowUpChain: []dto.AppointmentChainItem{
+			{ID: 10, Depth: -1},
+			{ID: 11, ParentAppointmentID: &parent, Depth: 0},
+		},
+	})
+	Expect(err).To(BeNil())
+
+	var out map[string]any
+	Expect(json.Unmarshal(raw, &out)).To(Succeed())
+	// ฟิลด์เดิมของนัดยังอยู่ระดับบนสุด
+	Expect(out["ID"]).To(BeNumerically("==", 11))
+	Expect(out["description"]).To(Equal("นัดติดตามผล"))
+	Expect(out["parent_appointment_id"]).To(BeNumerically("==", 10))
+	Expect(out["follow_up_chain"]).To(HaveLen(2))
+}
@@ -0,0 +1,98 @@
+package test
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"backend/internal/app/entity"
+	"backend/internal/service/calendarfeed"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestICalendar(t *testing.T) {
+	RegisterTestingT(t)
+
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	t.Run("event keeps stable UID and SEQUENCE", func(t *testing.T) {
+		cal := calendarfeed.Calendar{Events: []calendarfeed.Event{{
+			UID:      calendarfeed.AppointmentUID(7),
+			Sequence: 2,
+			Summary:  "นัดปรึกษา",
+			Start:    start,
+			End:      end,
+		}}}
+		out := string(cal.Bytes())
+
+		Expect(out).To(HavePrefix("BEGIN:VCALENDAR\r\n"))
+		Expect(out).To(HaveSuffix("END:VCALENDAR\r\n"))
+		Expect(out).To(ContainSubstring("UID:appointment-7@advisor.sut.ac.th\r\n"))
+		Expect(out).To(ContainSubstring("SEQUENCE:2\r\n"))
+		Expect(out).To(ContainSubstring("DTSTART:20250602T090000Z\r\n"))
+		Expect(out).To(ContainSubstring("DTEND:20250602T100000Z\r\n"))
+	})
+
+	t.Run("text values are escaped", func(t *testing.T) {
+		cal := calendarfeed.Calendar{Events: []calendarfeed.Event{{
+			UID:         "x",
+			Summary:     "a,b;c\\d",
+			Description: "บรรทัด1\nบรรทัด2",
+			Start:       start,
+			End:         end,
+		}}}
+		out := string(cal.Bytes())
+
+		Expect(out).To(ContainSubstring(`SUMMARY:a\,b\;c\\d`))
+		Expect(out).To(ContainSubstring(`DESCRIPTION:บรรทัด1\nบรรทัด2`))
+	})
+
+	t.Run("long Thai lines are folded without splitting characters", func(t *testing.T) {
+		cal := calendarfeed.Calendar{Events: []calendarfeed.Event{{
+			UID:     "x",
+			Summary: strings.Repeat("ปรึกษาอาจารย์", 20),
+			Start:   start,
+			End:     end,
+		}}}
+		out := string(cal.Bytes())
+
+		for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+			Expect(len(line)).To(BeNumerically("<=", 75))
+			Expect(utf8.ValidString(line)).To(BeTrue())
+		}
+		// ต่อบรรทัดกลับแล้วต้องได้ข้อความเดิม
+		unfolded := strings.ReplaceAll(out, "\r\n ", "")
+		Expect(unfolded).To(ContainSubstring("SUMMARY:" + strings.Repeat("ปรึกษาอาจารย์", 20) + "\r\n"))
+	})
+
+	t.Run("all-day events use DATE values", func(t *testing.T) {
+		cal := calendarfeed.Calendar{Events: []calendarfeed.Event{{
+			UID:     calendarfeed.AcademicEventUID(3),
+			Summary: "วันหยุด",
+			AllDay:  true,
+			Start:   time.Date(2025, 4, 13, 0, 0, 0, 0, time.UTC),
+			End:     time.Date(2025, 4, 16, 0, 0, 0, 0, time.UTC),
+		}}}
+		out := string(cal.Bytes())
+
+		Expect(out).To(ContainSubstring("DTSTART;VALUE=DATE:20250413\r\n"))
+		Expect(out).To(ContainSubstring("DTEND;VALUE=DATE:20250416\r\n"))
+	})
+
+	t.Run("rescheduled appointment stays in the feed as tentative at the bumped sequence", func(t *testing.T) {
+		Expect(calendarfeed.FeedStatusIDs).To(ContainElement(entity.StatusRescheduleID))
+
+		appt := entity.Appointment{AppointmentStatusID: entity.StatusRescheduleID, Sequence: 3, StartAt: &start, EndAt: &end}
+		appt.ID = 7
+		ev := calendarfeed.AppointmentEvent(appt)
+		Expect(ev.UID).To(Equal(calendarfeed.AppointmentUID(7)))
+		Expect(ev.Status).To(Equal("TENTATIVE"))
+		Expect(ev.Sequence).To(Equal(3))
+
+		appt.AppointmentStatusID = entity.StatusApprovedID
+		Expect(calendarfeed.AppointmentEvent(appt).Status).To(Equal("CONFIRMED"))
+	})
+}
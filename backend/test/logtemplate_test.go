@@ -0,0 +1,97 @@
+package test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"backend/internal/app/dto"
+	"backend/internal/app/entity"
+	"backend/internal/service/logtemplate"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestLogTemplate(t *testing.T) {
+	RegisterTestingT(t)
+
+	fields, err := logtemplate.BuildFields([]dto.LogTemplateFieldReq{
+		{Key: "issues", Label: "ประเด็นที่ปรึกษา", Type: "multiselect", Required: true, Options: []string{"แผนการเรียน", "ผลการเรียน", "แผนการเรียน"}},
+		{Key: "actions", Label: "สิ่งที่ตกลง", Type: "textarea", Required: true},
+		{Key: "referral", Label: "ส่งต่อ", Type: "select", Options: []string{"ไม่ส่งต่อ", "ศูนย์แนะแนว"}},
+		{Key: "follow_up", Label: "นัดติดตาม", Type: "checkbox"},
+	})
+	Expect(err).To(BeNil())
+	Expect(fields).To(HaveLen(4))
+	Expect(fields[0].Options).To(Equal([]string{"แผนการเรียน", "ผลการเรียน"}))
+	Expect(fields[3].Position).To(Equal(3))
+	tmpl := entity.LogTemplate{Fields: fields}
+
+	// ค่าที่มาจาก JSON ของ form (เหมือน request จริง)
+	decode := func(s string) map[string]any {
+		var m map[string]any
+		Expect(json.Unmarshal([]byte(s), &m)).To(Succeed())
+		return m
+	}
+	fieldOf := func(err error) string {
+		fe, ok := err.(*logtemplate.FieldError)
+		Expect(ok).To(BeTrue(), "expected *FieldError, got %v", err)
+		return fe.Field
+	}
+
+	t.Run("invalid template definitions", func(t *testing.T) {
+		_, err := logtemplate.BuildFields(nil)
+		Expect(err).NotTo(BeNil())
+
+		_, err = logtemplate.BuildFields([]dto.LogTemplateFieldReq{{Key: "Issues Discussed", Label: "x"}})
+		Expect(fieldOf(err)).To(Equal("fields[0].key"))
+
+		_, err = logtemplate.BuildFields([]dto.LogTemplateFieldReq{{Key: "a", Label: "x"}, {Key: "a", Label: "y"}})
+		Expect(fieldOf(err)).To(Equal("a"))
+
+		_, err = logtemplate.BuildFields([]dto.LogTemplateFieldReq{{Key: "a", Label: "x", Type: "select"}})
+		Expect(err).To(MatchError(ContainSubstring("needs options")))
+
+		_, err = logtemplate.BuildFields([]dto.LogTemplateFieldReq{{Key: "a", Label: "x", Type: "date"}})
+		Expect(err).To(MatchError(ContainSubstring("unknown type")))
+	})
+
+	t.Run("valid values are normalized", func(t *testing.T) {
+		out, err := logtemplate.ValidateValues(tmpl, decode(`{
+			"issues": ["ผลการเรียน", "ผลการเรียน"],
+			"actions": "  ลงเรียนซ้ำวิชา calculus  ",
+			"referral": "",
+			"follow_up": true
+		}`))
+		Expect(err).To(BeNil())
+		Expect(out).To(Equal(map[string]any{
+			"issues":    []string{"ผลการเรียน"},
+			"actions":   "ลงเรียนซ้ำวิชา calculus",
+			"follow_up": true,
+		}))
+
+		body := logtemplate.RenderBody(tmpl, out)
+		Expect(body).To(Equal("ประเด็นที่ปรึกษา: ผลการเรียน\nสิ่งที่ตกลง:\nลงเรียนซ้ำวิชา calculus\nนัดติดตาม: ใช่"))
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		_, err := logtemplate.ValidateValues(tmpl, decode(`{"actions": "x"}`))
+		Expect(fieldOf(err)).To(Equal("issues"))
+
+		_, err = logtemplate.ValidateValues(tmpl, decode(`{"issues": ["อื่น ๆ"], "actions": "x"}`))
+		Expect(fieldOf(err)).To(Equal("issues"))
+
+		_, err = logtemplate.ValidateValues(tmpl, decode(`{"issues": ["ผลการเรียน"], "actions": "x", "referral": "ตำรวจ"}`))
+		Expect(fieldOf(err)).To(Equal("referral"))
+
+		_, err = logtemplate.ValidateValues(tmpl, decode(`{"issues": ["ผลการเรียน"], "actions": "x", "follow_up": "yes"}`))
+		Expect(fieldOf(err)).To(Equal("follow_up"))
+
+		_, err = logtemplate.ValidateValues(tmpl, decode(`{"issues": ["ผลการเรียน"], "actions": "x", "mood": "ดี"}`))
+		Expect(fieldOf(err)).To(Equal("mood"))
+	})
+
+	t.Run("tag names", func(t *testing.T) {
+		Expect(logtemplate.NormalizeTags([]string{"Low-GPA, referral", " referral ", "", "career"})).
+			To(Equal([]string{"low-gpa", "referral", "career"}))
+	})
+}
@@ -0,0 +1,299 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"backend/internal/app/dto"
+	"backend/internal/app/entity"
+	"backend/internal/app/queryspec"
+	"backend/internal/app/repository"
+	"backend/internal/service/advisorlog"
+	"backend/internal/service/fileasset"
+	"backend/internal/service/progressreport"
+	"backend/internal/service/storage"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestProgressReportDueDate(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("date only means end of day in Bangkok", func(t *testing.T) {
+		due, err := progressreport.ParseDue("2025-01-31")
+		Expect(err).To(BeNil())
+		Expect(due.UTC()).To(Equal(time.Date(2025, 1, 31, 16, 59, 59, 0, time.UTC)))
+	})
+
+	t.Run("RFC3339 is kept as is", func(t *testing.T) {
+		due, err := progressreport.ParseDue("2025-01-31T09:00:00+07:00")
+		Expect(err).To(BeNil())
+		Expect(due.UTC()).To(Equal(time.Date(2025, 1, 31, 2, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := progressreport.ParseDue("31/01/2025")
+		Expect(err).To(Equal(progressreport.ErrInvalidDueDate))
+	})
+
+	t.Run("overdue days", func(t *testing.T) {
+		due := time.Date(2025, 1, 31, 17, 0, 0, 0, time.UTC)
+		Expect(progressreport.OverdueDays(due, due.Add(-time.Hour))).To(Equal(0))
+		Expect(progressreport.OverdueDays(due, due.Add(23*time.Hour))).To(Equal(0))
+		Expect(progressreport.OverdueDays(due, due.Add(49*time.Hour))).To(Equal(2))
+	})
+}
+
+func TestProgressReportReviewDecision(t *testing.T) {
+	RegisterTestingT(t)
+
+	report, log, err := progressreport.Decide("approve", "")
+	Expect(err).To(BeNil())
+	Expect(report).To(Equal("Approved"))
+	Expect(log).To(Equal("Completed"))
+
+	report, log, err = progressreport.Decide("request_revision", "เพิ่มแผนการลงทะเบียนเทอมหน้า")
+	Expect(err).To(BeNil())
+	Expect(report).To(Equal("RevisionRequested"))
+	Expect(log).To(Equal("PendingReport"))
+
+	_, _, err = progressreport.Decide("request_revision", "   ")
+	Expect(err).To(Equal(progressreport.ErrCommentRequired))
+
+	_, _, err = progressreport.Decide("reject", "x")
+	Expect(err).To(Equal(progressreport.ErrInvalidDecision))
+}
+
+func TestAdvisorLogStatusChangeWhileReportPendingReview(t *testing.T) {
+	RegisterTestingT(t)
+
+	// รายงานรอตรวจ: ต้องตัดสินผ่าน review เท่านั้น ไม่งั้นรายงานค้าง Submitted ตลอดไป
+	Expect(advisorlog.CheckStatusChange("ReportSubmitted")).To(Equal(advisorlog.ErrReportPendingReview))
+
+	for _, current := range []string{"Draft", "PendingReport", "Completed"} {
+		Expect(advisorlog.CheckStatusChange(current)).To(Succeed())
+	}
+}
+
+// --------------------
+// Fake ProgressReportRepository (ตรวจสิทธิ์ใน service โดยไม่ต้องมี DB)
+// --------------------
+type fakeProgressReportRepo struct {
+	logs    map[uint]*entity.AdvisorLog
+	reports map[uint]*entity.ProgressReport
+
+	// record calls
+	submitted    *entity.ProgressReport
+	reviewed     *entity.ProgressReport
+	reviewStatus string
+	dueLogID     uint
+	dueAt        *time.Time
+}
+
+var _ repository.ProgressReportRepository = (*fakeProgressReportRepo)(nil)
+
+func (f *fakeProgressReportRepo) GetLog(id uint) (*entity.AdvisorLog, error) {
+	l, ok := f.logs[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	cp := *l
+	return &cp, nil
+}
+
+func (f *fakeProgressReportRepo) ListByLog(logID uint) ([]entity.ProgressReport, error) {
+	var out []entity.ProgressReport
+	for _, r := range f.reports {
+		if r.AdvisorLogsID == logID {
+			out = append(out, *r)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeProgressReportRepo) GetReport(id uint) (*entity.ProgressReport, error) {
+	r, ok := f.reports[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	cp := *r
+	return &cp, nil
+}
+
+// Submit ไม่เรียก attach เพราะไม่มี tx จริง (การทดสอบไม่แนบไฟล์)
+func (f *fakeProgressReportRepo) Submit(report *entity.ProgressReport, _ func(tx *gorm.DB) error) error {
+	report.ID = 900
+	report.Version = 1
+	f.submitted = report
+	return nil
+}
+
+func (f *fakeProgressReportRepo) Review(report *entity.ProgressReport, _ *entity.ReportFeedback, logStatus string) error {
+	f.reviewed = report
+	f.reviewStatus = logStatus
+	return nil
+}
+
+func (f *fakeProgressReportRepo) GetFeedback(uint) (*entity.ReportFeedback, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeProgressReportRepo) CreateFeedback(*entity.ReportFeedback, func(tx *gorm.DB) error) error {
+	return nil
+}
+
+func (f *fakeProgressReportRepo) UpdateFeedbackBody(uint, string, time.Time) error { return nil }
+
+func (f *fakeProgressReportRepo) SetDueDate(logID uint, due *time.Time) error {
+	f.dueLogID = logID
+	f.dueAt = due
+	return nil
+}
+
+func (f *fakeProgressReportRepo) ListOverdueByAdvisor(uint, time.Time, queryspec.Spec) ([]entity.AdvisorLog, dto.PageMeta, error) {
+	return nil, dto.PageMeta{}, nil
+}
+
+func (f *fakeProgressReportRepo) ListEscalations([]uint) ([]entity.ReportEscalation, error) {
+	return nil, nil
+}
+
+const (
+	prStudentID      = uint(10)
+	prOtherStudentID = uint(11)
+	prAdvisorID      = uint(20)
+	prOtherAdvisorID = uint(21)
+)
+
+// newProgressReportFixture: log 1 รอรายงาน, log 2 Draft, log 3 Completed, log 4 ไม่ต้องส่งรายงาน,
+// log 5 รอตรวจพร้อมรายงาน 50 (Submitted) และรายงาน 51 ที่ตรวจแล้ว
+func newProgressReportFixture(t *testing.T) (progressreport.Service, *fakeProgressReportRepo) {
+	appt := &entity.Appointment{StudentUserID: prStudentID, AdvisorUserID: prAdvisorID}
+	log := func(id uint, status string, requiresReport bool) *entity.AdvisorLog {
+		l := &entity.AdvisorLog{Status: status, RequiresReport: requiresReport, Appointment: appt}
+		l.ID = id
+		return l
+	}
+	report := func(id uint, logID uint, status string) *entity.ProgressReport {
+		r := &entity.ProgressReport{AdvisorLogsID: logID, Body: "ความคืบหน้า", Status: status}
+		r.ID = id
+		return r
+	}
+
+	repo := &fakeProgressReportRepo{
+		logs: map[uint]*entity.AdvisorLog{
+			1: log(1, "PendingReport", true),
+			2: log(2, "Draft", true),
+			3: log(3, "Completed", true),
+			4: log(4, "Completed", false),
+			5: log(5, "ReportSubmitted", true),
+		},
+		reports: map[uint]*entity.ProgressReport{
+			50: report(50, 5, entity.ProgressReportSubmitted),
+			51: report(51, 5, entity.ProgressReportApproved),
+		},
+	}
+	files := fileasset.New(nil, storage.NewLocal(t.TempDir()))
+	return progressreport.New(repo, files), repo
+}
+
+func TestProgressReportOwnership(t *testing.T) {
+	RegisterTestingT(t)
+	ctx := context.Background()
+
+	t.Run("submit: only the appointment's student on a pending log", func(t *testing.T) {
+		svc, repo := newProgressReportFixture(t)
+		req := dto.ProgressReportCreateReq{AdvisorLogID: 1, Body: "ทำแผนการเรียนเสร็จแล้ว"}
+
+		_, err := svc.Submit(ctx, req, prOtherStudentID, "student")
+		Expect(err).To(Equal(progressreport.ErrForbidden))
+
+		_, err = svc.Submit(ctx, req, prAdvisorID, "advisor")
+		Expect(err).To(Equal(progressreport.ErrForbidden))
+
+		for _, logID := range []uint{2, 3, 5} {
+			_, err = svc.Submit(ctx, dto.ProgressReportCreateReq{AdvisorLogID: logID, Body: "x"}, prStudentID, "student")
+			Expect(err).To(Equal(progressreport.ErrNotPending))
+		}
+
+		_, err = svc.Submit(ctx, dto.ProgressReportCreateReq{AdvisorLogID: 99, Body: "x"}, prStudentID, "student")
+		Expect(err).To(Equal(progressreport.ErrLogNotFound))
+		Expect(repo.submitted).To(BeNil())
+
+		out, err := svc.Submit(ctx, req, prStudentID, "Student")
+		Expect(err).To(BeNil())
+		Expect(out.Status).To(Equal(entity.ProgressReportSubmitted))
+		Expect(repo.submitted.AdvisorLogsID).To(Equal(uint(1)))
+	})
+
+	t.Run("list: appointment's pair or admin, students never see Draft", func(t *testing.T) {
+		svc, _ := newProgressReportFixture(t)
+
+		_, err := svc.ListByLog(ctx, 5, prOtherStudentID, "student")
+		Expect(err).To(Equal(progressreport.ErrForbidden))
+		_, err = svc.ListByLog(ctx, 5, prOtherAdvisorID, "advisor")
+		Expect(err).To(Equal(progressreport.ErrForbidden))
+		_, err = svc.ListByLog(ctx, 2, prStudentID, "student")
+		Expect(err).To(Equal(progressreport.ErrForbidden))
+
+		out, err := svc.ListByLog(ctx, 5, prStudentID, "student")
+		Expect(err).To(BeNil())
+		Expect(out).To(HaveLen(2))
+
+		_, err = svc.ListByLog(ctx, 2, prAdvisorID, "advisor")
+		Expect(err).To(BeNil())
+		_, err = svc.ListByLog(ctx, 5, 1, "admin")
+		Expect(err).To(BeNil())
+	})
+
+	t.Run("review: only the appointment's advisor on a report awaiting review", func(t *testing.T) {
+		svc, repo := newProgressReportFixture(t)
+		approve := dto.ReportReviewReq{Decision: "approve"}
+
+		_, err := svc.Review(ctx, 50, approve, prOtherAdvisorID, "advisor")
+		Expect(err).To(Equal(progressreport.ErrForbidden))
+		_, err = svc.Review(ctx, 50, approve, prStudentID, "student")
+		Expect(err).To(Equal(progressreport.ErrForbidden))
+		_, err = svc.Review(ctx, 51, approve, prAdvisorID, "advisor")
+		Expect(err).To(Equal(progressreport.ErrAlreadyReviewed))
+		_, err = svc.Review(ctx, 99, approve, prAdvisorID, "advisor")
+		Expect(err).To(Equal(progressreport.ErrReportNotFound))
+		Expect(repo.reviewed).To(BeNil())
+
+		_, err = svc.Review(ctx, 50, approve, prAdvisorID, "advisor")
+		Expect(err).To(BeNil())
+		Expect(repo.reviewed.ID).To(Equal(uint(50)))
+		Expect(repo.reviewed.Status).To(Equal(entity.ProgressReportApproved))
+		Expect(*repo.reviewed.ReviewedByID).To(Equal(prAdvisorID))
+		Expect(repo.reviewStatus).To(Equal("Completed"))
+	})
+
+	t.Run("due date: only the appointment's advisor on a log that requires a report", func(t *testing.T) {
+		svc, repo := newProgressReportFixture(t)
+		due := time.Now().Add(72 * time.Hour)
+
+		_, err := svc.SetDueDate(ctx, 1, &due, prOtherAdvisorID, "advisor")
+		Expect(err).To(Equal(progressreport.ErrForbidden))
+		_, err = svc.SetDueDate(ctx, 1, &due, prStudentID, "student")
+		Expect(err).To(Equal(progressreport.ErrForbidden))
+		_, err = svc.SetDueDate(ctx, 4, &due, prAdvisorID, "advisor")
+		Expect(err).To(Equal(progressreport.ErrReportNotNeeded))
+
+		past := time.Now().Add(-time.Hour)
+		_, err = svc.SetDueDate(ctx, 1, &past, prAdvisorID, "advisor")
+		Expect(err).To(Equal(progressreport.ErrDueInPast))
+		Expect(repo.dueLogID).To(BeZero())
+
+		out, err := svc.SetDueDate(ctx, 1, &due, prAdvisorID, "advisor")
+		Expect(err).To(BeNil())
+		Expect(out.DueAt).NotTo(BeNil())
+		Expect(repo.dueLogID).To(Equal(uint(1)))
+
+		_, err = svc.SetDueDate(ctx, 1, nil, prAdvisorID, "advisor")
+		Expect(err).To(BeNil())
+		Expect(repo.dueAt).To(BeNil())
+	})
+}
@@ -0,0 +1,71 @@
+package test
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"backend/internal/app/dto"
+	"backend/internal/app/queryspec"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestQuerySpec(t *testing.T) {
+	RegisterTestingT(t)
+
+	opt := queryspec.Options{
+		Sorts:    map[string]string{"id": "users.id", "name": "users.first_name"},
+		Filters:  []string{"role", "major_id"},
+		IDColumn: "users.id",
+	}
+
+	t.Run("sort whitelist and stable order", func(t *testing.T) {
+		spec, err := queryspec.Parse(url.Values{"sort": {"-name"}}, opt)
+		Expect(err).To(BeNil())
+		Expect(spec.ToOrder()).To(Equal("users.first_name desc, users.id desc"))
+
+		spec, err = queryspec.Parse(url.Values{}, opt)
+		Expect(err).To(BeNil())
+		Expect(spec.ToOrder()).To(Equal("users.id desc"))
+
+		_, err = queryspec.Parse(url.Values{"sort": {"password"}}, opt)
+		var se *queryspec.SortError
+		Expect(err).To(BeAssignableToTypeOf(se))
+		Expect(err.Error()).To(ContainSubstring("allowed: id, name"))
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		spec, err := queryspec.Parse(url.Values{"role": {" Advisor "}, "major_id": {"x"}, "other": {"1"}}, opt)
+		Expect(err).To(BeNil())
+		Expect(spec.Filter("role")).To(Equal("Advisor"))
+		Expect(spec.Filter("other")).To(BeEmpty())
+		_, err = spec.FilterUint("major_id")
+		Expect(err).To(MatchError("invalid major_id"))
+	})
+
+	t.Run("cursor", func(t *testing.T) {
+		spec, err := queryspec.Parse(url.Values{"cursor": {""}, "page": {"4"}}, opt)
+		Expect(err).To(BeNil())
+		Expect(spec.Cursor).To(BeTrue())
+		Expect(spec.Page).To(Equal(1))
+		Expect(spec.After).To(BeZero())
+
+		spec, err = queryspec.Parse(url.Values{"cursor": {queryspec.EncodeCursor(42)}, "sort": {"id"}}, opt)
+		Expect(err).To(BeNil())
+		Expect(spec.After).To(Equal(uint(42)))
+		Expect(spec.ToOrder()).To(Equal("users.id asc"))
+
+		_, err = queryspec.Parse(url.Values{"cursor": {queryspec.EncodeCursor(42)}, "sort": {"name"}}, opt)
+		Expect(err).To(Equal(queryspec.ErrCursorSort))
+
+		_, err = queryspec.Parse(url.Values{"cursor": {"not-a-cursor"}}, opt)
+		Expect(err).To(Equal(queryspec.ErrInvalidCursor))
+	})
+
+	t.Run("envelope", func(t *testing.T) {
+		b, err := json.Marshal(dto.NewPageResp[string](nil, dto.PageMeta{Total: 0, Page: 1, PageSize: 20}))
+		Expect(err).To(BeNil())
+		Expect(string(b)).To(Equal(`{"data":[],"total":0,"page":1,"page_size":20}`))
+	})
+}
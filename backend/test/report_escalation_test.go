@@ -0,0 +1,41 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"backend/internal/service/reportescalation"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestReportEscalationSteps(t *testing.T) {
+	RegisterTestingT(t)
+
+	th := reportescalation.Thresholds{AdvisorAfter: 3 * 24 * time.Hour, AdminAfter: 7 * 24 * time.Hour}
+	due := time.Date(2025, 2, 10, 16, 59, 59, 0, time.UTC)
+
+	Expect(reportescalation.DueSteps(due, due, th)).To(BeEmpty())
+	Expect(reportescalation.DueSteps(due, due.Add(time.Minute), th)).To(Equal([]string{"student"}))
+	Expect(reportescalation.DueSteps(due, due.Add(3*24*time.Hour), th)).To(Equal([]string{"student", "advisor"}))
+	Expect(reportescalation.DueSteps(due, due.Add(10*24*time.Hour), th)).To(Equal([]string{"student", "advisor", "admin"}))
+
+	Expect(reportescalation.StepRank("admin")).To(BeNumerically(">", reportescalation.StepRank("advisor")))
+	Expect(reportescalation.StepRank("advisor")).To(BeNumerically(">", reportescalation.StepRank("student")))
+	Expect(reportescalation.StepRank("")).To(Equal(0))
+}
+
+func TestReportEscalationConfig(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Setenv("REPORT_ESCALATE_ADVISOR_DAYS", "2")
+	t.Setenv("REPORT_ESCALATE_ADMIN_DAYS", "x")
+	th := reportescalation.ThresholdsFromEnv()
+	Expect(th.AdvisorAfter).To(Equal(48 * time.Hour))
+	Expect(th.AdminAfter).To(Equal(reportescalation.DefaultAdminAfter))
+
+	t.Setenv("REPORT_ESCALATION_INTERVAL", "0")
+	Expect(reportescalation.IntervalFromEnv()).To(Equal(time.Duration(0)))
+	t.Setenv("REPORT_ESCALATION_INTERVAL", "")
+	Expect(reportescalation.IntervalFromEnv()).To(Equal(reportescalation.DefaultInterval))
+}
@@ -0,0 +1,60 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"backend/internal/app/dto"
+	"backend/internal/app/entity"
+	"backend/internal/service/progressreport"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestReportFeedbackThreads(t *testing.T) {
+	RegisterTestingT(t)
+
+	id := func(v uint) *uint { return &v }
+	flat := []dto.ReportFeedbackResp{
+		{ID: 1, Body: "ขอแผนการเรียนเพิ่ม"},
+		{ID: 2, ParentID: id(1), Body: "แนบไฟล์แผนแล้วครับ"},
+		{ID: 3, Body: "ความเห็นเพิ่มเติม"},
+		{ID: 4, ParentID: id(2), Body: "รับทราบ"},
+		{ID: 5, ParentID: id(1), Body: "ตอบอีกครั้ง"},
+		{ID: 6, ParentID: id(99), Body: "parent ถูกลบไปแล้ว"},
+	}
+
+	threads := progressreport.BuildThreads(flat)
+	Expect(threads).To(HaveLen(3))
+	Expect(threads[0].ID).To(Equal(uint(1)))
+	Expect(threads[0].Replies).To(HaveLen(2))
+	Expect(threads[0].Replies[0].ID).To(Equal(uint(2)))
+	Expect(threads[0].Replies[0].Replies[0].ID).To(Equal(uint(4)))
+	Expect(threads[0].Replies[1].ID).To(Equal(uint(5)))
+	Expect(threads[1].ID).To(Equal(uint(3)))
+	Expect(threads[1].Replies).To(BeEmpty())
+	Expect(threads[2].ID).To(Equal(uint(6)))
+}
+
+func TestReportFeedbackEditWindow(t *testing.T) {
+	RegisterTestingT(t)
+
+	author := uint(7)
+	posted := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	fb := entity.ReportFeedback{Model: gorm.Model{CreatedAt: posted}, AuthorUserID: &author}
+
+	Expect(progressreport.CanEdit(fb, author, posted.Add(5*time.Minute), 15*time.Minute)).To(BeTrue())
+	Expect(progressreport.CanEdit(fb, author, posted.Add(15*time.Minute), 15*time.Minute)).To(BeFalse())
+	Expect(progressreport.CanEdit(fb, 8, posted.Add(time.Minute), 15*time.Minute)).To(BeFalse())
+
+	// feedback เก่าที่ไม่มีผู้เขียนแก้ไม่ได้
+	fb.AuthorUserID = nil
+	Expect(progressreport.CanEdit(fb, author, posted, 15*time.Minute)).To(BeFalse())
+
+	t.Setenv("FEEDBACK_EDIT_WINDOW_MINUTES", "60")
+	Expect(progressreport.EditWindow()).To(Equal(time.Hour))
+	t.Setenv("FEEDBACK_EDIT_WINDOW_MINUTES", "abc")
+	Expect(progressreport.EditWindow()).To(Equal(progressreport.DefaultEditWindow))
+}
@@ -0,0 +1,141 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"backend/internal/service/search"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestSearchHelpers(t *testing.T) {
+	RegisterTestingT(t)
+
+	t.Run("query length counts Thai characters", func(t *testing.T) {
+		q, err := search.NormalizeQuery("  ทุน   การศึกษา ")
+		Expect(err).To(BeNil())
+		Expect(q).To(Equal("ทุน การศึกษา"))
+
+		_, err = search.NormalizeQuery("ก")
+		Expect(err).To(Equal(search.ErrQueryTooShort))
+		_, err = search.NormalizeQuery("   ")
+		Expect(err).To(Equal(search.ErrQueryTooShort))
+	})
+
+	t.Run("like pattern escapes wildcards", func(t *testing.T) {
+		Expect(search.LikePattern("100%_ok")).To(Equal(`%100\%\_ok%`))
+	})
+
+	t.Run("types", func(t *testing.T) {
+		types, err := search.ParseTypes(nil)
+		Expect(err).To(BeNil())
+		Expect(types).To(Equal([]string{"user", "advisor_log", "faq", "report"}))
+
+		types, err = search.ParseTypes([]string{"faq,user", "faq"})
+		Expect(err).To(BeNil())
+		Expect(types).To(Equal([]string{"faq", "user"}))
+
+		_, err = search.ParseTypes([]string{"appointment"})
+		Expect(err).To(Equal(search.ErrInvalidType))
+	})
+
+	t.Run("index and query use the same expression", func(t *testing.T) {
+		for _, src := range search.Sources {
+			Expect(src.Expr(true)).To(Equal(strings.ReplaceAll(src.Expr(false), "coalesce(", "coalesce("+src.Table+".")))
+		}
+		Expect(search.Sources[1].Expr(false)).To(Equal("(coalesce(title, '') || ' ' || coalesce(body, ''))"))
+	})
+
+	t.Run("snippet does not split Thai characters", func(t *testing.T) {
+		text := strings.Repeat("ก", 50) + "ผลการเรียนตกต่ำ" + strings.Repeat("ข", 50)
+		s := search.Snippet(text, "ผลการเรียน", 30)
+		Expect(s).To(ContainSubstring("ผลการเรียน"))
+		Expect(strings.HasPrefix(s, "…")).To(BeTrue())
+		Expect(strings.HasSuffix(s, "…")).To(BeTrue())
+		Expect([]rune(strings.Trim(s, "…"))).To(HaveLen(30))
+
+		Expect(search.Snippet("สั้น", "x", 30)).To(Equal("สั้น"))
+	})
+}
+
+// searchSQL สร้าง SQL ของ query โดยไม่ต่อ DB (DryRun) แล้วยุบช่องว่างให้เทียบง่าย
+func searchSQL(t *testing.T, build func(tx *gorm.DB) (*gorm.DB, error)) string {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1 user=test dbname=test sslmode=disable"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               logger.Default.LogMode(logger.Silent),
+	})
+	Expect(err).To(BeNil())
+
+	var buildErr error
+	sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		q, err := build(tx)
+		if err != nil {
+			buildErr = err
+			return tx
+		}
+		var rows []map[string]any
+		return q.Find(&rows)
+	})
+	Expect(buildErr).To(BeNil())
+	return strings.Join(strings.Fields(sql), " ")
+}
+
+func TestSearchScope(t *testing.T) {
+	RegisterTestingT(t)
+
+	const me = 42
+	ilike := "ILIKE '%ทุน%'"
+
+	userSQL := func(role string) string {
+		return searchSQL(t, func(tx *gorm.DB) (*gorm.DB, error) {
+			return search.UserQuery(tx, "ทุน", 10, me, role), nil
+		})
+	}
+	logSQL := func(role string) string {
+		return searchSQL(t, func(tx *gorm.DB) (*gorm.DB, error) {
+			return search.AdvisorLogQuery(tx, "ทุน", 10, me, role)
+		})
+	}
+
+	t.Run("student finds only staff and themself", func(t *testing.T) {
+		sql := userSQL("student")
+		// OR อยู่ในวงเล็บ ไม่งั้นเงื่อนไขค้นหาจะหลุดไปเปิดให้เห็นทุกคน
+		Expect(sql).To(ContainSubstring("WHERE (users.role_id IN (SELECT id FROM roles WHERE lower(role) IN ('advisor', 'admin')) OR users.id = 42) AND "))
+		Expect(sql).To(ContainSubstring(ilike))
+		Expect(sql).NotTo(ContainSubstring("student_profiles"))
+	})
+
+	t.Run("advisor finds staff and their advisees", func(t *testing.T) {
+		sql := userSQL("advisor")
+		Expect(sql).To(ContainSubstring("WHERE (users.role_id IN (SELECT id FROM roles WHERE lower(role) IN ('advisor', 'admin')) OR users.id IN ( SELECT sp.user_id FROM student_profiles sp"))
+		Expect(sql).To(ContainSubstring("WHERE ap.user_id = 42 AND sp.deleted_at IS NULL)) AND "))
+		Expect(sql).To(ContainSubstring(ilike))
+	})
+
+	t.Run("student finds only their own non-draft advisor logs", func(t *testing.T) {
+		sql := logSQL("student")
+		Expect(sql).To(ContainSubstring("WHERE (appointments.student_user_id = 42 AND advisor_logs.status <> 'Draft') AND "))
+		Expect(sql).To(ContainSubstring(ilike))
+		Expect(sql).NotTo(ContainSubstring("private_notes"))
+	})
+
+	t.Run("advisor finds logs of their appointments and advisees", func(t *testing.T) {
+		sql := logSQL("advisor")
+		Expect(sql).To(ContainSubstring("WHERE ((appointments.advisor_user_id = 42 OR appointments.student_user_id IN ( SELECT sp.user_id FROM student_profiles sp"))
+		Expect(sql).To(ContainSubstring("AND ((advisor_logs.status <> 'Draft' OR appointments.advisor_user_id = 42)) AND "))
+		Expect(sql).To(ContainSubstring(ilike))
+	})
+
+	t.Run("unknown role cannot search advisor logs", func(t *testing.T) {
+		db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+		Expect(err).To(BeNil())
+		_, err = search.AdvisorLogQuery(db, "ทุน", 10, me, "guest")
+		Expect(err).NotTo(BeNil())
+	})
+}
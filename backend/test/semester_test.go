@@ -0,0 +1,125 @@
+package test
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm/schema"
+
+	"backend/internal/app/dto"
+	"backend/internal/app/entity"
+	"backend/internal/app/queryspec"
+	service "backend/internal/service/academiccalendar"
+
+	. "github.com/onsi/gomega"
+)
+
+// fakeSemesterRepo เก็บภาคการศึกษาใน memory (Delete = soft delete: ไม่นับตอนตรวจปี/เทอมซ้ำ)
+type fakeSemesterRepo struct {
+	rows   map[uint]*entity.Semester
+	nextID uint
+}
+
+func newFakeSemesterRepo() *fakeSemesterRepo {
+	return &fakeSemesterRepo{rows: map[uint]*entity.Semester{}}
+}
+
+func (r *fakeSemesterRepo) FindAll(queryspec.Spec) ([]entity.Semester, dto.PageMeta, error) {
+	return nil, dto.PageMeta{}, nil
+}
+
+func (r *fakeSemesterRepo) FindByDate(time.Time) (*entity.Semester, error) { return nil, nil }
+
+func (r *fakeSemesterRepo) GetByID(id string) (*entity.Semester, error) {
+	n, _ := strconv.Atoi(id)
+	if s, ok := r.rows[uint(n)]; ok {
+		cp := *s
+		return &cp, nil
+	}
+	return nil, service.ErrSemesterNotFound
+}
+
+func (r *fakeSemesterRepo) YearTermTaken(year, term int, exceptID uint) (bool, error) {
+	for id, s := range r.rows {
+		if id != exceptID && s.AcademicYear == year && s.TermNumber == term {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeSemesterRepo) Create(s *entity.Semester) error {
+	r.nextID++
+	s.ID = r.nextID
+	cp := *s
+	r.rows[s.ID] = &cp
+	return nil
+}
+
+func (r *fakeSemesterRepo) Update(s *entity.Semester) error {
+	cp := *s
+	r.rows[s.ID] = &cp
+	return nil
+}
+
+func (r *fakeSemesterRepo) Delete(s *entity.Semester) error {
+	delete(r.rows, s.ID)
+	return nil
+}
+
+func TestSemesterYearTerm(t *testing.T) {
+	RegisterTestingT(t)
+
+	req := func(year, term int) dto.SemesterRequest {
+		return dto.SemesterRequest{
+			Name: "ภาค " + strconv.Itoa(term) + "/" + strconv.Itoa(year), AcademicYear: year, TermNumber: term,
+			StartDate: "2025-06-02", EndDate: "2025-10-03",
+		}
+	}
+
+	t.Run("duplicate year/term is a conflict", func(t *testing.T) {
+		svc := service.NewSemesterService(newFakeSemesterRepo(), nil)
+		first, err := svc.Create(req(2568, 1))
+		Expect(err).To(BeNil())
+
+		_, err = svc.Create(req(2568, 1))
+		Expect(err).To(Equal(service.ErrSemesterExists))
+
+		second, err := svc.Create(req(2568, 2))
+		Expect(err).To(BeNil())
+		_, err = svc.Update(strconv.Itoa(int(second.ID)), req(2568, 1))
+		Expect(err).To(Equal(service.ErrSemesterExists))
+
+		// แก้ภาคเดิมโดยไม่เปลี่ยนปี/เทอมต้องได้
+		_, err = svc.Update(strconv.Itoa(int(first.ID)), req(2568, 1))
+		Expect(err).To(BeNil())
+	})
+
+	t.Run("deleted semester frees its year/term", func(t *testing.T) {
+		svc := service.NewSemesterService(newFakeSemesterRepo(), nil)
+		sem, err := svc.Create(req(2568, 1))
+		Expect(err).To(BeNil())
+		Expect(svc.Delete(strconv.Itoa(int(sem.ID)))).To(Succeed())
+
+		_, err = svc.Create(req(2568, 1))
+		Expect(err).To(BeNil())
+	})
+
+	t.Run("unique index skips soft-deleted rows", func(t *testing.T) {
+		s, err := schema.Parse(&entity.Semester{}, &sync.Map{}, schema.NamingStrategy{})
+		Expect(err).To(BeNil())
+
+		var found bool
+		for _, idx := range s.ParseIndexes() {
+			if idx.Name == "idx_semester_year_term" {
+				found = true
+				Expect(idx.Class).To(Equal("UNIQUE"))
+				Expect(idx.Where).To(Equal("deleted_at IS NULL"))
+				Expect(idx.Fields).To(HaveLen(2))
+			}
+		}
+		Expect(found).To(BeTrue())
+	})
+}
@@ -0,0 +1,143 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend/internal/app/controller"
+	"backend/internal/app/entity"
+	"backend/internal/service/fileasset"
+	"backend/internal/service/signedurl"
+	"backend/internal/service/storage"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/gomega"
+)
+
+// fakeAssets ใช้ fileasset จริงกับ local storage แต่หาแถวจาก map แทน DB
+type fakeAssets struct {
+	fileasset.Service
+	rows map[uint]entity.FileAssets
+}
+
+func (f *fakeAssets) Get(_ context.Context, id uint) (*entity.FileAssets, error) {
+	a, ok := f.rows[id]
+	if !ok {
+		return nil, fileasset.ErrFileNotFound
+	}
+	return &a, nil
+}
+
+func TestSignedURL(t *testing.T) {
+	RegisterTestingT(t)
+
+	now := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	signer := signedurl.New([]byte("test-secret")).WithClock(func() time.Time { return now })
+
+	t.Run("round trip keeps disposition", func(t *testing.T) {
+		q, exp := signer.Sign(42, "inline", 2*time.Minute)
+		Expect(exp).To(Equal(now.Add(2 * time.Minute)))
+
+		disp, err := signer.Verify(42, q)
+		Expect(err).To(BeNil())
+		Expect(disp).To(Equal(signedurl.DispositionInline))
+	})
+
+	t.Run("signature is bound to file and disposition", func(t *testing.T) {
+		q, _ := signer.Sign(42, "attachment", time.Minute)
+
+		_, err := signer.Verify(43, q)
+		Expect(err).To(MatchError(signedurl.ErrInvalidSignature))
+
+		q.Set("disp", "inline")
+		_, err = signer.Verify(42, q)
+		Expect(err).To(MatchError(signedurl.ErrInvalidSignature))
+
+		_, err = signedurl.New([]byte("other")).Verify(42, q)
+		Expect(err).To(MatchError(signedurl.ErrInvalidSignature))
+	})
+
+	t.Run("expires and ttl is clamped", func(t *testing.T) {
+		q, exp := signer.Sign(1, "", 24*time.Hour)
+		Expect(exp).To(Equal(now.Add(signedurl.MaxTTL)))
+
+		later := signer.WithClock(func() time.Time { return now.Add(signedurl.MaxTTL + time.Second) })
+		_, err := later.Verify(1, q)
+		Expect(err).To(MatchError(signedurl.ErrExpired))
+	})
+}
+
+func TestSignedFileDownload(t *testing.T) {
+	RegisterTestingT(t)
+	gin.SetMode(gin.TestMode)
+
+	store := storage.NewLocal(t.TempDir())
+	content := []byte("0123456789abcdef")
+	Expect(store.Put(context.Background(), "a.txt", bytes.NewReader(content), int64(len(content)), "text/plain")).To(Succeed())
+	page := []byte("<html><script>alert(1)</script></html>")
+	Expect(store.Put(context.Background(), "b.html", bytes.NewReader(page), int64(len(page)), "text/html")).To(Succeed())
+	Expect(store.Put(context.Background(), "c.png", bytes.NewReader(content), int64(len(content)), "image/png")).To(Succeed())
+
+	files := &fakeAssets{
+		Service: fileasset.New(nil, store),
+		rows: map[uint]entity.FileAssets{
+			7: {OriginalName: "บันทึก.txt", MimeType: "text/plain", StoragePath: "a.txt", Size: int64(len(content))},
+			8: {OriginalName: "page.html", MimeType: "text/html", StoragePath: "b.html", Size: int64(len(page))},
+			9: {OriginalName: "photo.png", MimeType: "image/png", StoragePath: "c.png", Size: int64(len(content))},
+		},
+	}
+	signer := signedurl.New([]byte("test-secret"))
+
+	r := gin.New()
+	r.GET("/api/files/:id", controller.NewSignedFileController(signer, files).Download)
+
+	get := func(path string, header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("valid link without jwt, inline disposition", func(t *testing.T) {
+		q, _ := signer.Sign(9, "inline", time.Minute)
+		w := get(fmt.Sprintf("/api/files/9?%s", q.Encode()), nil)
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(w.Body.Bytes()).To(Equal(content))
+		Expect(w.Header().Get("Content-Type")).To(Equal("image/png"))
+		Expect(w.Header().Get("Content-Disposition")).To(HavePrefix("inline"))
+		Expect(w.Header().Get("Accept-Ranges")).To(Equal("bytes"))
+	})
+
+	t.Run("non-image is always a download even with an inline link", func(t *testing.T) {
+		for _, id := range []uint{7, 8} {
+			q, _ := signer.Sign(id, "inline", time.Minute)
+			w := get(fmt.Sprintf("/api/files/%d?%s", id, q.Encode()), nil)
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Header().Get("Content-Type")).To(Equal("application/octet-stream"))
+			Expect(w.Header().Get("Content-Disposition")).To(HavePrefix("attachment"))
+		}
+	})
+
+	t.Run("range request", func(t *testing.T) {
+		q, _ := signer.Sign(7, "attachment", time.Minute)
+		w := get(fmt.Sprintf("/api/files/7?%s", q.Encode()), map[string]string{"Range": "bytes=4-7"})
+		Expect(w.Code).To(Equal(http.StatusPartialContent))
+		Expect(w.Body.String()).To(Equal("4567"))
+		Expect(w.Header().Get("Content-Range")).To(Equal("bytes 4-7/16"))
+		Expect(w.Header().Get("Content-Disposition")).To(HavePrefix("attachment"))
+	})
+
+	t.Run("link for another file or without signature is rejected", func(t *testing.T) {
+		q, _ := signer.Sign(99, "inline", time.Minute)
+		Expect(get(fmt.Sprintf("/api/files/7?%s", q.Encode()), nil).Code).To(Equal(http.StatusForbidden))
+		Expect(get("/api/files/7", nil).Code).To(Equal(http.StatusForbidden))
+	})
+}
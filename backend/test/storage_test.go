@@ -0,0 +1,136 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"backend/internal/service/storage"
+
+	. "github.com/onsi/gomega"
+)
+
+// ชุดทดสอบเดียวกันสำหรับทุก driver
+func storageContract(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	content := []byte("ไฟล์แนบบันทึกการให้คำปรึกษา")
+	key := "logs/test-" + time.Now().Format("150405.000000") + ".txt"
+
+	t.Run("put then stat and get", func(t *testing.T) {
+		Expect(s.Put(ctx, key, bytes.NewReader(content), int64(len(content)), "text/plain")).To(Succeed())
+
+		info, err := s.Stat(ctx, key)
+		Expect(err).To(BeNil())
+		Expect(info.Size).To(Equal(int64(len(content))))
+
+		obj, info, err := s.Get(ctx, key)
+		Expect(err).To(BeNil())
+		defer obj.Close()
+		Expect(info.Size).To(Equal(int64(len(content))))
+		Expect(io.ReadAll(obj)).To(Equal(content))
+	})
+
+	t.Run("object is seekable for range requests", func(t *testing.T) {
+		obj, _, err := s.Get(ctx, key)
+		Expect(err).To(BeNil())
+		defer obj.Close()
+
+		_, err = obj.Seek(int64(len(content)-3), io.SeekStart)
+		Expect(err).To(BeNil())
+		Expect(io.ReadAll(obj)).To(Equal(content[len(content)-3:]))
+	})
+
+	t.Run("list walks objects under prefix", func(t *testing.T) {
+		other := "other/" + key
+		Expect(s.Put(ctx, other, bytes.NewReader(content), int64(len(content)), "")).To(Succeed())
+		defer s.Delete(ctx, other)
+
+		var all, logs []string
+		Expect(s.List(ctx, "", func(o storage.ObjectInfo) error {
+			all = append(all, o.Key)
+			return nil
+		})).To(Succeed())
+		Expect(s.List(ctx, "logs/", func(o storage.ObjectInfo) error {
+			logs = append(logs, o.Key)
+			Expect(o.Size).To(Equal(int64(len(content))))
+			return nil
+		})).To(Succeed())
+
+		Expect(all).To(ContainElements(key, other))
+		Expect(logs).To(ContainElement(key))
+		Expect(logs).NotTo(ContainElement(other))
+	})
+
+	t.Run("delete is idempotent and missing key is ErrNotFound", func(t *testing.T) {
+		Expect(s.Delete(ctx, key)).To(Succeed())
+		Expect(s.Delete(ctx, key)).To(Succeed())
+
+		_, err := s.Stat(ctx, key)
+		Expect(err).To(MatchError(storage.ErrNotFound))
+		_, _, err = s.Get(ctx, key)
+		Expect(err).To(MatchError(storage.ErrNotFound))
+	})
+
+	t.Run("keys cannot escape the root", func(t *testing.T) {
+		for _, bad := range []string{"", "../secret.txt", "a/../../b", "a//b"} {
+			err := s.Put(ctx, bad, strings.NewReader("x"), 1, "")
+			Expect(err).To(MatchError(storage.ErrInvalidKey), bad)
+		}
+	})
+}
+
+func TestLocalStorage(t *testing.T) {
+	RegisterTestingT(t)
+
+	s := storage.NewLocal(t.TempDir())
+	storageContract(t, s)
+
+	t.Run("list skips unfinished uploads and empty root", func(t *testing.T) {
+		dir := t.TempDir()
+		Expect(os.WriteFile(dir+"/.upload-123", []byte("partial"), 0644)).To(Succeed())
+
+		n := 0
+		Expect(storage.NewLocal(dir).List(context.Background(), "", func(storage.ObjectInfo) error { n++; return nil })).To(Succeed())
+		Expect(n).To(Equal(0))
+
+		Expect(storage.NewLocal(dir+"/not-created").List(context.Background(), "", func(storage.ObjectInfo) error { n++; return nil })).To(Succeed())
+	})
+
+	t.Run("signed url is not supported", func(t *testing.T) {
+		_, err := s.SignedURL(context.Background(), "a.pdf", time.Minute, "a.pdf")
+		Expect(err).To(MatchError(storage.ErrNotSupported))
+	})
+}
+
+// รันกับ MinIO ได้ด้วย: docker compose --profile s3 up -d minio
+// แล้วตั้ง S3_TEST_ENDPOINT=localhost:9000 (ใช้ S3_ACCESS_KEY/S3_SECRET_KEY หรือค่า default ของ compose)
+func TestS3Storage(t *testing.T) {
+	endpoint := os.Getenv("S3_TEST_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("S3_TEST_ENDPOINT not set")
+	}
+	RegisterTestingT(t)
+
+	cfg := storage.ConfigFromEnv()
+	cfg.Driver = storage.DriverS3
+	cfg.Endpoint = endpoint
+	cfg.Bucket = "advisor-test"
+	cfg.CreateBucket = true
+	if cfg.AccessKey == "" {
+		cfg.AccessKey, cfg.SecretKey = "minioadmin", "minioadmin"
+	}
+
+	s, err := storage.New(context.Background(), cfg)
+	Expect(err).To(BeNil())
+	storageContract(t, s)
+
+	t.Run("signed url", func(t *testing.T) {
+		u, err := s.SignedURL(context.Background(), "a.pdf", time.Minute, "รายงาน.pdf")
+		Expect(err).To(BeNil())
+		Expect(u).To(ContainSubstring("X-Amz-Signature"))
+	})
+}
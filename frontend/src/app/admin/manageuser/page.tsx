@@ -1,6 +1,6 @@
 "use client";
 
-import { useEffect, useMemo, useState, useCallback } from "react";
+import { useEffect, useState, useCallback } from "react";
 import { useAuth } from "@/src/contexts/AuthContext";
 import { useRouter } from "next/navigation";
 import { Select, ConfigProvider } from "antd";
@@ -18,6 +18,7 @@ import type {
   AdminUserDetailResponse,
   MajorEntry,
 } from "@/src/interfaces/adminusers";
+import { Pagination } from "@/src/components/Pagination/Pagination";
 
 // ใน Go DTO เราใช้ SutID เป็น string ดังนั้นเราจะใช้ string ใน Frontend ทั้งหมด
 export interface ManagedUserEntry extends BaseManagedUserEntry {
@@ -112,7 +113,10 @@ export default function ManageUsersPage() {
     major: "",
   });
 
+  // ค้นหา/แบ่งหน้าฝั่ง backend: ขอทีละหน้า (search หน่วงไว้ไม่ให้ยิงทุกตัวอักษร)
+  const [debouncedSearch, setDebouncedSearch] = useState("");
   const [page, setPage] = useState(1);
+  const [total, setTotal] = useState(0);
   const pageSize = 10;
 
   const [editingUserId, setEditingUserId] = useState<string | null>(null);
@@ -239,15 +243,25 @@ export default function ManageUsersPage() {
     fetchMajorsList();
   }, [isLoading, isAuthenticated]);
 
-  // Fetch User List (ใช้ API จริง: getManagedUsers)
+  useEffect(() => {
+    const t = setTimeout(() => setDebouncedSearch(search.trim()), 300);
+    return () => clearTimeout(t);
+  }, [search]);
+
+  // Fetch User List (ใช้ API จริง: getManagedUsers) ทีละหน้า
   useEffect(() => {
     if (isLoading || !isAuthenticated) return;
     const fetchList = async () => {
       setLoading(true);
       setError(null);
       try {
-        const response = await getManagedUsers(filters);
-        const usersWithCreationDatePromises = response.users.map(async (u) => {
+        const response = await getManagedUsers({
+          ...filters,
+          search: debouncedSearch,
+          page: String(page),
+          page_size: String(pageSize),
+        });
+        const usersWithCreationDatePromises = response.data.map(async (u) => {
           try {
             const createdDateResponse = await getUserCreatedDate(u.sutId);
             return {
@@ -268,6 +282,7 @@ export default function ManageUsersPage() {
         });
         const usersData = await Promise.all(usersWithCreationDatePromises);
         setUsers(usersData);
+        setTotal(response.total);
       } catch (err: any) {
         if (err.message.includes("Unauthorized")) {
           router.push("/login");
@@ -279,7 +294,7 @@ export default function ManageUsersPage() {
       }
     };
     fetchList();
-  }, [filters, isLoading, isAuthenticated, router]);
+  }, [filters, debouncedSearch, page, isLoading, isAuthenticated, router]);
 
   // useEffect: Fetch User Detail (ใช้ API จริง: getUserDetailBySutId)
   useEffect(() => {
@@ -306,20 +321,9 @@ export default function ManageUsersPage() {
     fetchDetail();
   }, [editingUserId]);
 
-  // --- Filtering & Pagination Logic ---
-
-  const filtered = useMemo(() => {
-    const q = search.trim().toLowerCase();
-    return users.filter((u) => {
-      if (q === "") return true;
-      return [u.fullName, u.sutId, u.email]
-        .filter(Boolean)
-        .some((field) => field!.toString().toLowerCase().includes(q));
-    });
-  }, [users, search]);
+  // --- Pagination (backend แบ่งหน้าแล้ว users คือหน้าปัจจุบัน) ---
 
-  const totalPages = Math.max(1, Math.ceil(filtered.length / pageSize));
-  const pageData = filtered.slice((page - 1) * pageSize, page * pageSize);
+  const pageData = users;
 
   // --- Render Components ---
 
@@ -511,7 +515,10 @@ export default function ManageUsersPage() {
               type="text"
               placeholder="ค้นหา (ชื่อ, ID, Email)"
               value={search}
-              onChange={(e) => setSearch(e.target.value)}
+              onChange={(e) => {
+                setSearch(e.target.value);
+                setPage(1);
+              }}
               className="pl-10 pr-4 py-2 w-full rounded-xl border border-gray-300 text-sm 
                              transition duration-150"
             />
@@ -588,7 +595,7 @@ export default function ManageUsersPage() {
           </div>
           {/* แสดงผลรวมผู้ใช้ทั้งหมด */}
           <div className="flex-shrink-0 text-sm font-medium text-gray-600 px-4 py-2 rounded-xl  ml-auto flex items-center h-11">
-            แสดง {filtered.length} ผู้ใช้
+            แสดง {total} ผู้ใช้
           </div>
         </div>
 
@@ -701,27 +708,7 @@ export default function ManageUsersPage() {
         </div>
 
         {/* 4. การแบ่งหน้า (Pagination) */}
-        <div className="flex items-center justify-between flex-shrink-0 mt-4 px-4 py-3">
-          <div className="text-sm text-gray-700">
-            หน้า {page} จาก {totalPages} ({filtered.length} รายการ)
-          </div>
-          <div className="flex items-center gap-2">
-            <button
-              disabled={page === 1}
-              onClick={() => setPage((p) => Math.max(1, p - 1))}
-              className="px-3 py-1 rounded-md border disabled:opacity-50 text-gray-700 bg-white hover:bg-gray-50"
-            >
-              ก่อนหน้า
-            </button>
-            <button
-              disabled={page === totalPages}
-              onClick={() => setPage((p) => Math.min(totalPages, p + 1))}
-              className="px-3 py-1 rounded-md border disabled:opacity-50 text-gray-700 bg-white hover:bg-gray-50"
-            >
-              ถัดไป
-            </button>
-          </div>
-        </div>
+        <Pagination page={page} pageSize={pageSize} total={total} onChange={setPage} disabled={loading} />
 
         {/* Modal: รายละเอียด/แก้ไขผู้ใช้ */}
         <UserDetailModal />
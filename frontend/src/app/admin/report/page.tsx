@@ -8,33 +8,40 @@ import {
 } from "@/src/services/http/report";
 import ReportTable from "@/src/components/report/reporttable";
 import ReportModal from "@/src/components/report/reportmodal-backup";
+import { DEFAULT_PAGE_SIZE } from "@/src/services/http/paging";
+import { Pagination } from "@/src/components/Pagination/Pagination";
 
 export default function ReportPage() {
   // summary
   const [summary, setSummary] = useState<any>(null);
 
-  // report list
+  // report list (backend แบ่งหน้า: ขอทีละหน้า)
   const [reports, setReports] = useState<any[]>([]);
+  const [page, setPage] = useState(1);
+  const [total, setTotal] = useState(0);
+  const pageSize = DEFAULT_PAGE_SIZE;
 
   // modal state
   const [open, setOpen] = useState(false);
   const [selected, setSelected] = useState<any>(null);
   const [loading, setLoading] = useState(false);
 
-  // โหลดข้อมูลครั้งแรก
+  // โหลดข้อมูลครั้งแรก และทุกครั้งที่เปลี่ยนหน้า
   useEffect(() => {
     loadData();
-  }, []);
+    // eslint-disable-next-line react-hooks/exhaustive-deps
+  }, [page]);
 
   const loadData = async () => {
     setLoading(true);
     try {
       const [summaryData, reportList] = await Promise.all([
         getReportSummary(),
-        getReports(),
+        getReports({ page, page_size: pageSize }),
       ]);
       setSummary(summaryData);
-      setReports(reportList);
+      setReports(reportList.data);
+      setTotal(reportList.total);
     } finally {
       setLoading(false);
     }
@@ -96,6 +103,7 @@ export default function ReportPage() {
         ) : (
     <ReportTable reports={reports} onEdit={handleEdit} />
   )}
+  <Pagination page={page} pageSize={pageSize} total={total} onChange={setPage} disabled={loading} />
 </div>
 
 
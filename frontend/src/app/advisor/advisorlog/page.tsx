@@ -3,20 +3,16 @@
 import { useEffect, useMemo, useState } from "react";
 import { useRouter, useSearchParams } from "next/navigation";
 
-import { apiClient } from "@/src/services/http/apiclient";
-import { ListAdvisorLogs, ListAdvisorLogsByStudent } from "@/src/services/http/advisorlog";
+import { listAppointmentsDone, type AppointmentListRow } from "@/src/services/http/approvalservice";
+import { DEFAULT_PAGE_SIZE } from "@/src/services/http/paging";
+import { Pagination } from "@/src/components/Pagination/Pagination";
 
 type LogStatus = "Draft" | "PendingReport" | "Completed";
 type BadgeTone = "warn" | "gray" | "success";
 
 type AdvisorLogNorm = {
   id: number;
-  appointmentId: number;
   status: LogStatus;
-  requiresReport: boolean;
-  fileName?: string;
-  filePath?: string;
-  createdAt?: string;
 };
 
 type AppointmentNorm = {
@@ -41,19 +37,6 @@ type Card = {
 /* =========================
    Small utils
 ========================= */
-const toNum = (v: any) => {
-  const n = Number(v);
-  return Number.isFinite(n) ? n : 0;
-};
-
-const pick = <T,>(obj: any, keys: string[], fallback: T): T => {
-  for (const k of keys) {
-    const v = obj?.[k];
-    if (v !== undefined && v !== null) return v as T;
-  }
-  return fallback;
-};
-
 function formatDateMaybe(s?: string) {
   if (!s) return "";
   const d = new Date(s);
@@ -68,133 +51,25 @@ function badgeStyle(tone: BadgeTone) {
 }
 
 /* =========================
-   JWT helper (get user_id)
-   - ใช้เพื่อส่ง advisor_id ให้ endpoint /api/appointments/done
+   Normalizer
+   - แถวนัดที่เสร็จแล้วมีสถานะบันทึกมาด้วย (advisorLogId/advisorLogStatus)
 ========================= */
-function getAdvisorIdFromToken(): number | null {
-  if (typeof window === "undefined") return null;
-
-  const token =
-    window.localStorage.getItem("token") ||
-    window.localStorage.getItem("access_token") ||
-    window.localStorage.getItem("accessToken") ||
-    "";
-
-  if (!token) return null;
-
-  try {
-    const payload = JSON.parse(atob(token.split(".")[1]));
-    const id = Number(payload?.user_id);
-    return Number.isFinite(id) && id > 0 ? id : null;
-  } catch {
-    return null;
-  }
-}
-
-/* =========================
-   Normalizers
-========================= */
-function normalizeLog(raw: any): AdvisorLogNorm {
-  const id = toNum(pick(raw, ["ID", "id"], 0));
-  const appointmentId = toNum(pick(raw, ["AppointmentID", "appointmentId", "appointment_id"], 0));
-  const status = pick<string>(raw, ["Status", "status"], "Draft") as LogStatus;
-
-  const requiresReport = Boolean(
-    pick(raw, ["RequiresReport", "requiresReport", "requires_report"], false)
-  );
-
-  const fileName = pick<string>(raw, ["FileName", "fileName", "file_name"], "");
-  const filePath = pick<string>(raw, ["FilePath", "filePath", "file_path"], "");
-  const createdAt = pick<string>(raw, ["CreatedAt", "createdAt", "created_at"], "");
-
+function normalizeAppointment(row: AppointmentListRow): AppointmentNorm {
   return {
-    id,
-    appointmentId,
-    status,
-    requiresReport,
-    fileName: fileName || undefined,
-    filePath: filePath || undefined,
-    createdAt: createdAt || undefined,
+    id: row.id,
+    topic: row.topic !== "-" ? row.topic : undefined,
+    startAt: row.startAt,
+    endAt: row.endAt,
+    student: {
+      sutId: row.sutId !== "-" ? row.sutId : undefined,
+      fullName: row.studentName !== "-" ? row.studentName : undefined,
+    },
   };
 }
 
-function normalizeAppointment(raw: any): AppointmentNorm {
-  const id = toNum(pick(raw, ["ID", "id", "appointmentId", "appointment_id"], 0));
-
-  const topic =
-    String(
-      pick(raw, ["TopicName", "topicName", "topic"], "") ||
-        pick(raw?.Topic, ["Name", "name"], "") ||
-        pick(raw?.topic, ["name"], "")
-    ) || undefined;
-
-  const startAt =
-    String(pick(raw, ["StartAt", "startAt", "start_at", "appointment_time"], "")) || undefined;
-
-  const endAt =
-    String(pick(raw, ["EndAt", "endAt", "end_at"], "")) || undefined;
-
-  const sutId =
-    String(
-      pick(raw, ["sutId", "sut_id", "StudentSutID", "studentSutId"], "") ||
-        pick(raw?.StudentUser, ["SutID", "sutId", "sut_id"], "") ||
-        pick(raw?.student, ["sutId", "sut_id"], "")
-    ) || undefined;
-
-  const fullName =
-    String(
-      pick(raw, ["studentName", "StudentName", "student_full_name", "fullName"], "") ||
-        pick(raw?.StudentUser, ["FullName", "fullName", "Name", "name"], "") ||
-        pick(raw?.student, ["fullName", "name"], "")
-    ) || undefined;
-
-  return {
-    id,
-    topic,
-    startAt,
-    endAt,
-    student: { sutId, fullName },
-  };
-}
-
-/* =========================
-   Fetch appointments (DONE)
-   - ใช้ /api/appointments/done เป็นหลัก (ตาม network ของคุณ)
-   - ส่ง advisor_id ถ้ามี
-========================= */
-async function fetchDoneAppointments(studentId?: string) {
-  const advisorId = getAdvisorIdFromToken();
-
-  const candidates = [
-    "/api/appointments/done",
-    "/api/advisor/me/appointments/done",
-    "/api/advisor/me/appointments", // เผื่อไม่มี done แยก
-    "/api/appointments",
-  ];
-
-  const params: Record<string, any> = {};
-  if (advisorId) params.advisor_id = advisorId;
-  if (studentId) params.studentId = studentId;
-
-  let lastErr: any = null;
-
-  for (const url of candidates) {
-    try {
-      const resp = await apiClient.get(url, { params });
-      const payload = resp.data?.data ?? resp.data ?? [];
-      if (Array.isArray(payload)) return payload;
-      return payload ? [payload] : [];
-    } catch (e: any) {
-      lastErr = e;
-      // ลองตัวถัดไป
-    }
-  }
-
-  const msg =
-    lastErr?.response?.data?.error ||
-    lastErr?.message ||
-    "ไม่พบ endpoint สำหรับดึงนัดหมายที่เสร็จสิ้น";
-  throw new Error(msg);
+function normalizeLog(row: AppointmentListRow): AdvisorLogNorm | null {
+  if (!row.advisorLogId) return null;
+  return { id: row.advisorLogId, status: (row.advisorLogStatus || "Draft") as LogStatus };
 }
 
 /* =========================
@@ -210,11 +85,21 @@ export default function AdvisorLogPage() {
   const [loading, setLoading] = useState(true);
   const [error, setError] = useState<string | null>(null);
 
+  // backend แบ่งหน้า: ขอนัดที่เสร็จแล้วทีละหน้า (ล่าสุดก่อน) แล้วแสดงจำนวนทั้งหมดจาก total
+  const [page, setPage] = useState(1);
+  const [total, setTotal] = useState(0);
+  const pageSize = DEFAULT_PAGE_SIZE;
+
   const totalText = useMemo(
-    () => (loading ? "..." : `${cards.length} รายการ`),
-    [loading, cards.length]
+    () => (loading ? "..." : `${total} รายการ`),
+    [loading, total]
   );
 
+  // เปลี่ยนนักศึกษาแล้วกลับไปหน้าแรก
+  useEffect(() => {
+    setPage(1);
+  }, [studentId]);
+
   useEffect(() => {
     let mounted = true;
 
@@ -223,33 +108,19 @@ export default function AdvisorLogPage() {
       setError(null);
 
       try {
-        // 1) appointments (done)
-        const apptsRaw = await fetchDoneAppointments(studentId || undefined);
-        let apptsArr = Array.isArray(apptsRaw) ? apptsRaw : (apptsRaw ? [apptsRaw] : []);
-        const apptsNorm = apptsArr.map(normalizeAppointment).filter((a) => a.id > 0);
-
-        // 2) logs
-        let logsRaw: any[] = [];
-        if (studentId) {
-          const res = await ListAdvisorLogsByStudent(studentId);
-          if (!res.success) throw new Error(res.message || "โหลดบันทึกไม่สำเร็จ");
-          logsRaw = res.data?.data ?? res.data ?? [];
-        } else {
-          const res = await ListAdvisorLogs();
-          if (!res.success) throw new Error(res.message || "โหลดบันทึกไม่สำเร็จ");
-          logsRaw = res.data?.data ?? res.data ?? [];
-        }
-        if (!Array.isArray(logsRaw)) logsRaw = logsRaw ? [logsRaw] : [];
-
-        const logByAppt = new Map<number, AdvisorLogNorm>();
-        for (const l of logsRaw) {
-          const nl = normalizeLog(l);
-          if (nl.appointmentId) logByAppt.set(nl.appointmentId, nl);
-        }
-
-        // 3) build cards
-        const built: Card[] = apptsNorm.map((appt) => {
-          const log = logByAppt.get(appt.id) ?? null;
+        // 1) appointments (done) + สถานะบันทึกของแต่ละนัด
+        const res = await listAppointmentsDone({
+          page,
+          page_size: pageSize,
+          sort: "-start_at",
+          ...(studentId ? { student_id: studentId } : {}),
+        });
+        const rows = res.data.filter((r) => r.id > 0);
+
+        // 2) build cards
+        const built: Card[] = rows.map((row) => {
+          const appt = normalizeAppointment(row);
+          const log = normalizeLog(row);
 
           // UI rules ตามแบบ
           let badgeText: Card["ui"]["badgeText"] = "รอการบันทึก";
@@ -287,15 +158,14 @@ export default function AdvisorLogPage() {
           };
         });
 
-        // sort ล่าสุดก่อน (ใช้ startAt ถ้ามี)
-        built.sort((a, b) => (b.appointment.startAt || "").localeCompare(a.appointment.startAt || ""));
-
         if (!mounted) return;
         setCards(built);
+        setTotal(res.total);
       } catch (e: any) {
         if (!mounted) return;
         setError(e?.message ?? "เกิดข้อผิดพลาด");
         setCards([]);
+        setTotal(0);
       } finally {
         if (mounted) setLoading(false);
       }
@@ -304,7 +174,7 @@ export default function AdvisorLogPage() {
     return () => {
       mounted = false;
     };
-  }, [studentId]);
+  }, [studentId, page, pageSize]);
 
   return (
   <div className="w-full min-h-screen bg-gray-50 flex justify-center py-6 px-4">
@@ -323,11 +193,6 @@ export default function AdvisorLogPage() {
       ) : error ? (
         <div className="p-4 text-sm text-orange-600 bg-orange-50 rounded mb-4">
           {error}
-          <div className="mt-2 text-xs text-orange-700">
-            ถ้า error คือ “advisor_id is required” ให้เช็คว่า token มี{" "}
-            <span className="font-mono">user_id</span> ไหม (เพราะหน้านี้ส่ง{" "}
-            <span className="font-mono">advisor_id</span> อัตโนมัติจาก token)
-          </div>
         </div>
       ) : cards.length === 0 ? (
         <div className="p-6 text-center text-gray-500">ยังไม่มีนัดหมายที่เสร็จสิ้น</div>
@@ -337,7 +202,6 @@ export default function AdvisorLogPage() {
       <div className="grid grid-cols-1 sm:grid-cols-2 lg:grid-cols-3 gap-6">
         {cards.map((c) => {
           const appt = c.appointment;
-          const log = c.advisorLog;
 
           return (
             <div
@@ -373,10 +237,6 @@ export default function AdvisorLogPage() {
                 {appt.endAt ? (
                   <p className="truncate">✅ เสร็จเมื่อ: {formatDateMaybe(appt.endAt)}</p>
                 ) : null}
-
-                {log?.fileName ? (
-                  <p className="text-xs text-[#F26522] truncate">ไฟล์: {log.fileName}</p>
-                ) : null}
               </div>
 
               {/* footer */}
@@ -401,6 +261,10 @@ export default function AdvisorLogPage() {
           );
         })}
       </div>
+
+      {!loading && !error && total > 0 && (
+        <Pagination page={page} pageSize={pageSize} total={total} onChange={setPage} />
+      )}
     </div>
   </div>
 );
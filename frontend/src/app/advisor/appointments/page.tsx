@@ -10,6 +10,8 @@ import {
   type AppointmentListRow,
   type AppointmentStatus,
 } from "@/src/services/http/approvalservice";
+import { DEFAULT_PAGE_SIZE } from "@/src/services/http/paging";
+import { Pagination } from "@/src/components/Pagination/Pagination";
 
 type ViewMode = "PENDING" | "DONE" | "ALL";
 
@@ -47,10 +49,15 @@ export default function AdvisorRequestsPage() {
   const [requests, setRequests] = useState<AppointmentListRow[]>([]);
   const [loading, setLoading] = useState<boolean>(true);
 
+  // backend แบ่งหน้า: ขอทีละหน้า แล้วแสดงจำนวนทั้งหมดจาก total
+  const [page, setPage] = useState(1);
+  const [total, setTotal] = useState(0);
+  const pageSize = DEFAULT_PAGE_SIZE;
+
   // กัน race ตอนสลับแท็บเร็ว ๆ
   const abortRef = useRef<AbortController | null>(null);
 
-  const load = async (mode: ViewMode) => {
+  const load = async (mode: ViewMode, pageNo: number) => {
     setLoading(true);
 
     // abort request เก่า (กันสลับแท็บเร็ว)
@@ -62,15 +69,19 @@ export default function AdvisorRequestsPage() {
       // NOTE: axios (apiClient) ไม่รองรับ AbortController signal แบบ fetch ตรง ๆ
       // เราใช้ abort เพื่อกัน state update หลังเปลี่ยนแท็บเป็นหลัก (เช็คด้านล่าง)
 
-      let rows: AppointmentListRow[] = [];
-      if (mode === "PENDING") rows = await listAppointmentsPending();
-      else if (mode === "DONE") rows = await listAppointmentsDone();
-      else rows = await listAppointmentsAll();
+      const params = { page: pageNo, page_size: pageSize };
+      const res =
+        mode === "PENDING"
+          ? await listAppointmentsPending(params)
+          : mode === "DONE"
+          ? await listAppointmentsDone(params)
+          : await listAppointmentsAll(params);
 
       // ถ้า request นี้ถูก abort ไปแล้ว ไม่ต้อง setState
       if (controller.signal.aborted) return;
 
-      setRequests(rows);
+      setRequests(res.data);
+      setTotal(res.total);
     } catch (e: any) {
       if (controller.signal.aborted) return;
 
@@ -79,6 +90,7 @@ export default function AdvisorRequestsPage() {
 
       if (status === 401) {
         setRequests([]);
+        setTotal(0);
         setLoading(false);
         router.push("/login");
         return;
@@ -86,6 +98,7 @@ export default function AdvisorRequestsPage() {
 
       console.error("❌ load appointments failed:", e);
       setRequests([]);
+      setTotal(0);
     } finally {
       if (!controller.signal.aborted) setLoading(false);
     }
@@ -93,17 +106,17 @@ export default function AdvisorRequestsPage() {
 
   useEffect(() => {
     if (typeof window === "undefined") return;
-    load(viewMode);
+    load(viewMode, page);
     // eslint-disable-next-line react-hooks/exhaustive-deps
-  }, [viewMode]);
+  }, [viewMode, page]);
 
   useEffect(() => {
     if (typeof window === "undefined") return;
-    const onFocus = () => load(viewMode);
+    const onFocus = () => load(viewMode, page);
     window.addEventListener("focus", onFocus);
     return () => window.removeEventListener("focus", onFocus);
     // eslint-disable-next-line react-hooks/exhaustive-deps
-  }, [viewMode]);
+  }, [viewMode, page]);
 
   const filteredRequests = useMemo(() => requests, [requests]);
 
@@ -120,7 +133,7 @@ export default function AdvisorRequestsPage() {
         <div className="flex items-baseline gap-2">
           <h2 className="text-xl font-bold text-[#E3743D]">{headerTitle}</h2>
           <span className="rounded-full bg-amber-50 px-3 py-1 text-xs font-semibold text-amber-700">
-            {filteredRequests.length} รายการ
+            {total} รายการ
           </span>
         </div>
       </div>
@@ -130,7 +143,10 @@ export default function AdvisorRequestsPage() {
         {(["PENDING", "DONE", "ALL"] as ViewMode[]).map((m) => (
           <button
             key={m}
-            onClick={() => setViewMode(m)}
+            onClick={() => {
+              setViewMode(m);
+              setPage(1);
+            }}
             className={`rounded-full px-4 py-2 text-xs font-semibold border ${
               viewMode === m
                 ? "bg-[#E3743D] text-white border-[#E3743D]"
@@ -179,7 +195,7 @@ export default function AdvisorRequestsPage() {
                 key={req.id}
                 className="border-t border-slate-100 hover:bg-slate-50/60"
               >
-                <td className="px-4 py-3">{index + 1}</td>
+                <td className="px-4 py-3">{(page - 1) * pageSize + index + 1}</td>
 
                 <td className="px-4 py-3">
                   <div className="flex flex-col">
@@ -230,6 +246,8 @@ export default function AdvisorRequestsPage() {
           </tbody>
         </table>
       </div>
+
+      <Pagination page={page} pageSize={pageSize} total={total} onChange={setPage} disabled={loading} />
     </div>
   );
 }
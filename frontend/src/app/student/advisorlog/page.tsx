@@ -3,6 +3,8 @@
 import { useEffect, useMemo, useState } from "react";
 import { useRouter } from "next/navigation";
 import { ListAdvisorLogsByStudent } from "@/src/services/http/advisorlog";
+import { DEFAULT_PAGE_SIZE } from "@/src/services/http/paging";
+import { Pagination } from "@/src/components/Pagination/Pagination";
 
 // Type Definition
 type LogStatus = "Draft" | "PendingReport" | "Completed" | string;
@@ -74,9 +76,14 @@ export default function StudentAdvisorLogPage() {
   const [loading, setLoading] = useState(true);
   const [error, setError] = useState<string | null>(null);
 
+  // backend แบ่งหน้า (ใหม่สุดก่อน): ขอทีละหน้า แล้วแสดงจำนวนทั้งหมดจาก total
+  const [page, setPage] = useState(1);
+  const [total, setTotal] = useState(0);
+  const pageSize = DEFAULT_PAGE_SIZE;
+
   const totalText = useMemo(
-    () => (loading ? "..." : `${logs.length} รายการ`),
-    [loading, logs.length]
+    () => (loading ? "..." : `${total} รายการ`),
+    [loading, total]
   );
 
   useEffect(() => {
@@ -104,11 +111,10 @@ export default function StudentAdvisorLogPage() {
         }
 
         // 3. Fetch Logs
-        const res = await ListAdvisorLogsByStudent(userId);
+        const res = await ListAdvisorLogsByStudent(userId, { page, page_size: pageSize });
         if (!res.success) throw new Error(res.message || "โหลดบันทึกไม่สำเร็จ");
 
-        let arr: any[] = res.data?.data ?? res.data ?? [];
-        if (!Array.isArray(arr)) arr = arr ? [arr] : [];
+        const arr: any[] = res.data.data;
 
         // 4. Map Data
         const mapped: AdvisorLogItem[] = arr.map((it: any) => ({
@@ -124,10 +130,10 @@ export default function StudentAdvisorLogPage() {
           updatedAt: pick(it, ["UpdatedAt", "updatedAt"], "") || undefined,
         })).filter((x) => x.id > 0 && x.status !== "Draft"); // กรอง Draft ออก (เผื่อ Backend หลุดมา)
 
-        // Sort: ใหม่สุดขึ้นก่อน
-        mapped.sort((a, b) => (b.createdAt || "").localeCompare(a.createdAt || ""));
-
-        if (mounted) setLogs(mapped);
+        if (mounted) {
+          setLogs(mapped);
+          setTotal(res.data.total);
+        }
 
       } catch (e: any) {
         if (mounted) {
@@ -138,6 +144,7 @@ export default function StudentAdvisorLogPage() {
             } else {
                 setError(e?.message ?? "เกิดข้อผิดพลาดในการเชื่อมต่อ");
                 setLogs([]);
+                setTotal(0);
             }
         }
       } finally {
@@ -148,7 +155,7 @@ export default function StudentAdvisorLogPage() {
     return () => {
       mounted = false;
     };
-  }, [router]);
+  }, [router, page, pageSize]);
 
   // UI Helpers
   const badgeClass = (st?: string) => {
@@ -260,6 +267,10 @@ export default function StudentAdvisorLogPage() {
             })}
           </div>
         )}
+
+        {!loading && !error && total > 0 && (
+          <Pagination page={page} pageSize={pageSize} total={total} onChange={setPage} />
+        )}
       </div>
     </div>
   );
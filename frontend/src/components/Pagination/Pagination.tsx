@@ -0,0 +1,37 @@
+// src/components/Pagination/Pagination.tsx
+type PaginationProps = {
+  page: number;
+  pageSize: number;
+  total: number;
+  onChange: (page: number) => void;
+  disabled?: boolean;
+};
+
+// ปุ่มก่อนหน้า/ถัดไปของหน้ารายการที่ backend แบ่งหน้า (total มาจากซอง { data, total, page, page_size })
+export function Pagination({ page, pageSize, total, onChange, disabled = false }: PaginationProps) {
+  const totalPages = Math.max(1, Math.ceil(total / Math.max(1, pageSize)));
+
+  return (
+    <div className="flex items-center justify-between flex-shrink-0 mt-4 px-4 py-3">
+      <div className="text-sm text-gray-700">
+        หน้า {page} จาก {totalPages} ({total} รายการ)
+      </div>
+      <div className="flex items-center gap-2">
+        <button
+          disabled={disabled || page <= 1}
+          onClick={() => onChange(Math.max(1, page - 1))}
+          className="px-3 py-1 rounded-md border disabled:opacity-50 text-gray-700 bg-white hover:bg-gray-50"
+        >
+          ก่อนหน้า
+        </button>
+        <button
+          disabled={disabled || page >= totalPages}
+          onClick={() => onChange(Math.min(totalPages, page + 1))}
+          className="px-3 py-1 rounded-md border disabled:opacity-50 text-gray-700 bg-white hover:bg-gray-50"
+        >
+          ถัดไป
+        </button>
+      </div>
+    </div>
+  );
+}
@@ -17,8 +17,10 @@ export interface ManagedUserEntry {
 
 // AdminManagedUsersResponse (อิงตาม Go Backend DTO)
 export interface AdminManagedUsersResponse {
-    totalUsers: number;
-    users: ManagedUserEntry[];
+    data: ManagedUserEntry[];
+    total: number;
+    page: number;
+    page_size: number;
 }
 
 // UserFilters (ใช้สำหรับส่ง Query Params ใน Request List)
@@ -27,6 +29,9 @@ export interface UserFilters {
     status?: string;
     major?: string;
     search?: string; // สำหรับค้นหา (SutID, Name, Email)
+    page?: string;
+    page_size?: string;
+    sort?: string;    // เช่น sut_id, -created_at
 }
 
 
@@ -1,4 +1,5 @@
 import { apiClient } from "./apiclient";
+import { DEFAULT_PAGE_SIZE } from "./paging";
 import type {
   AdminManagedUsersResponse,
   AdminUserDetailResponse,
@@ -13,7 +14,7 @@ const USERS_API_URL = "/api/admin/users";
 // const BASE_BACKEND_URL = "http://localhost:8080"; 
 
 /**
- ดึงรายการผู้ใช้งานทั้งหมด (GET /api/admin/users)
+ ดึงรายการผู้ใช้งานทีละหน้า (GET /api/admin/users)
  * @param filters ตัวกรองสำหรับส่งเป็น Query Parameters
  * @returns Promise<AdminManagedUsersResponse>
  */
@@ -29,11 +30,14 @@ export async function getManagedUsers(
     }
   });
   
-  const queryString = params.toString();
-  
+  // ขอทีละหน้า (ผู้เรียกส่ง page/page_size/search มาเอง)
+  if (!params.has("page_size")) {
+    params.append("page_size", String(DEFAULT_PAGE_SIZE));
+  }
+
   try {
-    const res = await apiClient.get(`${USERS_API_URL}?${queryString}`);
-    // Go Controller (GetManagedUsers) ส่ง DTO ตรงๆ (AdminManagedUsersResponse)
+    const res = await apiClient.get(`${USERS_API_URL}?${params.toString()}`);
+    // Go Controller (GetManagedUsers) ส่งซองแบ่งหน้า { data, total, page, page_size }
     return res.data as AdminManagedUsersResponse;
   } catch (err: any) {
     const errorMessage = err.response?.data?.error || "โหลดรายการผู้ใช้งานล้มเหลว";
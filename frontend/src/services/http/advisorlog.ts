@@ -1,4 +1,5 @@
 import { apiClient } from "./apiclient";
+import { getPage, type PageEnvelope, type PageParams } from "./paging";
 import { AdvisorLogInterface } from "@/src/interfaces/advisorlog";
 import { AxiosProgressEvent } from "axios";
 
@@ -14,6 +15,14 @@ type UploadOptions = {
   onUploadProgress?: (percent: number) => void;
 };
 
+/* ---------- List Params (ดู advisorlog.ListQuery ฝั่ง backend) ---------- */
+export type AdvisorLogListParams = PageParams & {
+  status?: string;
+  from?: string; // YYYY-MM-DD
+  to?: string;
+  topic_id?: number;
+};
+
 /* ---------- Base URL ---------- */
 const BASE_URL = "/api/advisor_logs";
 
@@ -80,15 +89,16 @@ export async function GetAdvisorLogByID(id: number | string): Promise<ApiResult>
 }
 
 /* =========================================================
-   LIST Logs by Student
+   LIST Logs by Student (ทีละหน้า, data = ซอง { data, total, page, page_size })
    GET /api/advisor_logs/student/:studentId
 ========================================================= */
 export async function ListAdvisorLogsByStudent(
-  studentId: number | string
-): Promise<ApiResult> {
+  studentId: number | string,
+  params: AdvisorLogListParams = {}
+): Promise<ApiResult<PageEnvelope<any>>> {
   try {
-    const resp = await apiClient.get(`${BASE_URL}/student/${studentId}`);
-    return { success: true, data: resp.data };
+    const page = await getPage(`${BASE_URL}/student/${studentId}`, params);
+    return { success: true, data: page };
   } catch (err: any) {
     return {
       success: false,
@@ -99,13 +109,15 @@ export async function ListAdvisorLogsByStudent(
 }
 
 /* =========================================================
-   LIST Logs (Advisor sees all his logs)
+   LIST Logs (Advisor sees all his logs, ทีละหน้า, data = ซอง { data, total, page, page_size })
    GET /api/advisor_logs
 ========================================================= */
-export async function ListAdvisorLogs(): Promise<ApiResult> {
+export async function ListAdvisorLogs(
+  params: AdvisorLogListParams = {}
+): Promise<ApiResult<PageEnvelope<any>>> {
   try {
-    const resp = await apiClient.get(BASE_URL);
-    return { success: true, data: resp.data };
+    const page = await getPage(BASE_URL, params);
+    return { success: true, data: page };
   } catch (err: any) {
     return {
       success: false,
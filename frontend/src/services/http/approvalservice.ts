@@ -1,5 +1,6 @@
 // src/services/http/advisorservice.ts
 import { apiClient } from "./apiclient";
+import { getPage, type PageEnvelope, type PageParams } from "./paging";
 import type { AdvisorProfileResponse } from "@/src/interfaces/advisorprofile";
 
 // ==============================
@@ -38,6 +39,18 @@ export type AppointmentListRow = {
   topic: string;
   submittedAt: string;
   status: AppointmentStatus;
+  startAt?: string;
+  endAt?: string;
+  // บันทึกการปรึกษาของนัด (ไม่มี = ยังไม่บันทึก)
+  advisorLogId?: number;
+  advisorLogStatus?: string;
+};
+
+// ตัวกรองของรายการนัด (ดู repository.AppointmentListQuery ฝั่ง backend)
+export type AppointmentListParams = PageParams & {
+  status?: string;
+  topic_id?: number;
+  student_id?: number | string;
 };
 
 export type AppointmentDetail = {
@@ -70,106 +83,87 @@ const formatThaiDate = (raw: unknown) => {
 // Appointment List APIs (pending / done / all)
 // ==============================
 
-/**
- * GET /api/appointments/pending
- */
-export async function listAppointmentsPending(): Promise<AppointmentListRow[]> {
+// แถวที่ backend ส่งมาในซองแบ่งหน้า (mapToListRows)
+type AppointmentListRaw = {
+  id: number;
+  studentName: string;
+  sutId: string;
+  topic: string;
+  submittedAt: string;
+  status: string; // PENDING | APPROVED | RESCHEDULE
+  startAt?: string | null;
+  endAt?: string | null;
+  advisorLogId?: number;
+  advisorLogStatus?: string;
+};
+
+// ขอทีละหน้า (page/page_size/sort/ตัวกรองส่งต่อไปให้ backend) แล้วแปลงแถวเป็นรูปแบบของ UI
+async function listAppointmentsPage(
+  url: string,
+  params: AppointmentListParams,
+  fallbackMessage: string
+): Promise<PageEnvelope<AppointmentListRow>> {
   try {
-    const res = await apiClient.get("/api/appointments/pending");
-    const data = (res.data || []) as Array<{
-      id: number;
-      studentName: string;
-      sutId: string;
-      topic: string;
-      submittedAt: string;
-      status: string; // PENDING | APPROVED | RESCHEDULE
-    }>;
+    const res = await getPage<AppointmentListRaw>(url, params);
 
-    return data.map((x) => ({
-      id: Number(x.id),
-      studentName: x.studentName || "-",
-      sutId: x.sutId || "-",
-      topic: x.topic || "-",
-      submittedAt: x.submittedAt || "-",
-      status: normalizeAppointmentStatus(x.status),
-    }));
+    return {
+      ...res,
+      data: res.data.map((x) => ({
+        id: Number(x.id),
+        studentName: x.studentName || "-",
+        sutId: x.sutId || "-",
+        topic: x.topic || "-",
+        submittedAt: x.submittedAt || "-",
+        status: normalizeAppointmentStatus(x.status),
+        startAt: x.startAt || undefined,
+        endAt: x.endAt || undefined,
+        advisorLogId: x.advisorLogId ? Number(x.advisorLogId) : undefined,
+        advisorLogStatus: x.advisorLogStatus || undefined,
+      })),
+    };
   } catch (err: any) {
-    throw new Error(
-      err.response?.data?.message || "โหลดรายการคำขอที่รอพิจารณาล้มเหลว"
-    );
+    throw new Error(err.response?.data?.error || err.response?.data?.message || fallbackMessage);
   }
 }
 
 /**
- * GET /api/appointments/done (APPROVED + RESCHEDULE)
+ * GET /api/appointments/pending
  */
-export async function listAppointmentsDone(): Promise<AppointmentListRow[]> {
-  try {
-    const res = await apiClient.get("/api/appointments/done");
-    const data = (res.data || []) as Array<{
-      id: number;
-      studentName: string;
-      sutId: string;
-      topic: string;
-      submittedAt: string;
-      status: string;
-    }>;
+export function listAppointmentsPending(
+  params: AppointmentListParams = {}
+): Promise<PageEnvelope<AppointmentListRow>> {
+  return listAppointmentsPage("/api/appointments/pending", params, "โหลดรายการคำขอที่รอพิจารณาล้มเหลว");
+}
 
-    return data.map((x) => ({
-      id: Number(x.id),
-      studentName: x.studentName || "-",
-      sutId: x.sutId || "-",
-      topic: x.topic || "-",
-      submittedAt: x.submittedAt || "-",
-      status: normalizeAppointmentStatus(x.status),
-    }));
-  } catch (err: any) {
-    throw new Error(
-      err.response?.data?.message || "โหลดรายการคำขอที่พิจารณาแล้วล้มเหลว"
-    );
-  }
+/**
+ * GET /api/appointments/done (APPROVED + RESCHEDULE)
+ */
+export function listAppointmentsDone(
+  params: AppointmentListParams = {}
+): Promise<PageEnvelope<AppointmentListRow>> {
+  return listAppointmentsPage("/api/appointments/done", params, "โหลดรายการคำขอที่พิจารณาแล้วล้มเหลว");
 }
 
 /**
  * GET /api/appointments (all)
  */
-export async function listAppointmentsAll(): Promise<AppointmentListRow[]> {
-  try {
-    const res = await apiClient.get("/api/appointments");
-    const data = (res.data || []) as Array<{
-      id: number;
-      studentName: string;
-      sutId: string;
-      topic: string;
-      submittedAt: string;
-      status: string;
-    }>;
-
-    return data.map((x) => ({
-      id: Number(x.id),
-      studentName: x.studentName || "-",
-      sutId: x.sutId || "-",
-      topic: x.topic || "-",
-      submittedAt: x.submittedAt || "-",
-      status: normalizeAppointmentStatus(x.status),
-    }));
-  } catch (err: any) {
-    throw new Error(
-      err.response?.data?.message || "โหลดรายการคำขอทั้งหมดล้มเหลว"
-    );
-  }
+export function listAppointmentsAll(
+  params: AppointmentListParams = {}
+): Promise<PageEnvelope<AppointmentListRow>> {
+  return listAppointmentsPage("/api/appointments", params, "โหลดรายการคำขอทั้งหมดล้มเหลว");
 }
 
 /**
- * helper รวม: เรียกตามแท็บ (PENDING/DONE/ALL)
+ * helper รวม: เรียกตามแท็บ (PENDING/DONE/ALL) ทีละหน้า
  * ใช้ในหน้า app/advisor/appointments/page.tsx ได้เลย
  */
 export async function fetchAdvisorAppointments(
-  mode: ViewMode
-): Promise<AppointmentListRow[]> {
-  if (mode === "PENDING") return listAppointmentsPending();
-  if (mode === "DONE") return listAppointmentsDone();
-  return listAppointmentsAll();
+  mode: ViewMode,
+  params: AppointmentListParams = {}
+): Promise<PageEnvelope<AppointmentListRow>> {
+  if (mode === "PENDING") return listAppointmentsPending(params);
+  if (mode === "DONE") return listAppointmentsDone(params);
+  return listAppointmentsAll(params);
 }
 
 // ==============================
@@ -0,0 +1,50 @@
+import { apiClient } from "./apiclient";
+
+// ซองแบ่งหน้ามาตรฐานของ list endpoint ฝั่ง backend
+export interface PageEnvelope<T> {
+  data: T[];
+  total: number;
+  page: number;
+  page_size: number;
+  next_cursor?: string | null;
+}
+
+// พารามิเตอร์หน้า/เรียงลำดับที่ list endpoint รับ (sort เช่น "-created_at")
+export type PageParams = {
+  page?: number;
+  page_size?: number;
+  sort?: string;
+};
+
+// จำนวนแถวต่อหน้าของหน้ารายการ (backend ยอมสูงสุด 100 ตาม queryspec.MaxPageSize)
+export const DEFAULT_PAGE_SIZE = 20;
+
+/**
+ * แปลง response ของ list endpoint เป็นซองแบ่งหน้า
+ * ถ้า endpoint ยังตอบเป็น array ตรงๆ (ไม่แบ่งหน้า) ถือว่าเป็นหน้าเดียว
+ */
+export function toPage<T>(body: any, params: PageParams = {}): PageEnvelope<T> {
+  if (Array.isArray(body)) {
+    return { data: body, total: body.length, page: 1, page_size: body.length };
+  }
+  const data: T[] = Array.isArray(body?.data) ? body.data : [];
+  return {
+    data,
+    total: Number(body?.total ?? data.length),
+    page: Number(body?.page ?? params.page ?? 1),
+    page_size: Number(body?.page_size ?? params.page_size ?? DEFAULT_PAGE_SIZE),
+    next_cursor: body?.next_cursor ?? null,
+  };
+}
+
+/**
+ * GET หน้าเดียวผ่าน apiClient (params อื่นเช่นตัวกรองส่งต่อไปตามเดิม)
+ */
+export async function getPage<T>(
+  url: string,
+  params: PageParams & Record<string, any> = {}
+): Promise<PageEnvelope<T>> {
+  const query = { page_size: DEFAULT_PAGE_SIZE, ...params };
+  const res = await apiClient.get(url, { params: query });
+  return toPage<T>(res.data, query);
+}
@@ -1,3 +1,5 @@
+import { DEFAULT_PAGE_SIZE, toPage, type PageEnvelope, type PageParams } from "./paging";
+
 export async function getReportSummary() {
   const res = await fetch("http://localhost:8080/report/summary", {
     cache: "no-store",
@@ -5,11 +7,17 @@ export async function getReportSummary() {
   return res.json();
 }
 
-export async function getReports() {
-  const res = await fetch("http://localhost:8080/reports", {
+// ทีละหน้า: backend แบ่งหน้า { data, total, page, page_size } (sort เช่น "-created_at")
+export async function getReports(params: PageParams = {}): Promise<PageEnvelope<any>> {
+  const query = new URLSearchParams();
+  query.set("page", String(params.page ?? 1));
+  query.set("page_size", String(params.page_size ?? DEFAULT_PAGE_SIZE));
+  if (params.sort) query.set("sort", params.sort);
+
+  const res = await fetch(`http://localhost:8080/reports?${query.toString()}`, {
     cache: "no-store",
   });
-  return res.json();
+  return toPage<any>(await res.json(), params);
 }
 
 export async function getReportById(id: number) {